@@ -12,8 +12,11 @@ import (
 	"github.com/fatih/color"
 	"github.com/hashicorp/logutils"
 	flags "github.com/jessevdk/go-flags"
+	"github.com/spf13/afero"
+	"github.com/terraform-linters/tflint-plugin-sdk/hclext"
 	"github.com/terraform-linters/tflint/formatter"
 	"github.com/terraform-linters/tflint/terraform"
+	"github.com/terraform-linters/tflint/terraform/addrs"
 	"github.com/terraform-linters/tflint/tflint"
 )
 
@@ -22,6 +25,9 @@ const (
 	ExitCodeOK int = iota
 	ExitCodeError
 	ExitCodeIssuesFound
+	// ExitCodePartialFailure means that errors occurred in some directories with --keep-going,
+	// or in some plugins with --keep-going-plugins, but others were inspected.
+	ExitCodePartialFailure
 )
 
 // CLI is the command line object
@@ -31,11 +37,22 @@ type CLI struct {
 	outStream, errStream io.Writer
 	originalWorkingDir   string
 	sources              map[string][]byte
+	// unevaluated is a list of expressions that rules could not check.
+	// This is collected only with --explain-unknowns.
+	unevaluated []*tflint.UnevaluatedExpr
+	// pluginErrs is a list of errors in ruleset plugins.
+	// This is collected only with --keep-going-plugins.
+	pluginErrs tflint.PluginErrors
+	// profiler records timings with --profile or --trace-file. It is nil otherwise.
+	profiler *tflint.Profiler
 
 	// fields for each module
 	config    *tflint.Config
 	loader    *terraform.Loader
 	formatter *formatter.Formatter
+	// failedPlugins is a set of plugins that failed with --keep-going-plugins.
+	// Plugins are launched for each module, so the failure does not affect other modules.
+	failedPlugins map[string]bool
 }
 
 // NewCLI returns new CLI initialized by input streams
@@ -93,15 +110,28 @@ func (cli *CLI) Run(args []string) int {
 		return ExitCodeError
 	}
 
+	if opts.Yes && !opts.PluginsPrune {
+		cli.formatter.Print(tflint.Issues{}, errors.New("--yes can only be used with --plugins-prune"), map[string][]byte{})
+		return ExitCodeError
+	}
+
 	switch {
 	case opts.Version:
 		return cli.printVersion(opts)
 	case opts.Init:
 		return cli.init(opts)
+	case opts.MirrorPlugins != "":
+		return cli.mirrorPlugins(opts)
+	case opts.PluginsList:
+		return cli.listPlugins(opts)
+	case opts.PluginsPrune:
+		return cli.prunePlugins(opts)
 	case opts.Langserver:
 		return cli.startLanguageServer(opts)
 	case opts.ActAsBundledPlugin:
 		return cli.actAsBundledPlugin()
+	case opts.ActAsPluginLauncher:
+		return cli.actAsPluginLauncher()
 	default:
 		return cli.inspect(opts)
 	}
@@ -137,11 +167,27 @@ func findWorkingDirs(opts Options) ([]string, error) {
 		return []string{}, errors.New("cannot use --recursive and --chdir at the same time")
 	}
 
+	if !opts.Recursive {
+		switch {
+		case len(opts.Excludes) > 0:
+			return []string{}, errors.New("--exclude can only be used with --recursive")
+		case opts.Gitignore:
+			return []string{}, errors.New("--gitignore can only be used with --recursive")
+		case opts.RootModulesOnly:
+			return []string{}, errors.New("--root-modules-only can only be used with --recursive")
+		}
+	}
+
 	workingDirs := []string{}
 
 	if opts.Recursive {
+		ignorer, err := newDirIgnorer(opts)
+		if err != nil {
+			return []string{}, err
+		}
+
 		// NOTE: The target directory is always the current directory in recursive mode
-		err := filepath.WalkDir(".", func(path string, d os.DirEntry, err error) error {
+		err = filepath.WalkDir(".", func(path string, d os.DirEntry, err error) error {
 			if err != nil {
 				return err
 			}
@@ -152,6 +198,17 @@ func findWorkingDirs(opts Options) ([]string, error) {
 			if path != "." && strings.HasPrefix(d.Name(), ".") {
 				return filepath.SkipDir
 			}
+			ignored, err := ignorer.ignored(path)
+			if err != nil {
+				return fmt.Errorf("Failed to match ignore patterns; %w", err)
+			}
+			if ignored {
+				log.Printf("[DEBUG] Skip ignored directory: %s", path)
+				return filepath.SkipDir
+			}
+			if err := ignorer.load(path); err != nil {
+				return fmt.Errorf("Failed to load ignore files; %w", err)
+			}
 
 			workingDirs = append(workingDirs, path)
 			return nil
@@ -159,6 +216,10 @@ func findWorkingDirs(opts Options) ([]string, error) {
 		if err != nil {
 			return []string{}, err
 		}
+
+		if opts.RootModulesOnly {
+			workingDirs = filterRootModules(workingDirs, opts)
+		}
 	} else {
 		if opts.Chdir == "" {
 			workingDirs = []string{"."}
@@ -170,6 +231,125 @@ func findWorkingDirs(opts Options) ([]string, error) {
 	return workingDirs, nil
 }
 
+// filterRootModules removes directories that are called as local modules from other directories.
+// Issues in these modules are reported by their callers, so inspecting them again is redundant.
+//
+// Directories with root module markers (a backend/cloud block or a .terraform directory)
+// are always kept, since they are also used as root modules. A directory is never removed
+// because of its own module calls.
+//
+// Calls are only taken into account if the caller inspects local modules, that is,
+// call_module_type is not "none", since otherwise no one reports issues in the modules.
+//
+// Module calls are found statically, so directories that cannot be loaded are kept
+// to report errors during the inspection.
+func filterRootModules(dirs []string, opts Options) []string {
+	parser := terraform.NewParser(afero.NewOsFs())
+
+	children := map[string]bool{}
+	roots := map[string]bool{}
+	for _, dir := range dirs {
+		mod, diags := parser.LoadConfigDir(".", dir)
+		if diags.HasErrors() {
+			log.Printf("[DEBUG] Failed to load %s to find module calls: %s", dir, diags)
+			continue
+		}
+		if hasRootModuleMarkers(dir, mod) {
+			roots[filepath.Clean(dir)] = true
+		}
+
+		if callModuleTypeIn(dir, opts) == terraform.CallNoModule {
+			log.Printf("[DEBUG] Module calls in %s are not taken into account because modules are not called", dir)
+			continue
+		}
+		for _, call := range mod.ModuleCalls {
+			if source, ok := call.SourceAddr.(addrs.ModuleSourceLocal); ok {
+				child := filepath.Join(dir, source.String())
+				if child == filepath.Clean(dir) {
+					continue
+				}
+				children[child] = true
+			}
+		}
+	}
+
+	ret := []string{}
+	for _, dir := range dirs {
+		if children[filepath.Clean(dir)] && !roots[filepath.Clean(dir)] {
+			log.Printf("[DEBUG] Skip directory only used as a local module: %s", dir)
+			continue
+		}
+		ret = append(ret, dir)
+	}
+	return ret
+}
+
+// callModuleTypeIn returns the type of modules called when inspecting the directory.
+// The flag takes precedence over the config file loaded in the directory, as in the inspection.
+// If the config cannot be loaded, modules are treated as not called, since the inspection fails.
+func callModuleTypeIn(dir string, opts Options) terraform.CallModuleType {
+	if opts.CallModuleType != nil {
+		if callModuleType, err := terraform.AsCallModuleType(*opts.CallModuleType); err == nil {
+			return callModuleType
+		}
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		return terraform.CallNoModule
+	}
+	if err := os.Chdir(dir); err != nil {
+		return terraform.CallNoModule
+	}
+	defer func() {
+		if err := os.Chdir(wd); err != nil {
+			log.Printf("[ERROR] Failed to switch to the original working directory; %s", err)
+		}
+	}()
+
+	config, err := tflint.LoadConfig(afero.Afero{Fs: afero.NewOsFs()}, opts.Config)
+	if err != nil {
+		log.Printf("[DEBUG] Failed to load TFLint config in %s; %s", dir, err)
+		return terraform.CallNoModule
+	}
+	return config.CallModuleType
+}
+
+var rootModuleMarkerSchema = &hclext.BodySchema{
+	Blocks: []hclext.BlockSchema{
+		{
+			Type: "terraform",
+			Body: &hclext.BodySchema{
+				Blocks: []hclext.BlockSchema{
+					{Type: "backend", LabelNames: []string{"type"}},
+					{Type: "cloud"},
+				},
+			},
+		},
+	},
+}
+
+// hasRootModuleMarkers returns whether the module in the directory is used as a root module.
+// A module is a root module if it has a backend/cloud block or is initialized by "terraform init".
+func hasRootModuleMarkers(dir string, mod *terraform.Module) bool {
+	if info, err := os.Stat(filepath.Join(dir, ".terraform")); err == nil && info.IsDir() {
+		return true
+	}
+
+	for _, file := range mod.Files {
+		content, diags := hclext.PartialContent(file.Body, rootModuleMarkerSchema)
+		if diags.HasErrors() {
+			continue
+		}
+		for _, block := range content.Blocks {
+			if len(block.Body.Blocks) > 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 func (cli *CLI) withinChangedDir(dir string, proc func() error) (err error) {
 	if dir != "." {
 		chErr := os.Chdir(dir)
@@ -0,0 +1,206 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/bmatcuk/doublestar"
+)
+
+// tflintIgnoreFileName is the name of the file that lists directories
+// to be excluded from recursive inspection, using the gitignore syntax.
+const tflintIgnoreFileName = ".tflintignore"
+
+// ignoreRule is a pattern in the gitignore syntax.
+// The pattern is relative to the directory where the rule is declared.
+type ignoreRule struct {
+	base     string
+	pattern  string
+	negate   bool
+	anchored bool
+}
+
+// parseIgnoreRule parses a line in the gitignore syntax.
+// It returns nil if the line has no pattern, such as a blank line or a comment.
+func parseIgnoreRule(base string, line string) (*ignoreRule, error) {
+	line = strings.TrimRight(line, " \t\r")
+	if line == "" || strings.HasPrefix(line, "#") {
+		return nil, nil
+	}
+
+	rule := &ignoreRule{base: base}
+
+	if strings.HasPrefix(line, "!") {
+		rule.negate = true
+		line = line[1:]
+	} else if strings.HasPrefix(line, `\!`) || strings.HasPrefix(line, `\#`) {
+		line = line[1:]
+	}
+
+	// Only directories are matched, so a trailing slash has no meaning
+	line = strings.TrimSuffix(line, "/")
+	if line == "" {
+		return nil, nil
+	}
+
+	// A pattern that contains a slash is relative to the base directory.
+	// Otherwise, it matches the name at any depth.
+	if strings.Contains(line, "/") {
+		rule.anchored = true
+		line = strings.TrimPrefix(line, "/")
+	}
+	rule.pattern = line
+
+	if err := validateIgnorePattern(rule.pattern); err != nil {
+		return nil, fmt.Errorf(`"%s" is an invalid ignore pattern; %w`, line, err)
+	}
+
+	return rule, nil
+}
+
+// validateIgnorePattern checks the syntax of the pattern in advance.
+// doublestar.Match does not report syntax errors in the rest of the pattern
+// once the name fails to match, so unclosed brackets and braces are also checked here.
+func validateIgnorePattern(pattern string) error {
+	if _, err := doublestar.Match(pattern, ""); err != nil {
+		return err
+	}
+
+	braces := 0
+	inClass := false
+	for i := 0; i < len(pattern); i++ {
+		switch pattern[i] {
+		case '\\':
+			i++
+			if i >= len(pattern) {
+				return doublestar.ErrBadPattern
+			}
+		case '[':
+			inClass = true
+		case ']':
+			inClass = false
+		case '{':
+			if !inClass {
+				braces++
+			}
+		case '}':
+			if !inClass && braces > 0 {
+				braces--
+			}
+		}
+	}
+	if inClass || braces > 0 {
+		return doublestar.ErrBadPattern
+	}
+	return nil
+}
+
+// match returns whether the passed directory matches the rule.
+// The path must be a slash-separated path relative to the current directory.
+func (r *ignoreRule) match(dir string) (bool, error) {
+	rel := dir
+	if r.base != "." {
+		if !strings.HasPrefix(dir, r.base+"/") {
+			return false, nil
+		}
+		rel = strings.TrimPrefix(dir, r.base+"/")
+	}
+
+	if !r.anchored {
+		rel = path.Base(rel)
+	}
+	matched, err := doublestar.Match(r.pattern, rel)
+	if err != nil {
+		return false, fmt.Errorf(`"%s" is an invalid ignore pattern; %w`, r.pattern, err)
+	}
+	return matched, nil
+}
+
+// dirIgnorer determines whether directories are excluded from recursive inspection.
+// Rules are evaluated in order, and the last matching rule wins.
+type dirIgnorer struct {
+	// rules are loaded from ignore files. Directories are walked in lexical order,
+	// so rules in a parent directory always precede rules in its children.
+	rules []*ignoreRule
+	// excludes are passed by --exclude. These are evaluated after ignore files.
+	excludes []*ignoreRule
+	// files is the list of ignore file names to read in each directory
+	files []string
+}
+
+func newDirIgnorer(opts Options) (*dirIgnorer, error) {
+	ignorer := &dirIgnorer{files: []string{tflintIgnoreFileName}}
+	if opts.Gitignore {
+		// .tflintignore takes precedence over .gitignore
+		ignorer.files = []string{".gitignore", tflintIgnoreFileName}
+	}
+
+	for _, exclude := range opts.Excludes {
+		rule, err := parseIgnoreRule(".", exclude)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to parse --exclude; %w", err)
+		}
+		if rule != nil {
+			ignorer.excludes = append(ignorer.excludes, rule)
+		}
+	}
+
+	return ignorer, nil
+}
+
+// load reads the ignore files in the passed directory.
+// It must be called before the children of the directory are checked.
+func (i *dirIgnorer) load(dir string) error {
+	for _, name := range i.files {
+		filename := filepath.Join(dir, name)
+		src, err := os.ReadFile(filename)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return err
+		}
+		log.Printf("[DEBUG] Load ignore file: %s", filename)
+
+		scanner := bufio.NewScanner(bytes.NewReader(src))
+		for lineno := 1; scanner.Scan(); lineno++ {
+			rule, err := parseIgnoreRule(filepath.ToSlash(dir), scanner.Text())
+			if err != nil {
+				return fmt.Errorf("%s:%d: %w", filename, lineno, err)
+			}
+			if rule != nil {
+				i.rules = append(i.rules, rule)
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ignored returns whether the passed directory is excluded.
+func (i *dirIgnorer) ignored(dir string) (bool, error) {
+	dir = filepath.ToSlash(dir)
+
+	ret := false
+	for _, rules := range [][]*ignoreRule{i.rules, i.excludes} {
+		for _, rule := range rules {
+			matched, err := rule.match(dir)
+			if err != nil {
+				return false, err
+			}
+			if matched {
+				ret = !rule.negate
+			}
+		}
+	}
+	return ret, nil
+}
@@ -0,0 +1,403 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_dirIgnorer_ignored(t *testing.T) {
+	tests := []struct {
+		name     string
+		files    map[string]string
+		opts     Options
+		dirs     map[string]bool
+		errCheck func(error) bool
+	}{
+		{
+			name: "no ignore files",
+			dirs: map[string]bool{
+				".":            false,
+				"node_modules": false,
+			},
+		},
+		{
+			name: "unanchored pattern",
+			files: map[string]string{
+				".tflintignore": "# comment\n\nnode_modules/\n",
+			},
+			dirs: map[string]bool{
+				"node_modules":         true,
+				"foo/node_modules":     true,
+				"foo/node_modules_bak": false,
+				"foo":                  false,
+			},
+		},
+		{
+			name: "anchored pattern",
+			files: map[string]string{
+				".tflintignore": "/vendor\nexamples/*\n",
+			},
+			dirs: map[string]bool{
+				"vendor":          true,
+				"foo/vendor":      false,
+				"examples":        false,
+				"examples/foo":    true,
+				"foo/examples/ab": false,
+			},
+		},
+		{
+			name: "double star",
+			files: map[string]string{
+				".tflintignore": "**/generated/**\n",
+			},
+			dirs: map[string]bool{
+				"generated":         false,
+				"generated/foo":     true,
+				"foo/generated/bar": true,
+			},
+		},
+		{
+			name: "negation",
+			files: map[string]string{
+				".tflintignore": "examples/*\n!examples/complete\n",
+			},
+			dirs: map[string]bool{
+				"examples/simple":   true,
+				"examples/complete": false,
+			},
+		},
+		{
+			name: "escaped",
+			files: map[string]string{
+				".tflintignore": "\\#foo\n\\!bar\n",
+			},
+			dirs: map[string]bool{
+				"#foo": true,
+				"!bar": true,
+			},
+		},
+		{
+			name: "nested ignore file",
+			files: map[string]string{
+				".tflintignore":     "modules\n",
+				"foo/.tflintignore": "/bar\n!modules\n",
+			},
+			dirs: map[string]bool{
+				"modules":     true,
+				"bar":         false,
+				"foo/bar":     true,
+				"foo/modules": false,
+				"baz/modules": true,
+			},
+		},
+		{
+			name: ".gitignore is not respected by default",
+			files: map[string]string{
+				".gitignore": "vendor\n",
+			},
+			dirs: map[string]bool{
+				"vendor": false,
+			},
+		},
+		{
+			name: ".gitignore is respected with --gitignore",
+			files: map[string]string{
+				".gitignore":    "vendor\nexamples\n",
+				".tflintignore": "!examples\n",
+			},
+			opts: Options{Gitignore: true},
+			dirs: map[string]bool{
+				"vendor":   true,
+				"examples": false,
+			},
+		},
+		{
+			name: "--exclude",
+			files: map[string]string{
+				".tflintignore": "!examples\n",
+			},
+			opts: Options{Excludes: []string{"examples", "test/*"}},
+			dirs: map[string]bool{
+				"examples":      true,
+				"test/fixtures": true,
+				"test":          false,
+			},
+		},
+		{
+			name: "invalid pattern",
+			files: map[string]string{
+				".tflintignore": "[\n",
+			},
+			errCheck: func(err error) bool {
+				return err == nil || err.Error() != `.tflintignore:1: "[" is an invalid ignore pattern; syntax error in pattern`
+			},
+		},
+		{
+			name: "unclosed brace",
+			files: map[string]string{
+				".tflintignore": "{foo,bar\n",
+			},
+			errCheck: func(err error) bool {
+				return err == nil || err.Error() != `.tflintignore:1: "{foo,bar" is an invalid ignore pattern; syntax error in pattern`
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for path, content := range test.files {
+				path = filepath.Join(dir, path)
+				if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			withinDir(t, dir, func() {
+				ignorer, err := newDirIgnorer(test.opts)
+				if err != nil {
+					t.Fatal(err)
+				}
+				for _, d := range []string{".", "foo"} {
+					if err := ignorer.load(d); err != nil {
+						if test.errCheck == nil || test.errCheck(err) {
+							t.Fatalf("unexpected error: %s", err)
+						}
+						return
+					}
+				}
+				if test.errCheck != nil {
+					t.Fatal("an error was expected to occur, but it did not")
+				}
+
+				got := map[string]bool{}
+				for d := range test.dirs {
+					got[d], err = ignorer.ignored(d)
+					if err != nil {
+						t.Fatal(err)
+					}
+				}
+				if diff := cmp.Diff(test.dirs, got); diff != "" {
+					t.Error(diff)
+				}
+			})
+		})
+	}
+}
+
+func Test_findWorkingDirs(t *testing.T) {
+	callModuleTypeNone := "none"
+
+	tests := []struct {
+		name  string
+		files map[string]string
+		opts  Options
+		want  []string
+		err   string
+	}{
+		{
+			name: "not recursive",
+			opts: Options{},
+			want: []string{"."},
+		},
+		{
+			name: "recursive",
+			files: map[string]string{
+				"main.tf":                 "",
+				".terraform/modules/a.tf": "",
+				"node_modules/foo/a.tf":   "",
+				"modules/foo/main.tf":     "",
+			},
+			opts: Options{Recursive: true},
+			want: []string{".", "modules", "modules/foo", "node_modules", "node_modules/foo"},
+		},
+		{
+			name: "recursive with ignore files",
+			files: map[string]string{
+				".tflintignore":         "node_modules\n",
+				".gitignore":            "vendor\n",
+				"node_modules/foo/a.tf": "",
+				"vendor/a.tf":           "",
+				"examples/simple/a.tf":  "",
+			},
+			opts: Options{Recursive: true, Gitignore: true, Excludes: []string{"examples/*"}},
+			want: []string{".", "examples"},
+		},
+		{
+			name: "recursive with --root-modules-only",
+			files: map[string]string{
+				"main.tf": `
+module "foo" {
+  source = "./modules/foo"
+}
+module "remote" {
+  source = "terraform-aws-modules/vpc/aws"
+}`,
+				"modules/foo/main.tf": `
+module "bar" {
+  source = "../bar"
+}`,
+				"modules/bar/main.tf": "",
+				"examples/main.tf": `
+module "foo" {
+  source = "../modules/foo"
+}`,
+				"broken/main.tf": `module "foo" {`,
+			},
+			opts: Options{Recursive: true, RootModulesOnly: true},
+			want: []string{".", "broken", "examples", "modules"},
+		},
+		{
+			name: "recursive with --root-modules-only and root modules called as local modules",
+			files: map[string]string{
+				"main.tf": `
+module "backend" {
+  source = "./backend"
+}
+module "cloud" {
+  source = "./cloud"
+}
+module "initialized" {
+  source = "./initialized"
+}`,
+				"backend/main.tf": `
+terraform {
+  backend "s3" {}
+}`,
+				"cloud/main.tf": `
+terraform {
+  cloud {}
+}`,
+				"initialized/main.tf":                 "",
+				"initialized/.terraform/modules.json": "",
+			},
+			opts: Options{Recursive: true, RootModulesOnly: true},
+			want: []string{".", "backend", "cloud", "initialized"},
+		},
+		{
+			name: "recursive with --root-modules-only and self-referencing module",
+			files: map[string]string{
+				"main.tf": `
+module "self" {
+  source = "./"
+}`,
+				"modules/foo/main.tf": `
+module "self" {
+  source = "./"
+}`,
+			},
+			opts: Options{Recursive: true, RootModulesOnly: true},
+			want: []string{".", "modules", "modules/foo"},
+		},
+		{
+			name: "recursive with --root-modules-only and --call-module-type=none",
+			files: map[string]string{
+				"main.tf": `
+module "foo" {
+  source = "./modules/foo"
+}`,
+				"modules/foo/main.tf": "",
+			},
+			opts: Options{Recursive: true, RootModulesOnly: true, CallModuleType: &callModuleTypeNone},
+			want: []string{".", "modules", "modules/foo"},
+		},
+		{
+			name: "recursive with --root-modules-only and call_module_type = none in config",
+			files: map[string]string{
+				".tflint.hcl": `
+config {
+  call_module_type = "none"
+}`,
+				"main.tf": `
+module "foo" {
+  source = "./modules/foo"
+}`,
+				"modules/foo/main.tf": "",
+				"examples/main.tf": `
+module "foo" {
+  source = "../modules/foo"
+}`,
+			},
+			opts: Options{Recursive: true, RootModulesOnly: true},
+			want: []string{".", "examples", "modules"},
+		},
+		{
+			name: "--exclude without --recursive",
+			opts: Options{Excludes: []string{"examples"}},
+			err:  "--exclude can only be used with --recursive",
+		},
+		{
+			name: "--gitignore without --recursive",
+			opts: Options{Gitignore: true},
+			err:  "--gitignore can only be used with --recursive",
+		},
+		{
+			name: "--root-modules-only without --recursive",
+			opts: Options{RootModulesOnly: true},
+			err:  "--root-modules-only can only be used with --recursive",
+		},
+		{
+			name: "invalid --exclude",
+			opts: Options{Recursive: true, Excludes: []string{"["}},
+			err:  `Failed to parse --exclude; "[" is an invalid ignore pattern; syntax error in pattern`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for path, content := range test.files {
+				path = filepath.Join(dir, path)
+				if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			withinDir(t, dir, func() {
+				got, err := findWorkingDirs(test.opts)
+				if err != nil {
+					if err.Error() != test.err {
+						t.Fatalf("unexpected error: %s", err)
+					}
+					return
+				}
+				if test.err != "" {
+					t.Fatal("an error was expected to occur, but it did not")
+				}
+
+				for i := range got {
+					got[i] = filepath.ToSlash(got[i])
+				}
+				if diff := cmp.Diff(test.want, got); diff != "" {
+					t.Error(diff)
+				}
+			})
+		})
+	}
+}
+
+func withinDir(t *testing.T, dir string, test func()) {
+	t.Helper()
+
+	current, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := os.Chdir(current); err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	test()
+}
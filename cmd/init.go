@@ -33,6 +33,15 @@ func (cli *CLI) init(opts Options) int {
 				return fmt.Errorf("Failed to load TFLint config; %w", err)
 			}
 
+			lockFile, err := plugin.LoadLockFile(plugin.LockFileName)
+			if err != nil {
+				return fmt.Errorf("Failed to load %s; %w", plugin.LockFileName, err)
+			}
+			lockExists := lockFile != nil
+			if !lockExists {
+				lockFile = &plugin.LockFile{}
+			}
+
 			found := false
 			for _, pluginCfg := range cfg.Plugins {
 				installCfg := plugin.NewInstallConfig(cfg, pluginCfg)
@@ -43,8 +52,37 @@ func (cli *CLI) init(opts Options) int {
 				}
 				found = true
 
-				_, err := plugin.FindPluginPath(installCfg)
-				if os.IsNotExist(err) {
+				locked := lockFile.Plugin(pluginCfg.Name)
+				if locked != nil && !locked.Matches(pluginCfg) && !opts.Upgrade {
+					return fmt.Errorf(
+						`Plugin "%s" is locked to version %s (source: %s), but version %s (source: %s) is configured. Run "tflint --init --upgrade" to update the lock file`,
+						pluginCfg.Name,
+						locked.Version,
+						locked.Source,
+						pluginCfg.Version,
+						pluginCfg.Source,
+					)
+				}
+				// With --upgrade, the lock is refreshed from the release
+				previous := locked
+				if opts.Upgrade {
+					locked = nil
+				}
+
+				// Version constraints are resolved to the locked version or the newest release
+				installCfg, err := installCfg.Resolve(locked)
+				if err != nil {
+					return fmt.Errorf("Failed to resolve version constraints; %w", err)
+				}
+
+				path, err := plugin.FindPluginPath(installCfg)
+				if err != nil && !os.IsNotExist(err) {
+					return fmt.Errorf("Failed to find a plugin; %w", err)
+				}
+
+				// Reinstall if the checksum of the installed binary is not locked.
+				// This makes sure that the binary is verified against the locked checksums of the release.
+				if os.IsNotExist(err) || locked == nil || !locked.HasBinaryChecksum() {
 					fmt.Fprintf(cli.outStream, "Installing \"%s\" plugin...\n", pluginCfg.Name)
 
 					sigchecker := plugin.NewSignatureChecker(installCfg)
@@ -52,22 +90,35 @@ func (cli *CLI) init(opts Options) int {
 						_, _ = color.New(color.FgYellow).Fprintln(cli.outStream, `No signing key configured. Set "signing_key" to verify that the release is signed by the plugin developer`)
 					}
 
+					installCfg.Lock = locked
 					_, err = installCfg.Install()
 					if err != nil {
 						return fmt.Errorf("Failed to install a plugin; %w", err)
 					}
+					lockFile.SetPlugin(installCfg.Lock)
 
-					fmt.Fprintf(cli.outStream, "Installed \"%s\" (source: %s, version: %s)\n", pluginCfg.Name, pluginCfg.Source, pluginCfg.Version)
+					if previous != nil && previous.Version != installCfg.Version {
+						fmt.Fprintf(cli.outStream, "Upgraded \"%s\" (source: %s, version: %s -> %s)\n", pluginCfg.Name, pluginCfg.Source, previous.Version, installCfg.Version)
+						continue
+					}
+					fmt.Fprintf(cli.outStream, "Installed \"%s\" (source: %s, version: %s)\n", pluginCfg.Name, pluginCfg.Source, installCfg.Version)
 					continue
 				}
 
-				if err != nil {
-					return fmt.Errorf("Failed to find a plugin; %w", err)
+				if err := locked.VerifyBinary(path); err != nil {
+					return fmt.Errorf(`Plugin "%s" does not match %s; %w. Remove %s and run "tflint --init" again`, pluginCfg.Name, plugin.LockFileName, err, path)
 				}
 
 				fmt.Fprintf(cli.outStream, "Plugin \"%s\" is already installed\n", pluginCfg.Name)
 			}
 
+			lockFile.Prune(cfg)
+			if lockExists || len(lockFile.Plugins) > 0 {
+				if err := lockFile.Write(plugin.LockFileName); err != nil {
+					return fmt.Errorf("Failed to write %s; %w", plugin.LockFileName, err)
+				}
+			}
+
 			if opts.Recursive && !found {
 				fmt.Fprint(cli.outStream, "No plugins to install\n")
 			}
@@ -1,11 +1,13 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io"
+	"log"
 	"os"
 	"path/filepath"
-	"strings"
 
 	"github.com/hashicorp/go-version"
 	"github.com/hashicorp/hcl/v2"
@@ -22,16 +24,51 @@ func (cli *CLI) inspect(opts Options) int {
 	// Respect the "--format" flag until a config is loaded
 	cli.formatter.Format = opts.Format
 
+	if opts.Archive != "" {
+		var err error
+		switch {
+		case opts.Fix:
+			err = errors.New("cannot use --fix with --archive because the archive is read-only")
+		case opts.Recursive:
+			err = errors.New("cannot use --recursive and --archive at the same time")
+		case opts.Chdir != "":
+			err = errors.New("cannot use --chdir and --archive at the same time")
+		}
+		if err != nil {
+			cli.formatter.Print(tflint.Issues{}, err, map[string][]byte{})
+			return ExitCodeError
+		}
+	}
+	if opts.KeepGoing && !opts.Recursive {
+		cli.formatter.Print(tflint.Issues{}, errors.New("--keep-going can only be used with --recursive"), map[string][]byte{})
+		return ExitCodeError
+	}
+	if opts.AllWorkspaces && opts.Workspace != nil {
+		cli.formatter.Print(tflint.Issues{}, errors.New("cannot use --workspace and --all-workspaces at the same time"), map[string][]byte{})
+		return ExitCodeError
+	}
+
 	workingDirs, err := findWorkingDirs(opts)
 	if err != nil {
 		cli.formatter.Print(tflint.Issues{}, fmt.Errorf("Failed to find workspaces; %w", err), map[string][]byte{})
 		return ExitCodeError
 	}
 
+	if opts.Profile || opts.TraceFile != "" {
+		cli.profiler = tflint.NewProfiler()
+	}
+
 	issues := tflint.Issues{}
 	changes := map[string][]byte{}
+	dirErrs := tflint.WorkingDirErrors{}
+	failedDirs := 0
 
+	profiler := cli.profiler
 	for _, wd := range workingDirs {
+		if opts.Recursive {
+			// Distinguish timings of each working directory
+			cli.profiler = profiler.WithArgs("dir", wd)
+		}
 		err := cli.withinChangedDir(wd, func() error {
 			filterFiles := []string{}
 			for _, pattern := range opts.Filter {
@@ -51,14 +88,18 @@ func (cli *CLI) inspect(opts Options) int {
 				filterFiles[i] = filepath.Join(wd, file)
 			}
 
+			pluginErrCount := len(cli.pluginErrs)
 			moduleIssues, moduleChanges, err := cli.inspectModule(opts, ".", filterFiles)
 			if err != nil {
 				if len(workingDirs) > 1 {
 					// Print the current working directory in recursive inspection
-					return fmt.Errorf("%w working_dir=%s", err, wd)
+					return &tflint.WorkingDirError{Dir: wd, Err: err}
 				}
 				return err
 			}
+			if len(workingDirs) > 1 && len(cli.pluginErrs) > pluginErrCount {
+				dirErrs = append(dirErrs, &tflint.WorkingDirError{Dir: wd, Err: cli.pluginErrs[pluginErrCount:]})
+			}
 			issues = append(issues, moduleIssues...)
 			for path, source := range moduleChanges {
 				changes[path] = source
@@ -71,10 +112,25 @@ func (cli *CLI) inspect(opts Options) int {
 			if cli.loader != nil {
 				sources = cli.loader.Sources()
 			}
+
+			var dirErr *tflint.WorkingDirError
+			if opts.KeepGoing && errors.As(err, &dirErr) {
+				log.Printf("[ERROR] %s", err)
+				dirErrs = append(dirErrs, dirErr)
+				failedDirs++
+				// Keep the sources to print diagnostics later
+				for path, source := range sources {
+					cli.sources[path] = source
+				}
+				continue
+			}
+
 			cli.formatter.Print(tflint.Issues{}, err, sources)
+			cli.writeProfileOnError(opts)
 			return ExitCodeError
 		}
 	}
+	cli.profiler = profiler
 
 	var force bool
 	if opts.Recursive {
@@ -88,14 +144,43 @@ func (cli *CLI) inspect(opts Options) int {
 		force = cli.config.Force
 	}
 
+	var appErr error
+	if len(dirErrs) > 0 {
+		appErr = dirErrs
+	} else if len(cli.pluginErrs) > 0 {
+		appErr = cli.pluginErrs
+	}
+
 	cli.formatter.Fix = opts.Fix
-	cli.formatter.Print(issues, nil, cli.sources)
+	if opts.ExplainUnknowns {
+		cli.formatter.Unevaluated = tflint.MergeUnevaluated(cli.unevaluated)
+	}
+	cli.formatter.Print(issues, appErr, cli.sources)
+	if opts.ExplainUnknowns {
+		cli.formatter.PrintUnevaluated(cli.formatter.Unevaluated)
+	}
 
 	if opts.Fix {
 		if err := writeChanges(changes); err != nil {
 			cli.formatter.Print(tflint.Issues{}, err, cli.sources)
+			cli.writeProfileOnError(opts)
+			return ExitCodeError
+		}
+	}
+
+	if err := cli.writeProfile(opts); err != nil {
+		cli.formatter.Print(tflint.Issues{}, err, cli.sources)
+		return ExitCodeError
+	}
+
+	if failedDirs > 0 {
+		if failedDirs == len(workingDirs) {
 			return ExitCodeError
 		}
+		return ExitCodePartialFailure
+	}
+	if len(cli.pluginErrs) > 0 {
+		return ExitCodePartialFailure
 	}
 
 	if len(issues) > 0 && !force && exceedsMinimumFailure(issues, opts.MinimumFailureSeverity) {
@@ -111,30 +196,53 @@ func (cli *CLI) inspectModule(opts Options, dir string, filterFiles []string) (t
 	var err error
 
 	// Setup config
+	stop := cli.profiler.Start("load TFLint config")
 	cli.config, err = tflint.LoadConfig(afero.Afero{Fs: afero.NewOsFs()}, opts.Config)
+	stop()
 	if err != nil {
 		return issues, changes, fmt.Errorf("Failed to load TFLint config; %w", err)
 	}
 	cli.config.Merge(opts.toConfig())
 
 	// Setup loader
-	cli.loader, err = terraform.NewLoader(afero.Afero{Fs: afero.NewOsFs()}, cli.originalWorkingDir)
+	// If an archive is passed, the archive root is treated as the current directory.
+	fs := afero.NewOsFs()
+	if opts.Archive != "" {
+		fs, err = terraform.NewArchiveFs(opts.Archive)
+		if err != nil {
+			return issues, changes, fmt.Errorf("Failed to open the archive; %w", err)
+		}
+	}
+	cli.loader, err = terraform.NewLoader(afero.Afero{Fs: fs}, cli.originalWorkingDir)
 	if err != nil {
 		return issues, changes, fmt.Errorf("Failed to prepare loading; %w", err)
 	}
+	if opts.Archive != "" {
+		// Values files passed by --var-file are read from the local disk
+		cli.loader.SetVarFileFs(afero.NewOsFs())
+	}
+	cli.loader.SetLanguage(cli.config.Language)
+	cli.loader.SetModuleMirror(cli.config.ModuleMirror)
+	cli.loader.SetStaleModuleManifest(cli.config.StaleModuleManifest)
+	cli.loader.SetInputVariables(cli.config.Varfiles, cli.config.Variables)
 	if opts.Recursive && !cli.loader.IsConfigDir(dir) {
 		// Ignore non-module directories in recursive mode
 		return issues, changes, nil
 	}
 
-	// Setup runners
-	rootRunner, moduleRunners, err := cli.setupRunners(opts, dir)
+	// Load configurations
+	stop = cli.profiler.Start("load Terraform configurations")
+	configs, annotations, err := cli.loadConfigs(dir)
+	stop()
 	if err != nil {
 		return issues, changes, err
 	}
 
 	// Launch plugin processes
+	cli.failedPlugins = map[string]bool{}
+	stop = cli.profiler.Start("launch plugins")
 	rulesetPlugin, err := launchPlugins(cli.config, opts.Fix)
+	stop()
 	if rulesetPlugin != nil {
 		defer rulesetPlugin.Clean()
 	}
@@ -160,13 +268,104 @@ func (cli *CLI) inspectModule(opts Options, dir string, filterFiles []string) (t
 		sdkVersions[name] = sdkVersion
 	}
 
-	// Run inspection
+	// Run inspection for each scenario
 	//
-	// Repeat an inspection until there are no more changes or the limit is reached,
-	// in case an autofix introduces new issues.
+	// If no scenarios are declared, inspect the module only once with the config.
+	// Otherwise, runners are built for each scenario while reusing the loaded configurations,
+	// and identical issues found in multiple scenarios are merged.
+	scenarios := cli.config.Scenarios
+	if len(scenarios) == 0 {
+		scenarios = []*tflint.ScenarioConfig{nil}
+	}
+	if opts.AllWorkspaces {
+		workspaces, err := terraform.Workspaces()
+		if err != nil {
+			return issues, changes, fmt.Errorf("Failed to find workspaces; %w", err)
+		}
+		scenarios = workspaceScenarios(scenarios, workspaces)
+	}
+	for _, scenario := range scenarios {
+		config := cli.config
+		scenarioConfigs := configs
+		if scenario != nil {
+			log.Printf("[INFO] Inspect the module in the scenario: %s", scenario.Name)
+			config = cli.config.ForScenario(scenario)
+
+			// In OpenTofu, module sources can refer to input variables,
+			// so the module tree is built again with the values of the scenario.
+			if cli.loader.Language(dir) == terraform.LanguageOpenTofu {
+				cli.loader.SetInputVariables(config.Varfiles, config.Variables)
+				var diags hcl.Diagnostics
+				scenarioConfigs, diags = cli.loader.LoadConfig(dir, config.CallModuleType)
+				if diags.HasErrors() {
+					return issues, changes, fmt.Errorf("Failed to set up the scenario %q; Failed to load configurations; %w", scenario.Name, diags)
+				}
+			}
+		}
+
+		rootRunner, moduleRunners, err := cli.setupRunners(config, dir, scenarioConfigs, annotations)
+		if err != nil {
+			if scenario != nil {
+				return issues, changes, fmt.Errorf("Failed to set up the scenario %q; %w", scenario.Name, err)
+			}
+			return issues, changes, err
+		}
+
+		scenarioIssues, err := cli.runInspection(opts, rulesetPlugin, sdkVersions, rootRunner, moduleRunners, filterFiles, changes)
+		if err != nil {
+			return issues, changes, err
+		}
+		if scenario != nil {
+			for _, issue := range scenarioIssues {
+				issue.Scenarios = []string{scenario.Name}
+			}
+		}
+		issues = append(issues, scenarioIssues...)
+	}
+	issues = issues.Merge()
+
+	// Set module sources to CLI
+	for path, source := range cli.loader.Sources() {
+		cli.sources[path] = source
+	}
+
+	return issues, changes, nil
+}
+
+// workspaceScenarios expands the passed scenarios for each workspace.
+// If no scenarios are declared (a nil scenario is passed), the workspace name is used as the scenario name.
+// Otherwise, the scenario name is suffixed with the workspace name like "production/default".
+func workspaceScenarios(scenarios []*tflint.ScenarioConfig, workspaces []string) []*tflint.ScenarioConfig {
+	ret := []*tflint.ScenarioConfig{}
+
+	for _, scenario := range scenarios {
+		for _, workspace := range workspaces {
+			if scenario == nil {
+				ret = append(ret, &tflint.ScenarioConfig{Name: workspace, Workspace: workspace})
+				continue
+			}
+			ret = append(ret, &tflint.ScenarioConfig{
+				Name:      fmt.Sprintf("%s/%s", scenario.Name, workspace),
+				Varfiles:  scenario.Varfiles,
+				Variables: scenario.Variables,
+				Workspace: workspace,
+			})
+		}
+	}
+
+	return ret
+}
+
+// runInspection runs checks of all rulesets against the passed runners.
+//
+// Repeat an inspection until there are no more changes or the limit is reached,
+// in case an autofix introduces new issues. Changes made by autofixes are stored in the passed map.
+func (cli *CLI) runInspection(opts Options, rulesetPlugin *plugin.Plugin, sdkVersions map[string]*version.Version, rootRunner *tflint.Runner, moduleRunners []*tflint.Runner, filterFiles []string, changes map[string][]byte) (tflint.Issues, error) {
+	issues := tflint.Issues{}
+
 	for loop := 1; ; loop++ {
 		if loop > 10 {
-			return issues, changes, fmt.Errorf(`Reached the limit of autofix attempts, and the changes made by the autofix will not be applied. This may be due to the following reasons:
+			return issues, fmt.Errorf(`Reached the limit of autofix attempts, and the changes made by the autofix will not be applied. This may be due to the following reasons:
 
 1. The autofix is making changes that do not fix the issue.
 2. The autofix is continuing to introduce new issues.
@@ -174,30 +373,31 @@ func (cli *CLI) inspectModule(opts Options, dir string, filterFiles []string) (t
 By setting TFLINT_LOG=trace, you can confirm the changes made by the autofix and start troubleshooting.`)
 		}
 
-		for name, ruleset := range rulesetPlugin.RuleSets {
-			if err := ruleset.Check(plugin.NewGRPCServer(rootRunner, rootRunner, cli.loader.Files(), sdkVersions[name])); err != nil {
-				return issues, changes, fmt.Errorf("Failed to check ruleset; %w", err)
+		for name := range rulesetPlugin.RuleSets {
+			// Failed plugins are not checked again in the following attempts and scenarios
+			if cli.failedPlugins[name] {
+				continue
 			}
-			// Run checks for module calls are performed in parallel.
-			// The rootRunner is shared between goroutines but read-only, so this is goroutine-safe.
-			// Note that checks against the rootRunner are not parallelized, as autofix may cause the module to be rebuilt.
-			ch := make(chan error, len(moduleRunners))
-			for _, runner := range moduleRunners {
-				if opts.NoParallelRunners {
-					ch <- ruleset.Check(plugin.NewGRPCServer(runner, rootRunner, cli.loader.Files(), sdkVersions[name]))
-				} else {
-					go func(runner *tflint.Runner) {
-						ch <- ruleset.Check(plugin.NewGRPCServer(runner, rootRunner, cli.loader.Files(), sdkVersions[name]))
-					}(runner)
-				}
+
+			runners := append(moduleRunners, rootRunner)
+			counts := make([]int, len(runners))
+			for i, runner := range runners {
+				counts[i] = len(runner.Issues)
 			}
-			for i := 0; i < len(moduleRunners); i++ {
-				err = <-ch
-				if err != nil {
-					return issues, changes, fmt.Errorf("Failed to check ruleset; %w", err)
+
+			if err := cli.checkRuleset(opts, rulesetPlugin, name, sdkVersions[name], rootRunner, moduleRunners); err != nil {
+				if !opts.KeepGoingPlugins {
+					return issues, fmt.Errorf("Failed to check ruleset; %w", err)
+				}
+
+				log.Printf(`[ERROR] Failed to check "%s" ruleset; %s`, name, err)
+				cli.pluginErrs = append(cli.pluginErrs, &tflint.PluginError{Name: name, Err: err})
+				cli.failedPlugins[name] = true
+				// Discard issues emitted by the failed plugin, as the result is incomplete
+				for i, runner := range runners {
+					runner.Issues = runner.Issues[:counts[i]]
 				}
 			}
-			close(ch)
 		}
 
 		changesInAttempt := map[string][]byte{}
@@ -210,6 +410,11 @@ By setting TFLINT_LOG=trace, you can confirm the changes made by the autofix and
 			}
 			runner.Issues = tflint.Issues{}
 
+			// Expressions are recorded in the first attempt. The following attempts only check fixed code.
+			if opts.ExplainUnknowns && loop == 1 {
+				cli.unevaluated = append(cli.unevaluated, runner.LookupUnevaluated(filterFiles...)...)
+			}
+
 			for path, source := range runner.LookupChanges(filterFiles...) {
 				changesInAttempt[path] = source
 				changes[path] = source
@@ -222,27 +427,123 @@ By setting TFLINT_LOG=trace, you can confirm the changes made by the autofix and
 		}
 	}
 
-	// Set module sources to CLI
-	for path, source := range cli.loader.Sources() {
-		cli.sources[path] = source
+	return issues, nil
+}
+
+// checkRuleset runs checks of the ruleset against the root runner and module runners.
+//
+// Checks for module calls are performed in parallel.
+// The rootRunner is shared between goroutines but read-only, so this is goroutine-safe.
+// Note that checks against the rootRunner are not parallelized, as autofix may cause the module to be rebuilt.
+//
+// If the check does not finish within the timeout, the plugin is killed.
+// The "timeout" attribute of the plugin takes precedence over --plugin-timeout.
+func (cli *CLI) checkRuleset(opts Options, rulesetPlugin *plugin.Plugin, name string, sdkVersion *version.Version, rootRunner *tflint.Runner, moduleRunners []*tflint.Runner) error {
+	timeout := opts.PluginTimeout
+	if pluginCfg, exists := cli.config.Plugins[name]; exists && pluginCfg.CheckTimeout > 0 {
+		timeout = pluginCfg.CheckTimeout
 	}
 
-	return issues, changes, nil
+	ctx := context.Background()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeoutCause(ctx, timeout, fmt.Errorf("the check did not finish within %s", timeout))
+		defer cancel()
+	}
+
+	check := func(runner *tflint.Runner) error {
+		defer cli.profiler.Start("check", "plugin", name, "module", runner.ModuleName())()
+
+		server := plugin.NewGRPCServer(runner, rootRunner, cli.loader.Files(), sdkVersion).WithProfiler(cli.profiler, name)
+		return rulesetPlugin.Check(ctx, name, server)
+	}
+
+	if err := check(rootRunner); err != nil {
+		return err
+	}
+
+	ch := make(chan error, len(moduleRunners))
+	for _, runner := range moduleRunners {
+		if opts.NoParallelRunners {
+			ch <- check(runner)
+		} else {
+			go func(runner *tflint.Runner) {
+				ch <- check(runner)
+			}(runner)
+		}
+	}
+	// Wait for all checks to finish so that runners are not touched after returning
+	var checkErr error
+	for i := 0; i < len(moduleRunners); i++ {
+		if err := <-ch; err != nil && checkErr == nil {
+			checkErr = err
+		}
+	}
+	close(ch)
+
+	return checkErr
+}
+
+// writeProfile prints the timing summary with --profile and writes the trace file with --trace-file.
+// The summary is written to stderr so as not to break the output format.
+func (cli *CLI) writeProfile(opts Options) error {
+	if opts.Profile {
+		fmt.Fprint(cli.errStream, "\nProfile:\n\n")
+		if err := cli.profiler.PrintSummary(cli.errStream); err != nil {
+			return fmt.Errorf("Failed to print the profile; %w", err)
+		}
+	}
+
+	if opts.TraceFile != "" {
+		// The trace file is relative to the directory where the command is run
+		path := opts.TraceFile
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(cli.originalWorkingDir, path)
+		}
+
+		f, err := os.Create(path)
+		if err != nil {
+			return fmt.Errorf("Failed to write the trace file; %w", err)
+		}
+		defer f.Close()
+
+		if err := cli.profiler.WriteTrace(f); err != nil {
+			return fmt.Errorf("Failed to write the trace file; %w", err)
+		}
+	}
+
+	return nil
+}
+
+// writeProfileOnError writes the profile when the inspection fails, so that slow operations
+// before the failure can be investigated. An error in writing is only logged so as not to hide the original error.
+func (cli *CLI) writeProfileOnError(opts Options) {
+	if err := cli.writeProfile(opts); err != nil {
+		log.Printf("[ERROR] %s", err)
+	}
 }
 
-func (cli *CLI) setupRunners(opts Options, dir string) (*tflint.Runner, []*tflint.Runner, error) {
+// loadConfigs loads Terraform configurations and annotations in the passed directory.
+// The loaded configurations are shared by runners in all scenarios.
+func (cli *CLI) loadConfigs(dir string) (*terraform.Config, map[string]tflint.Annotations, error) {
 	configs, diags := cli.loader.LoadConfig(dir, cli.config.CallModuleType)
 	if diags.HasErrors() {
-		return nil, []*tflint.Runner{}, fmt.Errorf("Failed to load configurations; %w", diags)
+		return nil, nil, fmt.Errorf("Failed to load configurations; %w", diags)
 	}
+	// Warnings (e.g. stale module manifests) do not stop the inspection.
+	// They are printed with issues in the output format.
+	cli.formatter.AddWarnings(diags)
 
 	files, diags := cli.loader.LoadConfigDirFiles(dir)
 	if diags.HasErrors() {
-		return nil, []*tflint.Runner{}, fmt.Errorf("Failed to load configurations; %w", diags)
+		return nil, nil, fmt.Errorf("Failed to load configurations; %w", diags)
+	}
+	for path, file := range configs.Module.TestFiles {
+		files[path] = file
 	}
 	annotations := map[string]tflint.Annotations{}
 	for path, file := range files {
-		if !strings.HasSuffix(path, ".tf") {
+		if !terraform.IsNativeSyntaxFile(path) {
 			continue
 		}
 		ants, lexDiags := tflint.NewAnnotations(path, file)
@@ -250,25 +551,33 @@ func (cli *CLI) setupRunners(opts Options, dir string) (*tflint.Runner, []*tflin
 		annotations[path] = ants
 	}
 	if diags.HasErrors() {
-		return nil, []*tflint.Runner{}, fmt.Errorf("Failed to load configurations; %w", diags)
+		return nil, nil, fmt.Errorf("Failed to load configurations; %w", diags)
 	}
 
-	variables, diags := cli.loader.LoadValuesFiles(dir, cli.config.Varfiles...)
+	return configs, annotations, nil
+}
+
+func (cli *CLI) setupRunners(config *tflint.Config, dir string, configs *terraform.Config, annotations map[string]tflint.Annotations) (*tflint.Runner, []*tflint.Runner, error) {
+	variables, diags := cli.loader.LoadValuesFiles(dir, config.Varfiles...)
 	if diags.HasErrors() {
 		return nil, []*tflint.Runner{}, fmt.Errorf("Failed to load values files; %w", diags)
 	}
-	cliVars, diags := terraform.ParseVariableValues(cli.config.Variables, configs.Module.Variables)
+	cliVars, diags := terraform.ParseVariableValues(config.Variables, configs.Module.Variables)
 	if diags.HasErrors() {
 		return nil, []*tflint.Runner{}, fmt.Errorf("Failed to parse variables; %w", diags)
 	}
 	variables = append(variables, cliVars)
 
-	runner, err := tflint.NewRunner(cli.originalWorkingDir, cli.config, annotations, configs, variables...)
+	stop := cli.profiler.Start("NewRunner")
+	runner, err := tflint.NewRunner(cli.originalWorkingDir, config, annotations, configs, variables...)
+	stop()
 	if err != nil {
 		return nil, []*tflint.Runner{}, fmt.Errorf("Failed to initialize a runner; %w", err)
 	}
 
+	stop = cli.profiler.Start("NewModuleRunners")
 	moduleRunners, err := tflint.NewModuleRunners(runner)
+	stop()
 	if err != nil {
 		return nil, []*tflint.Runner{}, fmt.Errorf("Failed to prepare rule checking; %w", err)
 	}
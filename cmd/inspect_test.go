@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/terraform-linters/tflint/tflint"
+)
+
+func Test_workspaceScenarios(t *testing.T) {
+	tests := []struct {
+		name       string
+		scenarios  []*tflint.ScenarioConfig
+		workspaces []string
+		want       []*tflint.ScenarioConfig
+	}{
+		{
+			name:       "no scenarios",
+			scenarios:  []*tflint.ScenarioConfig{nil},
+			workspaces: []string{"default", "production"},
+			want: []*tflint.ScenarioConfig{
+				{Name: "default", Workspace: "default"},
+				{Name: "production", Workspace: "production"},
+			},
+		},
+		{
+			name: "scenarios",
+			scenarios: []*tflint.ScenarioConfig{
+				{Name: "small", Variables: []string{"size=micro"}},
+				{Name: "large", Varfiles: []string{"large.tfvars"}, Workspace: "staging"},
+			},
+			workspaces: []string{"default", "production"},
+			want: []*tflint.ScenarioConfig{
+				{Name: "small/default", Variables: []string{"size=micro"}, Workspace: "default"},
+				{Name: "small/production", Variables: []string{"size=micro"}, Workspace: "production"},
+				{Name: "large/default", Varfiles: []string{"large.tfvars"}, Workspace: "default"},
+				{Name: "large/production", Varfiles: []string{"large.tfvars"}, Workspace: "production"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := workspaceScenarios(test.scenarios, test.workspaces)
+
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/afero"
+	"github.com/terraform-linters/tflint/plugin"
+	"github.com/terraform-linters/tflint/tflint"
+)
+
+func (cli *CLI) mirrorPlugins(opts Options) int {
+	workingDirs, err := findWorkingDirs(opts)
+	if err != nil {
+		cli.formatter.Print(tflint.Issues{}, fmt.Errorf("Failed to find workspaces; %w", err), map[string][]byte{})
+		return ExitCodeError
+	}
+
+	// The mirror directory is relative to the directory where the command is run
+	mirrorDir := opts.MirrorPlugins
+	if !filepath.IsAbs(mirrorDir) {
+		mirrorDir = filepath.Join(cli.originalWorkingDir, mirrorDir)
+	}
+
+	if opts.Recursive {
+		fmt.Fprint(cli.outStream, "Mirroring plugins on each working directory...\n\n")
+	}
+
+	for _, wd := range workingDirs {
+		err := cli.withinChangedDir(wd, func() error {
+			if opts.Recursive {
+				fmt.Fprint(cli.outStream, "====================================================\n")
+				fmt.Fprintf(cli.outStream, "working directory: %s\n\n", wd)
+			}
+
+			cfg, err := tflint.LoadConfig(afero.Afero{Fs: afero.NewOsFs()}, opts.Config)
+			if err != nil {
+				return fmt.Errorf("Failed to load TFLint config; %w", err)
+			}
+
+			found := false
+			for _, pluginCfg := range cfg.Plugins {
+				installCfg := plugin.NewInstallConfig(cfg, pluginCfg)
+
+				// Manually installed plugins cannot be downloaded
+				if installCfg.ManuallyInstalled() {
+					continue
+				}
+				found = true
+
+				fmt.Fprintf(cli.outStream, "Mirroring \"%s\" plugin...\n", pluginCfg.Name)
+
+				dir, err := installCfg.Mirror(mirrorDir)
+				if err != nil {
+					return fmt.Errorf("Failed to mirror a plugin; %w", err)
+				}
+
+				fmt.Fprintf(cli.outStream, "Mirrored \"%s\" (source: %s, version: %s) to %s\n", pluginCfg.Name, pluginCfg.Source, pluginCfg.Version, dir)
+			}
+
+			if !found {
+				fmt.Fprint(cli.outStream, "No plugins to mirror\n")
+			}
+
+			return nil
+		})
+		if err != nil {
+			cli.formatter.Print(tflint.Issues{}, err, map[string][]byte{})
+			return ExitCodeError
+		}
+	}
+
+	return ExitCodeOK
+}
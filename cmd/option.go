@@ -3,6 +3,7 @@ package cmd
 import (
 	"log"
 	"strings"
+	"time"
 
 	"github.com/terraform-linters/tflint/terraform"
 	"github.com/terraform-linters/tflint/tflint"
@@ -10,31 +11,51 @@ import (
 
 // Options is an option specified by arguments.
 type Options struct {
-	Version                bool     `short:"v" long:"version" description:"Print TFLint version"`
-	Init                   bool     `long:"init" description:"Install plugins"`
-	Langserver             bool     `long:"langserver" description:"Start language server"`
-	Format                 string   `short:"f" long:"format" description:"Output format" choice:"default" choice:"json" choice:"checkstyle" choice:"junit" choice:"compact" choice:"sarif"`
-	Config                 string   `short:"c" long:"config" description:"Config file name (default: .tflint.hcl)" value-name:"FILE"`
-	IgnoreModules          []string `long:"ignore-module" description:"Ignore module sources" value-name:"SOURCE"`
-	EnableRules            []string `long:"enable-rule" description:"Enable rules from the command line" value-name:"RULE_NAME"`
-	DisableRules           []string `long:"disable-rule" description:"Disable rules from the command line" value-name:"RULE_NAME"`
-	Only                   []string `long:"only" description:"Enable only this rule, disabling all other defaults. Can be specified multiple times" value-name:"RULE_NAME"`
-	EnablePlugins          []string `long:"enable-plugin" description:"Enable plugins from the command line" value-name:"PLUGIN_NAME"`
-	Varfiles               []string `long:"var-file" description:"Terraform variable file name" value-name:"FILE"`
-	Variables              []string `long:"var" description:"Set a Terraform variable" value-name:"'foo=bar'"`
-	Module                 *bool    `long:"module" description:"Enable module inspection" hidden:"true"`
-	NoModule               *bool    `long:"no-module" description:"Disable module inspection" hidden:"true"`
-	CallModuleType         *string  `long:"call-module-type" description:"Types of module to call (default: local)" choice:"all" choice:"local" choice:"none"`
-	Chdir                  string   `long:"chdir" description:"Switch to a different working directory before executing the command" value-name:"DIR"`
-	Recursive              bool     `long:"recursive" description:"Run command in each directory recursively"`
-	Filter                 []string `long:"filter" description:"Filter issues by file names or globs" value-name:"FILE"`
-	Force                  *bool    `long:"force" description:"Return zero exit status even if issues found"`
-	MinimumFailureSeverity string   `long:"minimum-failure-severity" description:"Sets minimum severity level for exiting with a non-zero error code" choice:"error" choice:"warning" choice:"notice"`
-	Color                  bool     `long:"color" description:"Enable colorized output"`
-	NoColor                bool     `long:"no-color" description:"Disable colorized output"`
-	Fix                    bool     `long:"fix" description:"Fix issues automatically"`
-	NoParallelRunners      bool     `long:"no-parallel-runners" description:"Disable per-runner parallelism"`
-	ActAsBundledPlugin     bool     `long:"act-as-bundled-plugin" hidden:"true"`
+	Version                bool          `short:"v" long:"version" description:"Print TFLint version"`
+	Init                   bool          `long:"init" description:"Install plugins"`
+	Upgrade                bool          `long:"upgrade" description:"Reinstall plugins and update the lock file with --init"`
+	MirrorPlugins          string        `long:"mirror-plugins" description:"Download plugins into the directory to use as a plugin mirror" value-name:"DIR"`
+	PluginsList            bool          `long:"plugins-list" description:"List installed plugins"`
+	PluginsPrune           bool          `long:"plugins-prune" description:"Print installed plugin versions that are not used by the config. Use with --yes to remove them"`
+	Yes                    bool          `long:"yes" description:"Remove unused plugins with --plugins-prune"`
+	Langserver             bool          `long:"langserver" description:"Start language server"`
+	Format                 string        `short:"f" long:"format" description:"Output format" choice:"default" choice:"json" choice:"checkstyle" choice:"junit" choice:"compact" choice:"sarif"`
+	Config                 string        `short:"c" long:"config" description:"Config file name (default: .tflint.hcl)" value-name:"FILE"`
+	IgnoreModules          []string      `long:"ignore-module" description:"Ignore module sources or module calls" value-name:"SOURCE"`
+	EnableRules            []string      `long:"enable-rule" description:"Enable rules from the command line" value-name:"RULE_NAME"`
+	DisableRules           []string      `long:"disable-rule" description:"Disable rules from the command line" value-name:"RULE_NAME"`
+	Only                   []string      `long:"only" description:"Enable only this rule, disabling all other defaults. Can be specified multiple times" value-name:"RULE_NAME"`
+	EnablePlugins          []string      `long:"enable-plugin" description:"Enable plugins from the command line" value-name:"PLUGIN_NAME"`
+	Varfiles               []string      `long:"var-file" description:"Terraform variable file name" value-name:"FILE"`
+	Variables              []string      `long:"var" description:"Set a Terraform variable" value-name:"'foo=bar'"`
+	Module                 *bool         `long:"module" description:"Enable module inspection" hidden:"true"`
+	NoModule               *bool         `long:"no-module" description:"Disable module inspection" hidden:"true"`
+	CallModuleType         *string       `long:"call-module-type" description:"Types of module to call (default: local)" choice:"all" choice:"local" choice:"none"`
+	Language               *string       `long:"language" description:"Configuration language to interpret (default: auto)" choice:"auto" choice:"terraform" choice:"opentofu"`
+	StaleModuleManifest    *string       `long:"stale-module-manifest" description:"How to handle module manifests that do not match the configuration (default: warning)" choice:"warning" choice:"error" choice:"ignore"`
+	Workspace              *string       `long:"workspace" description:"Workspace name to evaluate terraform.workspace (default: current workspace)" value-name:"NAME"`
+	AllWorkspaces          bool          `long:"all-workspaces" description:"Run inspection in each workspace found in terraform.tfstate.d"`
+	Chdir                  string        `long:"chdir" description:"Switch to a different working directory before executing the command" value-name:"DIR"`
+	Recursive              bool          `long:"recursive" description:"Run command in each directory recursively"`
+	KeepGoing              bool          `long:"keep-going" description:"Continue inspecting other directories even if errors occur in recursive mode"`
+	Excludes               []string      `long:"exclude" description:"Exclude directories matching the pattern in recursive mode" value-name:"GLOB"`
+	Gitignore              bool          `long:"gitignore" description:"Exclude directories ignored by .gitignore in recursive mode"`
+	RootModulesOnly        bool          `long:"root-modules-only" description:"Skip directories only used as local modules in recursive mode"`
+	Archive                string        `long:"archive" description:"Inspect a module archive (zip, tar, tar.gz) instead of the current directory" value-name:"FILE"`
+	Filter                 []string      `long:"filter" description:"Filter issues by file names or globs" value-name:"FILE"`
+	Force                  *bool         `long:"force" description:"Return zero exit status even if issues found"`
+	MinimumFailureSeverity string        `long:"minimum-failure-severity" description:"Sets minimum severity level for exiting with a non-zero error code" choice:"error" choice:"warning" choice:"notice"`
+	Color                  bool          `long:"color" description:"Enable colorized output"`
+	NoColor                bool          `long:"no-color" description:"Disable colorized output"`
+	Fix                    bool          `long:"fix" description:"Fix issues automatically"`
+	ExplainUnknowns        bool          `long:"explain-unknowns" description:"Report expressions that could not be evaluated and the inputs to set"`
+	NoParallelRunners      bool          `long:"no-parallel-runners" description:"Disable per-runner parallelism"`
+	PluginTimeout          time.Duration `long:"plugin-timeout" description:"Abort checks of plugins that do not finish within the duration" value-name:"DURATION"`
+	KeepGoingPlugins       bool          `long:"keep-going-plugins" description:"Continue inspection with other plugins even if a plugin fails"`
+	Profile                bool          `long:"profile" description:"Print a timing summary of loading, evaluation, and plugin checks"`
+	TraceFile              string        `long:"trace-file" description:"Write timings in the Chrome trace event format to the file" value-name:"FILE"`
+	ActAsBundledPlugin     bool          `long:"act-as-bundled-plugin" hidden:"true"`
+	ActAsPluginLauncher    bool          `long:"act-as-plugin-launcher" hidden:"true"`
 }
 
 func (opts *Options) toConfig() *tflint.Config {
@@ -76,6 +97,37 @@ func (opts *Options) toConfig() *tflint.Config {
 		callModuleTypeSet = true
 	}
 
+	language := terraform.LanguageAuto
+	languageSet := false
+	if opts.Language != nil {
+		var err error
+		language, err = terraform.AsLanguage(*opts.Language)
+		if err != nil {
+			// This should never happen because the option is already validated by go-flags
+			panic(err)
+		}
+		languageSet = true
+	}
+
+	staleModuleManifest := terraform.StaleModuleManifestWarning
+	staleModuleManifestSet := false
+	if opts.StaleModuleManifest != nil {
+		var err error
+		staleModuleManifest, err = terraform.AsStaleModuleManifest(*opts.StaleModuleManifest)
+		if err != nil {
+			// This should never happen because the option is already validated by go-flags
+			panic(err)
+		}
+		staleModuleManifestSet = true
+	}
+
+	var workspace string
+	var workspaceSet bool
+	if opts.Workspace != nil {
+		workspace = *opts.Workspace
+		workspaceSet = true
+	}
+
 	var force, forceSet bool
 	if opts.Force != nil {
 		force = *opts.Force
@@ -86,6 +138,10 @@ func (opts *Options) toConfig() *tflint.Config {
 	log.Printf("[DEBUG]   CallModuleType: %s", callModuleType)
 	log.Printf("[DEBUG]   Force: %t", force)
 	log.Printf("[DEBUG]   Format: %s", opts.Format)
+	log.Printf("[DEBUG]   Language: %s", language)
+	log.Printf("[DEBUG]   StaleModuleManifest: %s", staleModuleManifest)
+	log.Printf("[DEBUG]   Workspace: %s", workspace)
+	log.Printf("[DEBUG]   AllWorkspaces: %t", opts.AllWorkspaces)
 	log.Printf("[DEBUG]   Varfiles: %s", strings.Join(opts.Varfiles, ", "))
 	log.Printf("[DEBUG]   Variables: %s", strings.Join(opts.Variables, ", "))
 	log.Printf("[DEBUG]   EnableRules: %s", strings.Join(opts.EnableRules, ", "))
@@ -136,6 +192,15 @@ func (opts *Options) toConfig() *tflint.Config {
 		Format:    opts.Format,
 		FormatSet: opts.Format != "",
 
+		Language:    language,
+		LanguageSet: languageSet,
+
+		StaleModuleManifest:    staleModuleManifest,
+		StaleModuleManifestSet: staleModuleManifestSet,
+
+		Workspace:    workspace,
+		WorkspaceSet: workspaceSet,
+
 		DisabledByDefault:    len(opts.Only) > 0,
 		DisabledByDefaultSet: len(opts.Only) > 0,
 
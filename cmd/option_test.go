@@ -37,6 +37,54 @@ func Test_toConfig(t *testing.T) {
 				Plugins:           map[string]*tflint.PluginConfig{},
 			},
 		},
+		{
+			Name:    "--language",
+			Command: "./tflint --language opentofu",
+			Expected: &tflint.Config{
+				CallModuleType:    terraform.CallLocalModule,
+				Force:             false,
+				Language:          terraform.LanguageOpenTofu,
+				LanguageSet:       true,
+				IgnoreModules:     map[string]bool{},
+				Varfiles:          []string{},
+				Variables:         []string{},
+				DisabledByDefault: false,
+				Rules:             map[string]*tflint.RuleConfig{},
+				Plugins:           map[string]*tflint.PluginConfig{},
+			},
+		},
+		{
+			Name:    "--stale-module-manifest",
+			Command: "./tflint --stale-module-manifest error",
+			Expected: &tflint.Config{
+				CallModuleType:         terraform.CallLocalModule,
+				Force:                  false,
+				StaleModuleManifest:    terraform.StaleModuleManifestError,
+				StaleModuleManifestSet: true,
+				IgnoreModules:          map[string]bool{},
+				Varfiles:               []string{},
+				Variables:              []string{},
+				DisabledByDefault:      false,
+				Rules:                  map[string]*tflint.RuleConfig{},
+				Plugins:                map[string]*tflint.PluginConfig{},
+			},
+		},
+		{
+			Name:    "--workspace",
+			Command: "./tflint --workspace production",
+			Expected: &tflint.Config{
+				CallModuleType:    terraform.CallLocalModule,
+				Force:             false,
+				Workspace:         "production",
+				WorkspaceSet:      true,
+				IgnoreModules:     map[string]bool{},
+				Varfiles:          []string{},
+				Variables:         []string{},
+				DisabledByDefault: false,
+				Rules:             map[string]*tflint.RuleConfig{},
+				Plugins:           map[string]*tflint.PluginConfig{},
+			},
+		},
 		{
 			Name:    "--module",
 			Command: "./tflint --module",
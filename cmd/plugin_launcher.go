@@ -0,0 +1,18 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/terraform-linters/tflint/plugin"
+)
+
+// actAsPluginLauncher starts a plugin with the environment passed by the parent TFLint process.
+// See plugin.LaunchPlugin for details.
+func (cli *CLI) actAsPluginLauncher() int {
+	code, err := plugin.LaunchPlugin()
+	if err != nil {
+		fmt.Fprintf(cli.errStream, "Failed to launch the plugin; %s\n", err)
+		return ExitCodeError
+	}
+	return code
+}
@@ -0,0 +1,184 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/afero"
+	"github.com/terraform-linters/tflint/plugin"
+	"github.com/terraform-linters/tflint/tflint"
+)
+
+// pluginDirUsage is a plugin directory and paths of plugins used by configs.
+// Only the install directory (the first directory of the search path) is writable.
+// Other directories in the search path may be provisioned by others, so they are never pruned.
+type pluginDirUsage struct {
+	dir      string
+	used     map[string]bool
+	writable bool
+}
+
+func (cli *CLI) listPlugins(opts Options) int {
+	usages, err := cli.collectPluginDirUsages(opts)
+	if err != nil {
+		cli.formatter.Print(tflint.Issues{}, err, map[string][]byte{})
+		return ExitCodeError
+	}
+
+	for i, usage := range usages {
+		if i > 0 {
+			fmt.Fprint(cli.outStream, "\n")
+		}
+		if usage.writable {
+			fmt.Fprintf(cli.outStream, "Plugin directory: %s\n", usage.dir)
+		} else {
+			fmt.Fprintf(cli.outStream, "Plugin directory: %s (read-only)\n", usage.dir)
+		}
+
+		installed, err := plugin.InstalledPlugins(usage.dir)
+		if err != nil {
+			cli.formatter.Print(tflint.Issues{}, fmt.Errorf("Failed to list installed plugins; %w", err), map[string][]byte{})
+			return ExitCodeError
+		}
+		if len(installed) == 0 {
+			fmt.Fprint(cli.outStream, "No plugins installed\n")
+			continue
+		}
+
+		for _, p := range installed {
+			marker := " "
+			if usage.used[p.Path] {
+				marker = "*"
+			}
+			if p.ManuallyInstalled() {
+				fmt.Fprintf(cli.outStream, "%s %s (manually installed)\n", marker, p.Name)
+			} else {
+				fmt.Fprintf(cli.outStream, "%s %s %s (source: %s)\n", marker, p.Name, p.Version, p.Source)
+			}
+			fmt.Fprintf(cli.outStream, "    %s\n", p.Path)
+		}
+	}
+	fmt.Fprint(cli.outStream, "\n* Used by the current config\n")
+
+	return ExitCodeOK
+}
+
+func (cli *CLI) prunePlugins(opts Options) int {
+	usages, err := cli.collectPluginDirUsages(opts)
+	if err != nil {
+		cli.formatter.Print(tflint.Issues{}, err, map[string][]byte{})
+		return ExitCodeError
+	}
+
+	// Plugin directories can be shared with other configs (e.g. ~/.tflint.d/plugins),
+	// so plugins are removed only when explicitly requested with --yes.
+	remove := opts.Yes
+
+	pruned := false
+	for _, usage := range usages {
+		if !usage.writable {
+			continue
+		}
+		installed, err := plugin.InstalledPlugins(usage.dir)
+		if err != nil {
+			cli.formatter.Print(tflint.Issues{}, fmt.Errorf("Failed to list installed plugins; %w", err), map[string][]byte{})
+			return ExitCodeError
+		}
+
+		for _, p := range installed {
+			// Manually installed plugins are not versioned, so they are never removed
+			if p.ManuallyInstalled() || usage.used[p.Path] {
+				continue
+			}
+			pruned = true
+
+			if !remove {
+				fmt.Fprintf(cli.outStream, "Would remove \"%s\" (source: %s, version: %s) from %s\n", p.Name, p.Source, p.Version, p.VersionDir())
+				continue
+			}
+			if err := os.RemoveAll(p.VersionDir()); err != nil {
+				cli.formatter.Print(tflint.Issues{}, fmt.Errorf("Failed to remove a plugin; %w", err), map[string][]byte{})
+				return ExitCodeError
+			}
+			removeEmptyParents(filepath.Dir(p.VersionDir()), usage.dir)
+			fmt.Fprintf(cli.outStream, "Removed \"%s\" (source: %s, version: %s) from %s\n", p.Name, p.Source, p.Version, p.VersionDir())
+		}
+	}
+
+	if !pruned {
+		fmt.Fprint(cli.outStream, "No plugins to remove\n")
+	} else if !remove {
+		fmt.Fprint(cli.outStream, "\nThe plugins above may still be used by other configs that share the plugin directory. Run with --yes to remove them.\n")
+	}
+
+	return ExitCodeOK
+}
+
+// collectPluginDirUsages loads the config in each working directory, and returns plugin directories
+// in the search path with plugins used by the configs. Configs that share the plugin directory are grouped together,
+// so plugins used by any of the working directories are never removed.
+// A directory is writable if it is the install directory of any of the configs.
+func (cli *CLI) collectPluginDirUsages(opts Options) ([]*pluginDirUsage, error) {
+	workingDirs, err := findWorkingDirs(opts)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to find workspaces; %w", err)
+	}
+
+	usages := []*pluginDirUsage{}
+	byDir := map[string]*pluginDirUsage{}
+
+	for _, wd := range workingDirs {
+		err := cli.withinChangedDir(wd, func() error {
+			cfg, err := tflint.LoadConfig(afero.Afero{Fs: afero.NewOsFs()}, opts.Config)
+			if err != nil {
+				return fmt.Errorf("Failed to load TFLint config; %w", err)
+			}
+
+			dirs, err := plugin.PluginDirs(cfg)
+			if err != nil {
+				return fmt.Errorf("Failed to find plugin directories; %w", err)
+			}
+			paths, err := plugin.UsedPluginPaths(cfg)
+			if err != nil {
+				return fmt.Errorf("Failed to find plugins; %w", err)
+			}
+
+			for i, dir := range dirs {
+				usage, exists := byDir[dir]
+				if !exists {
+					usage = &pluginDirUsage{dir: dir, used: map[string]bool{}}
+					byDir[dir] = usage
+					usages = append(usages, usage)
+				}
+				if i == 0 {
+					usage.writable = true
+				}
+				for _, path := range paths {
+					usage.used[path] = true
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return usages, nil
+}
+
+// removeEmptyParents removes empty directories from dir up to root.
+// The root directory itself is never removed.
+func removeEmptyParents(dir string, root string) {
+	for dir != root && len(dir) > len(root) {
+		entries, err := os.ReadDir(dir)
+		if err != nil || len(entries) > 0 {
+			return
+		}
+		if err := os.Remove(dir); err != nil {
+			return
+		}
+		dir = filepath.Dir(dir)
+	}
+}
@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_prunePlugins(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("plugin binaries require the .exe extension on Windows")
+	}
+
+	config := `
+config {
+  plugin_dir = "./plugins"
+}
+
+plugin "terraform" {
+  enabled = false
+}
+
+plugin "foo" {
+  enabled = true
+  version = "0.2.0"
+  source  = "github.com/example/tflint-ruleset-foo"
+}`
+	plugins := []string{
+		"plugins/github.com/example/tflint-ruleset-foo/0.1.0/tflint-ruleset-foo",
+		"plugins/github.com/example/tflint-ruleset-foo/0.2.0/tflint-ruleset-foo",
+	}
+
+	tests := []struct {
+		name   string
+		args   []string
+		status int
+		stdout string
+		want   []string
+	}{
+		{
+			name:   "dry run",
+			args:   []string{"./tflint", "--plugins-prune"},
+			status: ExitCodeOK,
+			stdout: `Would remove "foo" (source: github.com/example/tflint-ruleset-foo, version: 0.1.0) from plugins/github.com/example/tflint-ruleset-foo/0.1.0
+
+The plugins above may still be used by other configs that share the plugin directory. Run with --yes to remove them.
+`,
+			want: plugins,
+		},
+		{
+			name:   "--yes",
+			args:   []string{"./tflint", "--plugins-prune", "--yes"},
+			status: ExitCodeOK,
+			stdout: `Removed "foo" (source: github.com/example/tflint-ruleset-foo, version: 0.1.0) from plugins/github.com/example/tflint-ruleset-foo/0.1.0
+`,
+			want: plugins[1:],
+		},
+		{
+			name:   "--force does not remove plugins",
+			args:   []string{"./tflint", "--plugins-prune", "--force"},
+			status: ExitCodeOK,
+			stdout: `Would remove "foo" (source: github.com/example/tflint-ruleset-foo, version: 0.1.0) from plugins/github.com/example/tflint-ruleset-foo/0.1.0
+
+The plugins above may still be used by other configs that share the plugin directory. Run with --yes to remove them.
+`,
+			want: plugins,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(dir, ".tflint.hcl"), []byte(config), 0644); err != nil {
+				t.Fatal(err)
+			}
+			for _, path := range plugins {
+				path = filepath.Join(dir, path)
+				if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(path, []byte{}, 0755); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			withinDir(t, dir, func() {
+				outStream, errStream := new(bytes.Buffer), new(bytes.Buffer)
+				cli, err := NewCLI(outStream, errStream)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				got := cli.Run(test.args)
+				if got != test.status {
+					t.Errorf("expected status is %d, but got %d: %s", test.status, got, errStream.String())
+				}
+				stdout := strings.ReplaceAll(outStream.String(), dir+string(os.PathSeparator), "")
+				if diff := cmp.Diff(test.stdout, stdout); diff != "" {
+					t.Error(diff)
+				}
+
+				remaining := []string{}
+				for _, path := range plugins {
+					if _, err := os.Stat(path); err == nil {
+						remaining = append(remaining, path)
+					}
+				}
+				if diff := cmp.Diff(test.want, remaining); diff != "" {
+					t.Error(diff)
+				}
+			})
+		})
+	}
+}
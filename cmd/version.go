@@ -64,7 +64,7 @@ func getPluginVersions(opts Options) []string {
 	defer rulesetPlugin.Clean()
 
 	versions := []string{}
-	for _, ruleset := range rulesetPlugin.RuleSets {
+	for pluginName, ruleset := range rulesetPlugin.RuleSets {
 		name, err := ruleset.RuleSetName()
 		if err != nil {
 			log.Printf("[ERROR] Failed to get ruleset name: %s", err)
@@ -76,6 +76,11 @@ func getPluginVersions(opts Options) []string {
 			continue
 		}
 
+		// Show where the plugin was resolved from, unless it is bundled
+		if path := rulesetPlugin.Path(pluginName); path != "" {
+			versions = append(versions, fmt.Sprintf("+ ruleset.%s (%s) from %s\n", name, version, path))
+			continue
+		}
 		versions = append(versions, fmt.Sprintf("+ ruleset.%s (%s)\n", name, version))
 	}
 
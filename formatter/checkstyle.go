@@ -3,17 +3,19 @@ package formatter
 import (
 	"encoding/xml"
 	"fmt"
+	"strings"
 
 	"github.com/terraform-linters/tflint/tflint"
 )
 
 type checkstyleError struct {
-	Rule     string `xml:"rule,attr"`
-	Line     int    `xml:"line,attr"`
-	Column   int    `xml:"column,attr"`
-	Severity string `xml:"severity,attr"`
-	Message  string `xml:"message,attr"`
-	Link     string `xml:"link,attr"`
+	Rule      string `xml:"rule,attr"`
+	Line      int    `xml:"line,attr"`
+	Column    int    `xml:"column,attr"`
+	Severity  string `xml:"severity,attr"`
+	Message   string `xml:"message,attr"`
+	Link      string `xml:"link,attr"`
+	Scenarios string `xml:"scenarios,attr,omitempty"`
 }
 
 type checkstyleFile struct {
@@ -30,12 +32,13 @@ func (f *Formatter) checkstylePrint(issues tflint.Issues, appErr error, sources
 	files := map[string]*checkstyleFile{}
 	for _, issue := range issues {
 		cherr := &checkstyleError{
-			Rule:     issue.Rule.Name(),
-			Line:     issue.Range.Start.Line,
-			Column:   issue.Range.Start.Column,
-			Severity: toSeverity(issue.Rule.Severity()),
-			Message:  issue.Message,
-			Link:     issue.Rule.Link(),
+			Rule:      issue.Rule.Name(),
+			Line:      issue.Range.Start.Line,
+			Column:    issue.Range.Start.Column,
+			Severity:  toSeverity(issue.Rule.Severity()),
+			Message:   issue.Message,
+			Link:      issue.Rule.Link(),
+			Scenarios: strings.Join(issue.Scenarios, ","),
 		}
 
 		if file, exists := files[issue.Range.Filename]; exists {
@@ -48,6 +51,26 @@ func (f *Formatter) checkstylePrint(issues tflint.Issues, appErr error, sources
 		}
 	}
 
+	// Warnings are not issues, so they are reported with the dedicated rule name
+	for _, diag := range f.warnings {
+		cherr := &checkstyleError{
+			Rule:     warningRuleName,
+			Line:     diag.Subject.Start.Line,
+			Column:   diag.Subject.Start.Column,
+			Severity: fromHclSeverity(diag.Severity),
+			Message:  fmt.Sprintf("%s. %s", diag.Summary, diag.Detail),
+		}
+
+		if file, exists := files[diag.Subject.Filename]; exists {
+			file.Errors = append(file.Errors, cherr)
+		} else {
+			files[diag.Subject.Filename] = &checkstyleFile{
+				Name:   diag.Subject.Filename,
+				Errors: []*checkstyleError{cherr},
+			}
+		}
+	}
+
 	ret := &checkstyle{}
 	for _, file := range files {
 		ret.Files = append(ret.Files, file)
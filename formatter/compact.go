@@ -3,6 +3,7 @@ package formatter
 import (
 	"errors"
 	"fmt"
+	"strings"
 
 	hcl "github.com/hashicorp/hcl/v2"
 	"github.com/terraform-linters/tflint/tflint"
@@ -14,37 +15,59 @@ func (f *Formatter) compactPrint(issues tflint.Issues, appErr error, sources map
 	}
 
 	for _, issue := range issues {
+		var scenarios string
+		if len(issue.Scenarios) > 0 {
+			scenarios = fmt.Sprintf(" (scenarios: %s)", strings.Join(issue.Scenarios, ", "))
+		}
+
 		fmt.Fprintf(
 			f.Stdout,
-			"%s:%d:%d: %s - %s (%s)\n",
+			"%s:%d:%d: %s - %s (%s)%s\n",
 			issue.Range.Filename,
 			issue.Range.Start.Line,
 			issue.Range.Start.Column,
 			issue.Rule.Severity(),
 			issue.Message,
 			issue.Rule.Name(),
+			scenarios,
 		)
 	}
 
+	if len(f.warnings) > 0 {
+		f.compactPrintErrors(f.warnings, sources)
+	}
+
 	if appErr != nil {
-		var diags hcl.Diagnostics
-		if errors.As(appErr, &diags) {
-			for _, diag := range diags {
-				fmt.Fprintf(
-					f.Stdout,
-					"%s:%d:%d: %s - %s. %s\n",
-					diag.Subject.Filename,
-					diag.Subject.Start.Line,
-					diag.Subject.Start.Column,
-					fromHclSeverity(diag.Severity),
-					diag.Summary,
-					diag.Detail,
-				)
+		var dirErrs tflint.WorkingDirErrors
+		if errors.As(appErr, &dirErrs) {
+			for _, err := range dirErrs {
+				f.compactPrintErrors(err, sources)
 			}
-
 			return
 		}
 
-		f.prettyPrintErrors(appErr, sources)
+		f.compactPrintErrors(appErr, sources)
 	}
 }
+
+func (f *Formatter) compactPrintErrors(appErr error, sources map[string][]byte) {
+	var diags hcl.Diagnostics
+	if errors.As(appErr, &diags) {
+		for _, diag := range diags {
+			fmt.Fprintf(
+				f.Stdout,
+				"%s:%d:%d: %s - %s. %s\n",
+				diag.Subject.Filename,
+				diag.Subject.Start.Line,
+				diag.Subject.Start.Column,
+				fromHclSeverity(diag.Severity),
+				diag.Summary,
+				diag.Detail,
+			)
+		}
+
+		return
+	}
+
+	f.prettyPrintErrors(appErr, sources)
+}
@@ -39,6 +39,25 @@ func Test_compactPrint(t *testing.T) {
 			Stdout: `1 issue(s) found:
 
 test.tf:1:1: Error - test (test_rule)
+`,
+		},
+		{
+			Name: "issues in scenarios",
+			Issues: tflint.Issues{
+				{
+					Rule:    &testRule{},
+					Message: "test",
+					Range: hcl.Range{
+						Filename: "test.tf",
+						Start:    hcl.Pos{Line: 1, Column: 1, Byte: 0},
+						End:      hcl.Pos{Line: 1, Column: 4, Byte: 3},
+					},
+					Scenarios: []string{"production", "staging"},
+				},
+			},
+			Stdout: `1 issue(s) found:
+
+test.tf:1:1: Error - test (test_rule) (scenarios: production, staging)
 `,
 		},
 		{
@@ -51,6 +70,15 @@ test.tf:1:1: Error - test (test_rule)
 			Error:  hclDiags(`resource "foo" "bar" {`),
 			Stdout: "main.tf:1:22: error - Unclosed configuration block. There is no closing brace for this block before the end of the file. This may be caused by incorrect brace nesting elsewhere in this file.\n",
 		},
+		{
+			Name: "errors in working directories",
+			Error: tflint.WorkingDirErrors{
+				{Dir: "foo", Err: errors.New("an error occurred")},
+				{Dir: "bar", Err: hclDiags(`resource "foo" "bar" {`)},
+			},
+			Stdout: "main.tf:1:22: error - Unclosed configuration block. There is no closing brace for this block before the end of the file. This may be caused by incorrect brace nesting elsewhere in this file.\n",
+			Stderr: "an error occurred working_dir=foo\n",
+		},
 	}
 
 	for _, tc := range cases {
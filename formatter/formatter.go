@@ -9,6 +9,9 @@ import (
 	"github.com/terraform-linters/tflint/tflint"
 )
 
+// warningRuleName is the rule name used to report warnings in formats that have no place for non-issue results.
+const warningRuleName = "tflint_warning"
+
 // Formatter outputs appropriate results to stdout and stderr depending on the format
 type Formatter struct {
 	Stdout  io.Writer
@@ -16,6 +19,12 @@ type Formatter struct {
 	Format  string
 	Fix     bool
 	NoColor bool
+	// Unevaluated is a list of expressions that rules could not check.
+	// This is set with --explain-unknowns and output by Print in the JSON format.
+	// In other formats, it is printed to stderr by PrintUnevaluated.
+	Unevaluated []*tflint.UnevaluatedExpr
+
+	warnings hcl.Diagnostics
 }
 
 // Print outputs the given issues and errors according to configured format
@@ -38,6 +47,13 @@ func (f *Formatter) Print(issues tflint.Issues, err error, sources map[string][]
 	}
 }
 
+// AddWarnings adds the given warning diagnostics to be output by Print.
+// Since warnings are not issues, they are output in the same way as errors,
+// but they do not affect the exit status.
+func (f *Formatter) AddWarnings(diags hcl.Diagnostics) {
+	f.warnings = f.warnings.Extend(diags)
+}
+
 func toSeverity(lintType tflint.Severity) string {
 	switch lintType {
 	case sdk.ERROR:
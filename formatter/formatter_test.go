@@ -1,6 +1,10 @@
 package formatter
 
 import (
+	"bytes"
+	"testing"
+
+	hcl "github.com/hashicorp/hcl/v2"
 	sdk "github.com/terraform-linters/tflint-plugin-sdk/tflint"
 	"github.com/terraform-linters/tflint/tflint"
 )
@@ -22,3 +26,77 @@ func (r *testRule) Severity() tflint.Severity {
 func (r *testRule) Link() string {
 	return "https://github.com"
 }
+
+func Test_AddWarnings(t *testing.T) {
+	warnings := hcl.Diagnostics{
+		{
+			Severity: hcl.DiagWarning,
+			Summary:  "Module source has changed",
+			Detail:   "detail",
+			Subject: &hcl.Range{
+				Filename: "main.tf",
+				Start:    hcl.Pos{Line: 1, Column: 1, Byte: 0},
+				End:      hcl.Pos{Line: 1, Column: 13, Byte: 12},
+			},
+		},
+	}
+	sources := map[string][]byte{"main.tf": []byte(`module "foo" {}`)}
+
+	cases := []struct {
+		Name   string
+		Format string
+		Stdout string
+		Stderr string
+	}{
+		{
+			Name:   "default",
+			Format: "default",
+			Stderr: `Warning: Module source has changed
+
+  on main.tf line 1, in module "foo":
+   1: module "foo" {}
+
+detail
+
+`,
+		},
+		{
+			Name:   "json",
+			Format: "json",
+			Stdout: `{"issues":[],"errors":[{"summary":"Module source has changed","message":"detail","severity":"warning","range":{"filename":"main.tf","start":{"line":1,"column":1},"end":{"line":1,"column":13}}}]}`,
+		},
+		{
+			Name:   "checkstyle",
+			Format: "checkstyle",
+			Stdout: `<?xml version="1.0" encoding="UTF-8"?>
+<checkstyle>
+  <file name="main.tf">
+    <error rule="tflint_warning" line="1" column="1" severity="warning" message="Module source has changed. detail" link=""></error>
+  </file>
+</checkstyle>`,
+		},
+		{
+			Name:   "compact",
+			Format: "compact",
+			Stdout: "main.tf:1:1: warning - Module source has changed. detail\n",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+			formatter := &Formatter{Stdout: stdout, Stderr: stderr, Format: tc.Format, NoColor: true}
+
+			formatter.AddWarnings(warnings)
+			formatter.Print(tflint.Issues{}, nil, sources)
+
+			if stdout.String() != tc.Stdout {
+				t.Fatalf("expected=%s, stdout=%s", tc.Stdout, stdout.String())
+			}
+			if stderr.String() != tc.Stderr {
+				t.Fatalf("expected=%s, stderr=%s", tc.Stderr, stderr.String())
+			}
+		})
+	}
+}
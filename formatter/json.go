@@ -16,6 +16,12 @@ type JSONIssue struct {
 	Message string      `json:"message"`
 	Range   JSONRange   `json:"range"`
 	Callers []JSONRange `json:"callers"`
+	// Scenarios is a list of scenario names in which the issue was found.
+	// This is omitted if no scenarios are declared.
+	Scenarios []string `json:"scenarios,omitempty"`
+	// ModuleInstances is a list of module instance addresses in which the issue was found.
+	// This is omitted unless the issue was found in modules called with count/for_each.
+	ModuleInstances []string `json:"module_instances,omitempty"`
 }
 
 // JSONRule is a temporary structure for converting TFLint rules to JSON.
@@ -44,12 +50,27 @@ type JSONError struct {
 	Message  string     `json:"message"`
 	Severity string     `json:"severity"`
 	Range    *JSONRange `json:"range,omitempty"` // pointer so omitempty works
+	// WorkingDir is the working directory where the error occurred.
+	// This is only set in recursive mode with --keep-going.
+	WorkingDir string `json:"working_dir,omitempty"`
+}
+
+// JSONUnevaluatedExpr is a temporary structure for converting unevaluated expressions to JSON.
+type JSONUnevaluatedExpr struct {
+	Reason  string      `json:"reason"`
+	Range   JSONRange   `json:"range"`
+	Callers []JSONRange `json:"callers"`
+	Causes  []string    `json:"causes"`
+	Inputs  []string    `json:"inputs"`
 }
 
 // JSONOutput is a temporary structure for converting to JSON.
 type JSONOutput struct {
 	Issues []JSONIssue `json:"issues"`
 	Errors []JSONError `json:"errors"`
+	// Unevaluated is a list of expressions that rules could not check.
+	// This is omitted unless --explain-unknowns is set and any expressions could not be evaluated.
+	Unevaluated []JSONUnevaluatedExpr `json:"unevaluated,omitempty"`
 }
 
 func (f *Formatter) jsonPrint(issues tflint.Issues, appErr error) {
@@ -68,7 +89,9 @@ func (f *Formatter) jsonPrint(issues tflint.Issues, appErr error) {
 				Start:    JSONPos{Line: issue.Range.Start.Line, Column: issue.Range.Start.Column},
 				End:      JSONPos{Line: issue.Range.End.Line, Column: issue.Range.End.Column},
 			},
-			Callers: make([]JSONRange, len(issue.Callers)),
+			Callers:         make([]JSONRange, len(issue.Callers)),
+			Scenarios:       issue.Scenarios,
+			ModuleInstances: issue.ModuleInstances,
 		}
 		for i, caller := range issue.Callers {
 			ret.Issues[idx].Callers[i] = JSONRange{
@@ -79,27 +102,36 @@ func (f *Formatter) jsonPrint(issues tflint.Issues, appErr error) {
 		}
 	}
 
+	for _, expr := range f.Unevaluated {
+		unevaluated := JSONUnevaluatedExpr{
+			Reason:  string(expr.Reason),
+			Range:   jsonRange(expr.Range),
+			Callers: make([]JSONRange, len(expr.Callers)),
+			Causes:  append([]string{}, expr.Causes...),
+			Inputs:  append([]string{}, expr.Inputs...),
+		}
+		for i, caller := range expr.Callers {
+			unevaluated.Callers[i] = jsonRange(caller)
+		}
+		ret.Unevaluated = append(ret.Unevaluated, unevaluated)
+	}
+
+	if len(f.warnings) > 0 {
+		ret.Errors = append(ret.Errors, jsonErrors(f.warnings)...)
+	}
+
 	if appErr != nil {
-		var diags hcl.Diagnostics
-		if errors.As(appErr, &diags) {
-			ret.Errors = make([]JSONError, len(diags))
-			for idx, diag := range diags {
-				ret.Errors[idx] = JSONError{
-					Severity: fromHclSeverity(diag.Severity),
-					Summary:  diag.Summary,
-					Message:  diag.Detail,
-					Range: &JSONRange{
-						Filename: diag.Subject.Filename,
-						Start:    JSONPos{Line: diag.Subject.Start.Line, Column: diag.Subject.Start.Column},
-						End:      JSONPos{Line: diag.Subject.End.Line, Column: diag.Subject.End.Column},
-					},
+		var dirErrs tflint.WorkingDirErrors
+		if errors.As(appErr, &dirErrs) {
+			for _, dirErr := range dirErrs {
+				errs := jsonErrors(dirErr.Err)
+				for idx := range errs {
+					errs[idx].WorkingDir = dirErr.Dir
 				}
+				ret.Errors = append(ret.Errors, errs...)
 			}
 		} else {
-			ret.Errors = []JSONError{{
-				Severity: toSeverity(sdk.ERROR),
-				Message:  appErr.Error(),
-			}}
+			ret.Errors = append(ret.Errors, jsonErrors(appErr)...)
 		}
 	}
 
@@ -109,3 +141,45 @@ func (f *Formatter) jsonPrint(issues tflint.Issues, appErr error) {
 	}
 	fmt.Fprint(f.Stdout, string(out))
 }
+
+func jsonRange(rng hcl.Range) JSONRange {
+	return JSONRange{
+		Filename: rng.Filename,
+		Start:    JSONPos{Line: rng.Start.Line, Column: rng.Start.Column},
+		End:      JSONPos{Line: rng.End.Line, Column: rng.End.Column},
+	}
+}
+
+func jsonErrors(appErr error) []JSONError {
+	var pluginErrs tflint.PluginErrors
+	if errors.As(appErr, &pluginErrs) {
+		ret := []JSONError{}
+		for _, pluginErr := range pluginErrs {
+			ret = append(ret, jsonErrors(pluginErr)...)
+		}
+		return ret
+	}
+
+	var diags hcl.Diagnostics
+	if errors.As(appErr, &diags) {
+		ret := make([]JSONError, len(diags))
+		for idx, diag := range diags {
+			ret[idx] = JSONError{
+				Severity: fromHclSeverity(diag.Severity),
+				Summary:  diag.Summary,
+				Message:  diag.Detail,
+				Range: &JSONRange{
+					Filename: diag.Subject.Filename,
+					Start:    JSONPos{Line: diag.Subject.Start.Line, Column: diag.Subject.Start.Column},
+					End:      JSONPos{Line: diag.Subject.End.Line, Column: diag.Subject.End.Column},
+				},
+			}
+		}
+		return ret
+	}
+
+	return []JSONError{{
+		Severity: toSeverity(sdk.ERROR),
+		Message:  appErr.Error(),
+	}}
+}
@@ -12,16 +12,88 @@ import (
 
 func Test_jsonPrint(t *testing.T) {
 	cases := []struct {
-		Name   string
-		Issues tflint.Issues
-		Error  error
-		Stdout string
+		Name        string
+		Issues      tflint.Issues
+		Error       error
+		Unevaluated []*tflint.UnevaluatedExpr
+		Stdout      string
 	}{
 		{
 			Name:   "no issues",
 			Issues: tflint.Issues{},
 			Stdout: `{"issues":[],"errors":[]}`,
 		},
+		{
+			Name: "issues in scenarios",
+			Issues: tflint.Issues{
+				{
+					Rule:    &testRule{},
+					Message: "test",
+					Range: hcl.Range{
+						Filename: "test.tf",
+						Start:    hcl.Pos{Line: 1, Column: 1, Byte: 0},
+						End:      hcl.Pos{Line: 1, Column: 4, Byte: 3},
+					},
+					Scenarios: []string{"production", "staging"},
+				},
+			},
+			Stdout: `{"issues":[{"rule":{"name":"test_rule","severity":"error","link":"https://github.com"},"message":"test","range":{"filename":"test.tf","start":{"line":1,"column":1},"end":{"line":1,"column":4}},"callers":[],"scenarios":["production","staging"]}],"errors":[]}`,
+		},
+		{
+			Name: "issues in module instances",
+			Issues: tflint.Issues{
+				{
+					Rule:    &testRule{},
+					Message: "test",
+					Range: hcl.Range{
+						Filename: "module/test.tf",
+						Start:    hcl.Pos{Line: 1, Column: 1, Byte: 0},
+						End:      hcl.Pos{Line: 1, Column: 4, Byte: 3},
+					},
+					Callers: []hcl.Range{
+						{
+							Filename: "main.tf",
+							Start:    hcl.Pos{Line: 3, Column: 3, Byte: 0},
+							End:      hcl.Pos{Line: 3, Column: 6, Byte: 3},
+						},
+					},
+					ModuleInstances: []string{`module.foo["a"]`, `module.foo["b"]`},
+				},
+			},
+			Stdout: `{"issues":[{"rule":{"name":"test_rule","severity":"error","link":"https://github.com"},"message":"test","range":{"filename":"module/test.tf","start":{"line":1,"column":1},"end":{"line":1,"column":4}},"callers":[{"filename":"main.tf","start":{"line":3,"column":3},"end":{"line":3,"column":6}}],"module_instances":["module.foo[\"a\"]","module.foo[\"b\"]"]}],"errors":[]}`,
+		},
+		{
+			Name:   "unevaluated expressions",
+			Issues: tflint.Issues{},
+			Unevaluated: []*tflint.UnevaluatedExpr{
+				{
+					Range: hcl.Range{
+						Filename: "main.tf",
+						Start:    hcl.Pos{Line: 1, Column: 9, Byte: 8},
+						End:      hcl.Pos{Line: 1, Column: 19, Byte: 18},
+					},
+					Reason: tflint.UnevaluatedUnknown,
+					Causes: []string{"local.name"},
+					Inputs: []string{"var.name"},
+				},
+				{
+					Range: hcl.Range{
+						Filename: "main.tf",
+						Start:    hcl.Pos{Line: 5, Column: 3, Byte: 0},
+						End:      hcl.Pos{Line: 5, Column: 9, Byte: 6},
+					},
+					Reason: tflint.UnevaluatedSensitive,
+					Callers: []hcl.Range{
+						{
+							Filename: "module/main.tf",
+							Start:    hcl.Pos{Line: 3, Column: 11, Byte: 0},
+							End:      hcl.Pos{Line: 3, Column: 23, Byte: 12},
+						},
+					},
+				},
+			},
+			Stdout: `{"issues":[],"errors":[],"unevaluated":[{"reason":"unknown","range":{"filename":"main.tf","start":{"line":1,"column":9},"end":{"line":1,"column":19}},"callers":[],"causes":["local.name"],"inputs":["var.name"]},{"reason":"sensitive","range":{"filename":"main.tf","start":{"line":5,"column":3},"end":{"line":5,"column":9}},"callers":[{"filename":"module/main.tf","start":{"line":3,"column":11},"end":{"line":3,"column":23}}],"causes":[],"inputs":[]}]}`,
+		},
 		{
 			Name:   "error",
 			Error:  fmt.Errorf("Failed to work; %w", errors.New("I don't feel like working")),
@@ -46,12 +118,47 @@ func Test_jsonPrint(t *testing.T) {
 			),
 			Stdout: `{"issues":[],"errors":[{"summary":"summary","message":"detail","severity":"warning","range":{"filename":"filename","start":{"line":1,"column":1},"end":{"line":5,"column":1}}}]}`,
 		},
+		{
+			Name: "errors in working directories",
+			Error: tflint.WorkingDirErrors{
+				{Dir: "foo", Err: errors.New("I don't feel like working")},
+				{
+					Dir: "bar",
+					Err: hcl.Diagnostics{
+						&hcl.Diagnostic{
+							Severity: hcl.DiagError,
+							Summary:  "summary",
+							Detail:   "detail",
+							Subject: &hcl.Range{
+								Filename: "bar/main.tf",
+								Start:    hcl.Pos{Line: 1, Column: 1, Byte: 0},
+								End:      hcl.Pos{Line: 1, Column: 4, Byte: 3},
+							},
+						},
+					},
+				},
+			},
+			Stdout: `{"issues":[],"errors":[{"message":"I don't feel like working","severity":"error","working_dir":"foo"},{"summary":"summary","message":"detail","severity":"error","range":{"filename":"bar/main.tf","start":{"line":1,"column":1},"end":{"line":1,"column":4}},"working_dir":"bar"}]}`,
+		},
+		{
+			Name: "errors in plugins",
+			Error: tflint.WorkingDirErrors{
+				{
+					Dir: "foo",
+					Err: tflint.PluginErrors{
+						{Name: "aws", Err: errors.New(`Plugin "aws" crashed`)},
+						{Name: "google", Err: errors.New(`Plugin "google" was killed`)},
+					},
+				},
+			},
+			Stdout: `{"issues":[],"errors":[{"message":"Failed to check \"aws\" ruleset; Plugin \"aws\" crashed","severity":"error","working_dir":"foo"},{"message":"Failed to check \"google\" ruleset; Plugin \"google\" was killed","severity":"error","working_dir":"foo"}]}`,
+		},
 	}
 
 	for _, tc := range cases {
 		stdout := &bytes.Buffer{}
 		stderr := &bytes.Buffer{}
-		formatter := &Formatter{Stdout: stdout, Stderr: stderr, Format: "json"}
+		formatter := &Formatter{Stdout: stdout, Stderr: stderr, Format: "json", Unevaluated: tc.Unevaluated}
 
 		formatter.Print(tc.Issues, tc.Error, map[string][]byte{})
 
@@ -3,6 +3,7 @@ package formatter
 import (
 	"encoding/xml"
 	"fmt"
+	"strings"
 
 	"github.com/jstemmer/go-junit-report/formatter"
 	"github.com/terraform-linters/tflint/tflint"
@@ -14,20 +15,25 @@ func (f *Formatter) junitPrint(issues tflint.Issues, appErr error, sources map[s
 	cases := make([]formatter.JUnitTestCase, len(issues))
 
 	for i, issue := range issues.Sort() {
+		contents := fmt.Sprintf(
+			"%s: %s\nRule: %s\nRange: %s",
+			issue.Rule.Severity(),
+			issue.Message,
+			issue.Rule.Name(),
+			issue.Range,
+		)
+		if len(issue.Scenarios) > 0 {
+			contents += fmt.Sprintf("\nScenarios: %s", strings.Join(issue.Scenarios, ", "))
+		}
+
 		cases[i] = formatter.JUnitTestCase{
 			Name:      issue.Rule.Name(),
 			Classname: issue.Range.Filename,
 			Time:      "0",
 			Failure: &formatter.JUnitFailure{
-				Message: fmt.Sprintf("%s: %s", issue.Range, issue.Message),
-				Type:    issue.Rule.Severity().String(),
-				Contents: fmt.Sprintf(
-					"%s: %s\nRule: %s\nRange: %s",
-					issue.Rule.Severity(),
-					issue.Message,
-					issue.Rule.Name(),
-					issue.Range,
-				),
+				Message:  fmt.Sprintf("%s: %s", issue.Range, issue.Message),
+				Type:     issue.Rule.Severity().String(),
+				Contents: contents,
 			},
 		}
 	}
@@ -50,6 +56,7 @@ func (f *Formatter) junitPrint(issues tflint.Issues, appErr error, sources map[s
 	fmt.Fprint(f.Stdout, xml.Header)
 	fmt.Fprint(f.Stdout, string(out))
 
+	f.prettyPrintWarnings(sources)
 	if appErr != nil {
 		f.prettyPrintErrors(appErr, sources)
 	}
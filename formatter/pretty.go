@@ -29,6 +29,7 @@ func (f *Formatter) prettyPrint(issues tflint.Issues, err error, sources map[str
 		}
 	}
 
+	f.prettyPrintWarnings(sources)
 	if err != nil {
 		f.prettyPrintErrors(err, sources)
 	}
@@ -95,6 +96,10 @@ func (f *Formatter) prettyPrintIssueWithSource(issue *tflint.Issue, sources map[
 		}
 	}
 
+	if len(issue.Scenarios) > 0 {
+		fmt.Fprintf(f.Stdout, "\nScenarios: %s\n", strings.Join(issue.Scenarios, ", "))
+	}
+
 	if issue.Rule.Link() != "" {
 		fmt.Fprintf(f.Stdout, "\nReference: %s\n", issue.Rule.Link())
 	}
@@ -103,6 +108,14 @@ func (f *Formatter) prettyPrintIssueWithSource(issue *tflint.Issue, sources map[
 }
 
 func (f *Formatter) prettyPrintErrors(err error, sources map[string][]byte) {
+	var dirErrs tflint.WorkingDirErrors
+	if errors.As(err, &dirErrs) {
+		for _, dirErr := range dirErrs {
+			f.prettyPrintErrors(dirErr, sources)
+		}
+		return
+	}
+
 	var diags hcl.Diagnostics
 	if errors.As(err, &diags) {
 		fmt.Fprintf(f.Stderr, "%s:\n\n", err)
@@ -114,6 +127,15 @@ func (f *Formatter) prettyPrintErrors(err error, sources map[string][]byte) {
 	}
 }
 
+func (f *Formatter) prettyPrintWarnings(sources map[string][]byte) {
+	if len(f.warnings) == 0 {
+		return
+	}
+
+	writer := hcl.NewDiagnosticTextWriter(f.Stderr, parseSources(sources), 0, !f.NoColor)
+	_ = writer.WriteDiagnostics(f.warnings)
+}
+
 func parseSources(sources map[string][]byte) map[string]*hcl.File {
 	ret := map[string]*hcl.File{}
 	parser := hclparse.NewParser()
@@ -62,6 +62,17 @@ func (f *Formatter) sarifPrint(issues tflint.Issues, appErr error) {
 		if location != nil {
 			result.WithLocation(sarif.NewLocationWithPhysicalLocation(location))
 		}
+
+		properties := sarif.Properties{}
+		if len(issue.Scenarios) > 0 {
+			properties["scenarios"] = issue.Scenarios
+		}
+		if len(issue.ModuleInstances) > 0 {
+			properties["moduleInstances"] = issue.ModuleInstances
+		}
+		if len(properties) > 0 {
+			result.WithProperties(properties)
+		}
 	}
 
 	errRun := sarif.NewRun("tflint-errors", "https://github.com/terraform-linters/tflint")
@@ -69,31 +80,18 @@ func (f *Formatter) sarifPrint(issues tflint.Issues, appErr error) {
 
 	report.AddRun(errRun)
 
+	if len(f.warnings) > 0 {
+		sarifAddErrors(errRun, f.warnings, "")
+	}
+
 	if appErr != nil {
-		var diags hcl.Diagnostics
-		if errors.As(appErr, &diags) {
-			for _, diag := range diags {
-				location := sarif.NewPhysicalLocation().
-					WithArtifactLocation(sarif.NewSimpleArtifactLocation(filepath.ToSlash(diag.Subject.Filename))).
-					WithRegion(
-						sarif.NewRegion().
-							WithByteOffset(diag.Subject.Start.Byte).
-							WithByteLength(diag.Subject.End.Byte - diag.Subject.Start.Byte).
-							WithStartLine(diag.Subject.Start.Line).
-							WithStartColumn(diag.Subject.Start.Column).
-							WithEndLine(diag.Subject.End.Line).
-							WithEndColumn(diag.Subject.End.Column),
-					)
-
-				errRun.AddResult(diag.Summary).
-					WithLevel(fromHclSeverity(diag.Severity)).
-					WithLocation(sarif.NewLocationWithPhysicalLocation(location)).
-					WithMessage(sarif.NewTextMessage(diag.Detail))
+		var dirErrs tflint.WorkingDirErrors
+		if errors.As(appErr, &dirErrs) {
+			for _, dirErr := range dirErrs {
+				sarifAddErrors(errRun, dirErr.Err, dirErr.Dir)
 			}
 		} else {
-			errRun.AddResult("application_error").
-				WithLevel("error").
-				WithMessage(sarif.NewTextMessage(appErr.Error()))
+			sarifAddErrors(errRun, appErr, "")
 		}
 	}
 
@@ -102,3 +100,41 @@ func (f *Formatter) sarifPrint(issues tflint.Issues, appErr error) {
 		panic(stdoutErr)
 	}
 }
+
+// sarifAddErrors adds the passed error to the run as results.
+// If the working directory is passed, it is set as a property of each result.
+func sarifAddErrors(run *sarif.Run, appErr error, workingDir string) {
+	var results []*sarif.Result
+
+	var diags hcl.Diagnostics
+	if errors.As(appErr, &diags) {
+		for _, diag := range diags {
+			location := sarif.NewPhysicalLocation().
+				WithArtifactLocation(sarif.NewSimpleArtifactLocation(filepath.ToSlash(diag.Subject.Filename))).
+				WithRegion(
+					sarif.NewRegion().
+						WithByteOffset(diag.Subject.Start.Byte).
+						WithByteLength(diag.Subject.End.Byte - diag.Subject.Start.Byte).
+						WithStartLine(diag.Subject.Start.Line).
+						WithStartColumn(diag.Subject.Start.Column).
+						WithEndLine(diag.Subject.End.Line).
+						WithEndColumn(diag.Subject.End.Column),
+				)
+
+			results = append(results, run.AddResult(diag.Summary).
+				WithLevel(fromHclSeverity(diag.Severity)).
+				WithLocation(sarif.NewLocationWithPhysicalLocation(location)).
+				WithMessage(sarif.NewTextMessage(diag.Detail)))
+		}
+	} else {
+		results = append(results, run.AddResult("application_error").
+			WithLevel("error").
+			WithMessage(sarif.NewTextMessage(appErr.Error())))
+	}
+
+	if workingDir != "" {
+		for _, result := range results {
+			result.WithProperties(sarif.Properties{"workingDir": workingDir})
+		}
+	}
+}
@@ -409,6 +409,47 @@ func Test_sarifPrint(t *testing.T) {
       ]
     }
   ]
+}`, tflint.Version, tflint.Version),
+		},
+		{
+			Name:  "errors in working directories",
+			Error: tflint.WorkingDirErrors{{Dir: "foo", Err: errors.New("I don't feel like working")}},
+			Stdout: fmt.Sprintf(`{
+  "version": "2.1.0",
+  "$schema": "https://json.schemastore.org/sarif-2.1.0-rtm.5.json",
+  "runs": [
+    {
+      "tool": {
+        "driver": {
+          "name": "tflint",
+          "version": "%s",
+          "informationUri": "https://github.com/terraform-linters/tflint"
+        }
+      },
+      "results": []
+    },
+    {
+      "tool": {
+        "driver": {
+          "name": "tflint-errors",
+          "version": "%s",
+          "informationUri": "https://github.com/terraform-linters/tflint"
+        }
+      },
+      "results": [
+        {
+          "ruleId": "application_error",
+          "level": "error",
+          "message": {
+            "text": "I don't feel like working"
+          },
+          "properties": {
+            "workingDir": "foo"
+          }
+        }
+      ]
+    }
+  ]
 }`, tflint.Version, tflint.Version),
 		},
 		{
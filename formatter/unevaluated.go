@@ -0,0 +1,68 @@
+package formatter
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/terraform-linters/tflint/tflint"
+)
+
+// PrintUnevaluated prints expressions that rules could not check, and a summary
+// of root module variables that would make the most expressions evaluable.
+// This is printed to stderr so as not to break the output. In the JSON format, nothing is printed
+// because the expressions are included in the output as "unevaluated".
+func (f *Formatter) PrintUnevaluated(exprs []*tflint.UnevaluatedExpr) {
+	if f.Format == "json" {
+		return
+	}
+	if len(exprs) == 0 {
+		fmt.Fprint(f.Stderr, "All expressions were evaluated.\n")
+		return
+	}
+
+	fmt.Fprintf(f.Stderr, "%d expression(s) could not be evaluated and were skipped by rules:\n\n", len(exprs))
+
+	counts := map[string]int{}
+	for _, expr := range exprs {
+		fmt.Fprintf(
+			f.Stderr,
+			"%s:%d:%d: %s value",
+			expr.Range.Filename,
+			expr.Range.Start.Line,
+			expr.Range.Start.Column,
+			expr.Reason,
+		)
+		if len(expr.Causes) > 0 {
+			fmt.Fprintf(f.Stderr, " (caused by %s)", strings.Join(expr.Causes, ", "))
+		}
+		fmt.Fprint(f.Stderr, "\n")
+		for _, caller := range expr.Callers {
+			fmt.Fprintf(f.Stderr, "  called at %s:%d:%d\n", caller.Filename, caller.Start.Line, caller.Start.Column)
+		}
+
+		for _, input := range expr.Inputs {
+			counts[input]++
+		}
+	}
+
+	if len(counts) == 0 {
+		return
+	}
+
+	inputs := make([]string, 0, len(counts))
+	for input := range counts {
+		inputs = append(inputs, input)
+	}
+	sort.Slice(inputs, func(i, j int) bool {
+		if counts[inputs[i]] != counts[inputs[j]] {
+			return counts[inputs[i]] > counts[inputs[j]]
+		}
+		return inputs[i] < inputs[j]
+	})
+
+	fmt.Fprint(f.Stderr, "\nSetting the following variables with --var or tfvars files may allow rules to check more expressions:\n\n")
+	for _, input := range inputs {
+		fmt.Fprintf(f.Stderr, "  %s: %d expression(s)\n", input, counts[input])
+	}
+}
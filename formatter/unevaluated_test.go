@@ -0,0 +1,99 @@
+package formatter
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/terraform-linters/tflint/tflint"
+)
+
+func Test_PrintUnevaluated(t *testing.T) {
+	cases := []struct {
+		Name   string
+		Format string
+		Exprs  []*tflint.UnevaluatedExpr
+		Stderr string
+	}{
+		{
+			Name:   "no expressions",
+			Exprs:  []*tflint.UnevaluatedExpr{},
+			Stderr: "All expressions were evaluated.\n",
+		},
+		{
+			Name: "expressions",
+			Exprs: []*tflint.UnevaluatedExpr{
+				{
+					Range:  hcl.Range{Filename: "main.tf", Start: hcl.Pos{Line: 1, Column: 9}},
+					Reason: tflint.UnevaluatedUnknown,
+					Causes: []string{"local.name"},
+					Inputs: []string{"var.name", "var.region"},
+				},
+				{
+					Range:  hcl.Range{Filename: "main.tf", Start: hcl.Pos{Line: 5, Column: 3}},
+					Reason: tflint.UnevaluatedNull,
+					Causes: []string{"var.region"},
+					Inputs: []string{"var.region"},
+				},
+				{
+					Range:  hcl.Range{Filename: "main.tf", Start: hcl.Pos{Line: 9, Column: 3}},
+					Reason: tflint.UnevaluatedSensitive,
+					Causes: []string{"var.password"},
+				},
+				{
+					Range:  hcl.Range{Filename: "main.tf", Start: hcl.Pos{Line: 12, Column: 3}},
+					Reason: tflint.UnevaluatedUnknown,
+				},
+				{
+					Range:   hcl.Range{Filename: "main.tf", Start: hcl.Pos{Line: 15, Column: 1}},
+					Reason:  tflint.UnevaluatedUnknown,
+					Callers: []hcl.Range{{Filename: "module/main.tf", Start: hcl.Pos{Line: 3, Column: 11}}},
+					Causes:  []string{"var.input"},
+				},
+			},
+			Stderr: `5 expression(s) could not be evaluated and were skipped by rules:
+
+main.tf:1:9: unknown value (caused by local.name)
+main.tf:5:3: null value (caused by var.region)
+main.tf:9:3: sensitive value (caused by var.password)
+main.tf:12:3: unknown value
+main.tf:15:1: unknown value (caused by var.input)
+  called at module/main.tf:3:11
+
+Setting the following variables with --var or tfvars files may allow rules to check more expressions:
+
+  var.region: 2 expression(s)
+  var.name: 1 expression(s)
+`,
+		},
+		{
+			Name:   "json",
+			Format: "json",
+			Exprs: []*tflint.UnevaluatedExpr{
+				{
+					Range:  hcl.Range{Filename: "main.tf", Start: hcl.Pos{Line: 1, Column: 9}},
+					Reason: tflint.UnevaluatedUnknown,
+				},
+			},
+			Stderr: "",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+			formatter := &Formatter{Stdout: stdout, Stderr: stderr, Format: tc.Format}
+
+			formatter.PrintUnevaluated(tc.Exprs)
+
+			if stdout.String() != "" {
+				t.Errorf("expected no stdout, but got %s", stdout.String())
+			}
+			if diff := cmp.Diff(tc.Stderr, stderr.String()); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,7 @@
+resource "aws_instance" "main" {
+  instance_type = "t2.micro"
+}
+
+resource "aws_instance" "workspace" {
+  instance_type = "t2.${terraform.workspace}"
+}
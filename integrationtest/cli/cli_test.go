@@ -84,6 +84,20 @@ func TestIntegration(t *testing.T) {
 			status:  cmd.ExitCodeError,
 			stderr:  "Failed to load configurations;",
 		},
+		{
+			name:    "--fix with --archive",
+			command: "./tflint --fix --archive module.zip",
+			dir:     "no_issues",
+			status:  cmd.ExitCodeError,
+			stderr:  "cannot use --fix with --archive because the archive is read-only",
+		},
+		{
+			name:    "unsupported archive",
+			command: "./tflint --archive module.rar",
+			dir:     "no_issues",
+			status:  cmd.ExitCodeError,
+			stderr:  `Failed to open the archive; "module.rar" is not a supported archive. Supported formats are: zip, tar, tar.gz`,
+		},
 		{
 			name:    "removed --debug options",
 			command: "./tflint --debug",
@@ -393,6 +407,85 @@ func TestIntegration(t *testing.T) {
 			status:  cmd.ExitCodeIssuesFound,
 			stdout:  fmt.Sprintf("%s (aws_instance_example_type)", color.New(color.Bold).Sprint("instance type is m5.2xlarge")),
 		},
+		{
+			name:    "--profile with --recursive",
+			command: "./tflint --recursive --keep-going --profile",
+			dir:     "keep_going",
+			status:  cmd.ExitCodePartialFailure,
+			stdout:  fmt.Sprintf("%s (aws_instance_example_type)", color.New(color.Bold).Sprint("instance type is m5.2xlarge")),
+			stderr:  "check (dir=ok, plugin=testing, module=root)",
+		},
+		{
+			name:    "--profile with loading errors",
+			command: "./tflint --profile",
+			dir:     "load_errors",
+			status:  cmd.ExitCodeError,
+			stderr:  "load Terraform configurations",
+		},
+		{
+			name:    "--yes without --plugins-prune",
+			command: "./tflint --yes",
+			dir:     "no_issues",
+			status:  cmd.ExitCodeError,
+			stderr:  "--yes can only be used with --plugins-prune",
+		},
+		{
+			name:    "--keep-going without --recursive",
+			command: "./tflint --keep-going",
+			dir:     "keep_going",
+			status:  cmd.ExitCodeError,
+			stderr:  "--keep-going can only be used with --recursive",
+		},
+		{
+			name:    "--recursive without --keep-going",
+			command: "./tflint --recursive",
+			dir:     "keep_going",
+			status:  cmd.ExitCodeError,
+			stderr:  "working_dir=broken",
+		},
+		{
+			name:    "--recursive and --keep-going",
+			command: "./tflint --recursive --keep-going",
+			dir:     "keep_going",
+			status:  cmd.ExitCodePartialFailure,
+			stdout:  fmt.Sprintf("%s (aws_instance_example_type)", color.New(color.Bold).Sprint("instance type is m5.2xlarge")),
+			stderr:  "working_dir=broken",
+		},
+		{
+			name:    "--all-workspaces",
+			command: "./tflint --all-workspaces --format compact",
+			dir:     "all_workspaces",
+			status:  cmd.ExitCodeIssuesFound,
+			stdout:  "main.tf:2:19: Error - instance type is t2.micro (aws_instance_example_type) (scenarios: default, production, staging)",
+		},
+		{
+			name:    "--all-workspaces propagates the workspace",
+			command: "./tflint --all-workspaces --format compact",
+			dir:     "all_workspaces",
+			status:  cmd.ExitCodeIssuesFound,
+			stdout:  "main.tf:6:19: Error - instance type is t2.production (aws_instance_example_type) (scenarios: production)",
+		},
+		{
+			name:    "--all-workspaces with --workspace",
+			command: "./tflint --all-workspaces --workspace=production",
+			dir:     "all_workspaces",
+			status:  cmd.ExitCodeError,
+			stderr:  "cannot use --workspace and --all-workspaces at the same time",
+		},
+		{
+			name:    "scenarios",
+			command: "./tflint --format json",
+			dir:     "scenarios",
+			status:  cmd.ExitCodeIssuesFound,
+			stdout:  `"message":"instance type is t2.micro","range":{"filename":"main.tf","start":{"line":7,"column":19},"end":{"line":7,"column":29}},"callers":[],"scenarios":["small","large"]`,
+		},
+		{
+			name:    "scenarios with --all-workspaces",
+			command: "./tflint --all-workspaces --format compact",
+			dir:     "scenarios",
+			status:  cmd.ExitCodeIssuesFound,
+			stdout:  "main.tf:11:19: Error - instance type is t2.large.production (aws_instance_example_type) (scenarios: large/production)",
+		},
 	}
 
 	dir, _ := os.Getwd()
@@ -0,0 +1,11 @@
+plugin "testing" {
+  enabled = true
+}
+
+scenario "small" {
+  variables = ["size=micro"]
+}
+
+scenario "large" {
+  varfile = ["large.tfvars"]
+}
@@ -0,0 +1,12 @@
+variable "size" {
+  type    = string
+  default = "micro"
+}
+
+resource "aws_instance" "main" {
+  instance_type = "t2.micro"
+}
+
+resource "aws_instance" "scenario" {
+  instance_type = "t2.${var.size}.${terraform.workspace}"
+}
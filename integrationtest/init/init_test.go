@@ -8,6 +8,7 @@ import (
 	"testing"
 
 	"github.com/terraform-linters/tflint/cmd"
+	"github.com/terraform-linters/tflint/plugin"
 	"github.com/terraform-linters/tflint/tflint"
 )
 
@@ -30,6 +31,9 @@ func TestIntegration(t *testing.T) {
 	os.Setenv("TFLINT_PLUGIN_DIR", pluginDir)
 	defer os.Setenv("TFLINT_PLUGIN_DIR", "")
 
+	lockFile := filepath.Join(dir, plugin.LockFileName)
+	defer os.Remove(lockFile)
+
 	// Init on the current directory
 	if err := os.Chdir(dir); err != nil {
 		t.Fatal(err)
@@ -53,6 +57,21 @@ func TestIntegration(t *testing.T) {
 		t.Fatalf("Expected to contain an installed log, but did not: stdout=%s, stderr=%s", outStream, errStream)
 	}
 
+	lock, err := plugin.LoadLockFile(lockFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lock == nil {
+		t.Fatal("Expected to write the lock file, but did not")
+	}
+	locked := lock.Plugin("aws")
+	if locked == nil || locked.Source != "github.com/terraform-linters/tflint-ruleset-aws" || locked.Version != "0.21.1" {
+		t.Fatalf("Expected to lock the aws plugin, but got %#v", locked)
+	}
+	if !locked.HasBinaryChecksum() {
+		t.Fatal("Expected to lock the checksum of the binary, but did not")
+	}
+
 	cli.Run([]string{"./tflint", "--init"})
 	if !strings.Contains(outStream.String(), `Plugin "aws" is already installed`) {
 		t.Fatalf("Expected to contain an already installed log, but did not: stdout=%s, stderr=%s", outStream, errStream)
@@ -63,6 +82,25 @@ func TestIntegration(t *testing.T) {
 		t.Fatalf("Expected to contain an plugin version log, but did not: stdout=%s, stderr=%s", outStream, errStream)
 	}
 
+	// Plugins that do not match the lock file are not launched
+	original, err := os.ReadFile(lockFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for platform := range locked.BinaryChecksums {
+		locked.BinaryChecksums[platform] = "sha256:0000000000000000000000000000000000000000000000000000000000000000"
+	}
+	if err := lock.Write(lockFile); err != nil {
+		t.Fatal(err)
+	}
+	cli.Run([]string{"./tflint"})
+	if !strings.Contains(errStream.String(), `Plugin "aws" does not match .tflint.lock.hcl`) {
+		t.Fatalf("Expected to contain a checksum error, but did not: stdout=%s, stderr=%s", outStream, errStream)
+	}
+	if err := os.WriteFile(lockFile, original, 0644); err != nil {
+		t.Fatal(err)
+	}
+
 	// Init with --chdir
 	if err := os.Chdir(current); err != nil {
 		t.Fatal(err)
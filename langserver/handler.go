@@ -167,6 +167,10 @@ func (h *handler) inspect() (map[string][]lsp.Diagnostic, error) {
 	if err != nil {
 		return ret, fmt.Errorf("Failed to prepare loading: %w", err)
 	}
+	loader.SetLanguage(h.config.Language)
+	loader.SetModuleMirror(h.config.ModuleMirror)
+	loader.SetStaleModuleManifest(h.config.StaleModuleManifest)
+	loader.SetInputVariables(h.config.Varfiles, h.config.Variables)
 
 	configs, diags := loader.LoadConfig(".", h.config.CallModuleType)
 	if diags.HasErrors() {
@@ -176,9 +180,12 @@ func (h *handler) inspect() (map[string][]lsp.Diagnostic, error) {
 	if diags.HasErrors() {
 		return ret, fmt.Errorf("Failed to load configurations: %w", diags)
 	}
+	for path, file := range configs.Module.TestFiles {
+		files[path] = file
+	}
 	annotations := map[string]tflint.Annotations{}
 	for path, file := range files {
-		if !strings.HasSuffix(path, ".tf") {
+		if !terraform.IsNativeSyntaxFile(path) {
 			continue
 		}
 		ants, lexDiags := tflint.NewAnnotations(path, file)
@@ -228,7 +235,7 @@ func (h *handler) inspect() (map[string][]lsp.Diagnostic, error) {
 			return ret, fmt.Errorf(`Failed to apply config to "%s" plugin`, name)
 		}
 		for _, runner := range runners {
-			err = ruleset.Check(plugin.NewGRPCServer(runner, runners[len(runners)-1], loader.Files(), h.clientSDKVersions[name]))
+			err = h.plugin.Check(context.Background(), name, plugin.NewGRPCServer(runner, runners[len(runners)-1], loader.Files(), h.clientSDKVersions[name]))
 			if err != nil {
 				return ret, fmt.Errorf("Failed to check ruleset: %w", err)
 			}
@@ -242,25 +249,28 @@ func (h *handler) inspect() (map[string][]lsp.Diagnostic, error) {
 	}
 	h.diagsPaths = []string{}
 
+	issues := tflint.Issues{}
 	for _, runner := range runners {
-		for _, issue := range runner.LookupIssues() {
-			path := filepath.Join(h.rootDir, issue.Range.Filename)
-			h.diagsPaths = append(h.diagsPaths, path)
-
-			diag := lsp.Diagnostic{
-				Message:  issue.Message,
-				Severity: toLSPSeverity(issue.Rule.Severity()),
-				Range: lsp.Range{
-					Start: lsp.Position{Line: issue.Range.Start.Line - 1, Character: issue.Range.Start.Column - 1},
-					End:   lsp.Position{Line: issue.Range.End.Line - 1, Character: issue.Range.End.Column - 1},
-				},
-			}
+		issues = append(issues, runner.LookupIssues()...)
+	}
+	// Issues found in multiple module instances are published only once
+	for _, issue := range issues.Merge() {
+		path := filepath.Join(h.rootDir, issue.Range.Filename)
+		h.diagsPaths = append(h.diagsPaths, path)
+
+		diag := lsp.Diagnostic{
+			Message:  issue.Message,
+			Severity: toLSPSeverity(issue.Rule.Severity()),
+			Range: lsp.Range{
+				Start: lsp.Position{Line: issue.Range.Start.Line - 1, Character: issue.Range.Start.Column - 1},
+				End:   lsp.Position{Line: issue.Range.End.Line - 1, Character: issue.Range.End.Column - 1},
+			},
+		}
 
-			if ret[path] == nil {
-				ret[path] = []lsp.Diagnostic{diag}
-			} else {
-				ret[path] = append(ret[path], diag)
-			}
+		if ret[path] == nil {
+			ret[path] = []lsp.Diagnostic{diag}
+		} else {
+			ret[path] = append(ret[path], diag)
 		}
 	}
 
@@ -23,14 +23,26 @@ import (
 func Discovery(config *tflint.Config) (*Plugin, error) {
 	clients := map[string]*plugin.Client{}
 	rulesets := map[string]*host2plugin.Client{}
+	buffers := map[string]*outputBuffer{}
+	paths := map[string]string{}
+
+	captureOutput()
+
+	lock, err := LoadLockFile(LockFileName)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to load %s; %w", LockFileName, err)
+	}
 
 	for _, pluginCfg := range config.Plugins {
-		installCfg := NewInstallConfig(config, pluginCfg)
+		installCfg := resolveInstallConfig(config, pluginCfg, lock)
 		pluginPath, err := FindPluginPath(installCfg)
 		var cmd *exec.Cmd
 		if os.IsNotExist(err) {
 			if pluginCfg.Name == "terraform" && installCfg.ManuallyInstalled() {
 				log.Print(`[INFO] Plugin "terraform" is not installed, but the bundled plugin is available.`)
+				if len(pluginCfg.Args) > 0 {
+					return nil, errors.New(`Plugin "terraform": "args" cannot be used with the bundled plugin`)
+				}
 				self, err := os.Executable()
 				if err != nil {
 					return nil, err
@@ -38,23 +50,40 @@ func Discovery(config *tflint.Config) (*Plugin, error) {
 				cmd = exec.Command(self, "--act-as-bundled-plugin")
 			} else {
 				if installCfg.ManuallyInstalled() {
-					pluginDir, err := getPluginDir(config)
+					pluginDirs, err := getPluginDirs(config)
 					if err != nil {
 						return nil, err
 					}
-					return nil, fmt.Errorf(`Plugin "%s" not found in %s`, pluginCfg.Name, pluginDir)
+					return nil, fmt.Errorf(`Plugin "%s" not found in %s`, pluginCfg.Name, strings.Join(pluginDirs, ", "))
 				}
 				return nil, fmt.Errorf(`Plugin "%s" not found. Did you run "tflint --init"?`, pluginCfg.Name)
 			}
 		} else {
-			cmd = exec.Command(pluginPath)
+			cmd = exec.Command(pluginPath, pluginCfg.Args...)
 		}
 
 		if pluginCfg.Enabled {
-			log.Printf(`[INFO] Plugin "%s" found`, pluginCfg.Name)
+			if pluginPath != "" {
+				log.Printf(`[INFO] Plugin "%s" found in %s`, pluginCfg.Name, pluginPath)
+				paths[pluginCfg.Name] = pluginPath
+			} else {
+				log.Printf(`[INFO] Plugin "%s" found`, pluginCfg.Name)
+			}
+
+			// Plugins installed by "tflint --init" are verified if the lock file exists
+			if lock != nil && pluginPath != "" && !installCfg.ManuallyInstalled() {
+				if err := verifyLockedPlugin(lock, pluginCfg, pluginPath); err != nil {
+					return nil, err
+				}
+			}
 
+			launcher, err := launcherCommand(cmd, pluginEnv(config, pluginCfg))
+			if err != nil {
+				return nil, err
+			}
+			buffers[pluginCfg.Name] = outputs.register(outputName(cmd, launcher))
 			client := host2plugin.NewClient(&host2plugin.ClientOpts{
-				Cmd: cmd,
+				Cmd: launcher,
 			})
 			rpcClient, err := client.Client()
 			if err != nil {
@@ -73,49 +102,113 @@ func Discovery(config *tflint.Config) (*Plugin, error) {
 		}
 	}
 
-	return &Plugin{RuleSets: rulesets, clients: clients}, nil
+	return &Plugin{RuleSets: rulesets, clients: clients, outputs: buffers, paths: paths}, nil
+}
+
+// verifyLockedPlugin verifies that the plugin matches the lock file before launching it.
+func verifyLockedPlugin(lock *LockFile, config *tflint.PluginConfig, path string) error {
+	locked := lock.Plugin(config.Name)
+	if locked == nil {
+		return fmt.Errorf(`Plugin "%s" is not recorded in %s. Run "tflint --init" to update the lock file`, config.Name, LockFileName)
+	}
+	if !locked.Matches(config) {
+		return fmt.Errorf(
+			`Plugin "%s" is locked to version %s (source: %s), but version %s (source: %s) is configured. Run "tflint --init --upgrade" to update the lock file`,
+			config.Name,
+			locked.Version,
+			locked.Source,
+			config.Version,
+			config.Source,
+		)
+	}
+	if err := locked.VerifyBinary(path); err != nil {
+		return fmt.Errorf(`Plugin "%s" does not match %s; %w`, config.Name, LockFileName, err)
+	}
+	log.Printf(`[DEBUG] Plugin "%s" matched the lock file`, config.Name)
+	return nil
 }
 
 // FindPluginPath returns the plugin binary path.
+// Plugin directories are searched in order, and the first plugin found is returned.
+// If the version is constraints, it returns the newest installed version that satisfies them.
 func FindPluginPath(config *InstallConfig) (string, error) {
-	dir, err := getPluginDir(config.globalConfig)
+	dirs, err := getPluginDirs(config.globalConfig)
 	if err != nil {
 		return "", err
 	}
 
-	path, err := findPluginPath(filepath.Join(dir, config.InstallPath()))
-	if err != nil {
-		return "", err
+	for _, dir := range dirs {
+		var path string
+		if config.VersionConstraints() != nil {
+			path, err = findConstrainedPluginPath(dir, config)
+		} else {
+			path, err = findPluginPath(filepath.Join(dir, config.InstallPath()))
+		}
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		log.Printf("[DEBUG] Find plugin path: %s", path)
+		return path, nil
 	}
-	log.Printf("[DEBUG] Find plugin path: %s", path)
-	return path, err
+	return "", err
 }
 
-// getPluginDir returns the base plugin directory.
+// getPluginDirs returns the search path of plugin directories.
 // Adopted with the following priorities:
 //
 //  1. `plugin_dir` in a global config
-//  2. `TFLINT_PLUGIN_DIR` environment variable
+//  2. `TFLINT_PLUGIN_DIR` environment variable (a list separated by the OS-specific path list separator)
 //  3. Current directory (./.tflint.d/plugins)
 //  4. Home directory (~/.tflint.d/plugins)
 //
 // If the environment variable is set, other directories will not be considered,
 // but if the current directory does not exist, it will fallback to the home directory.
-func getPluginDir(cfg *tflint.Config) (string, error) {
-	if cfg.PluginDir != "" {
-		return homedir.Expand(cfg.PluginDir)
+func getPluginDirs(cfg *tflint.Config) ([]string, error) {
+	if len(cfg.PluginDirs) > 0 {
+		dirs := make([]string, len(cfg.PluginDirs))
+		for i, dir := range cfg.PluginDirs {
+			expanded, err := homedir.Expand(dir)
+			if err != nil {
+				return nil, err
+			}
+			dirs[i] = expanded
+		}
+		return dirs, nil
 	}
 
-	if dir := os.Getenv("TFLINT_PLUGIN_DIR"); dir != "" {
-		return dir, nil
+	dirs := []string{}
+	for _, dir := range filepath.SplitList(os.Getenv("TFLINT_PLUGIN_DIR")) {
+		if dir != "" {
+			dirs = append(dirs, dir)
+		}
+	}
+	if len(dirs) > 0 {
+		return dirs, nil
 	}
 
 	_, err := os.Stat(localPluginRoot)
 	if os.IsNotExist(err) {
-		return homedir.Expand(PluginRoot)
+		dir, err := homedir.Expand(PluginRoot)
+		if err != nil {
+			return nil, err
+		}
+		return []string{dir}, nil
 	}
 
-	return localPluginRoot, err
+	return []string{localPluginRoot}, err
+}
+
+// getPluginDir returns the directory where plugins are installed.
+// This is the first directory of the search path.
+func getPluginDir(cfg *tflint.Config) (string, error) {
+	dirs, err := getPluginDirs(cfg)
+	if err != nil {
+		return "", err
+	}
+	return dirs[0], nil
 }
 
 // findPluginPath returns the path of the existing plugin.
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/terraform-linters/tflint/tflint"
@@ -132,7 +133,7 @@ func Test_Discovery_pluginDirConfig(t *testing.T) {
 	}
 
 	plugin, err := Discovery(&tflint.Config{
-		PluginDir: filepath.Join(cwd, "test-fixtures", "locals", ".tflint.d", "plugins"),
+		PluginDirs: []string{filepath.Join(cwd, "test-fixtures", "locals", ".tflint.d", "plugins")},
 		Plugins: map[string]*tflint.PluginConfig{
 			"foo": {
 				Name:    "foo",
@@ -463,8 +464,9 @@ func Test_FindPluginPath_pluginDirConfig(t *testing.T) {
 		t.Fatal(err)
 	}
 
+	dir := filepath.Join(cwd, "test-fixtures", "locals", ".tflint.d", "plugins")
 	globalConfig := tflint.EmptyConfig()
-	globalConfig.PluginDir = filepath.Join(cwd, "test-fixtures", "locals", ".tflint.d", "plugins")
+	globalConfig.PluginDirs = []string{dir}
 
 	cases := []struct {
 		Name     string
@@ -474,7 +476,7 @@ func Test_FindPluginPath_pluginDirConfig(t *testing.T) {
 		{
 			Name:     "manually installed",
 			Input:    NewInstallConfig(globalConfig, &tflint.PluginConfig{Name: "foo", Enabled: true}),
-			Expected: filepath.Join(globalConfig.PluginDir, "tflint-ruleset-foo"+fileExt()),
+			Expected: filepath.Join(dir, "tflint-ruleset-foo"+fileExt()),
 		},
 		{
 			Name: "auto installed",
@@ -484,7 +486,7 @@ func Test_FindPluginPath_pluginDirConfig(t *testing.T) {
 				Source:  "github.com/terraform-linters/tflint-ruleset-bar",
 				Version: "0.1.0",
 			}),
-			Expected: filepath.Join(globalConfig.PluginDir, "github.com/terraform-linters/tflint-ruleset-bar", "0.1.0", "tflint-ruleset-bar"+fileExt()),
+			Expected: filepath.Join(dir, "github.com/terraform-linters/tflint-ruleset-bar", "0.1.0", "tflint-ruleset-bar"+fileExt()),
 		},
 	}
 
@@ -501,6 +503,68 @@ func Test_FindPluginPath_pluginDirConfig(t *testing.T) {
 	}
 }
 
+func Test_FindPluginPath_searchPath(t *testing.T) {
+	orgDir := t.TempDir()
+	localDir := t.TempDir()
+	files := []string{
+		filepath.Join(orgDir, "tflint-ruleset-foo"+fileExt()),
+		filepath.Join(orgDir, "tflint-ruleset-bar"+fileExt()),
+		filepath.Join(localDir, "tflint-ruleset-bar"+fileExt()),
+	}
+	for _, file := range files {
+		if err := os.WriteFile(file, []byte("binary"), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cases := []struct {
+		Name     string
+		Config   *tflint.Config
+		Env      string
+		Plugin   string
+		Expected string
+	}{
+		{
+			Name:     "plugin_dir",
+			Config:   &tflint.Config{PluginDirs: []string{localDir, orgDir}},
+			Plugin:   "foo",
+			Expected: filepath.Join(orgDir, "tflint-ruleset-foo"+fileExt()),
+		},
+		{
+			Name:     "first directory takes precedence",
+			Config:   &tflint.Config{PluginDirs: []string{localDir, orgDir}},
+			Plugin:   "bar",
+			Expected: filepath.Join(localDir, "tflint-ruleset-bar"+fileExt()),
+		},
+		{
+			Name:     "TFLINT_PLUGIN_DIR",
+			Config:   tflint.EmptyConfig(),
+			Env:      strings.Join([]string{orgDir, localDir}, string(os.PathListSeparator)),
+			Plugin:   "bar",
+			Expected: filepath.Join(orgDir, "tflint-ruleset-bar"+fileExt()),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Setenv("TFLINT_PLUGIN_DIR", tc.Env)
+
+			got, err := FindPluginPath(NewInstallConfig(tc.Config, &tflint.PluginConfig{Name: tc.Plugin, Enabled: true}))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tc.Expected {
+				t.Errorf("want=%s got=%s", tc.Expected, got)
+			}
+		})
+	}
+
+	_, err := FindPluginPath(NewInstallConfig(&tflint.Config{PluginDirs: []string{localDir, orgDir}}, &tflint.PluginConfig{Name: "baz", Enabled: true}))
+	if !os.IsNotExist(err) {
+		t.Fatalf("Expected a not exist error, but got %s", err)
+	}
+}
+
 func Test_FindPluginPath_withoutExtensionInWindows(t *testing.T) {
 	cwd, err := os.Getwd()
 	if err != nil {
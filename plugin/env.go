@@ -0,0 +1,228 @@
+package plugin
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/terraform-linters/tflint/tflint"
+)
+
+// defaultEnvPassthrough is a list of environment variables passed to isolated plugins.
+// These are needed to run processes and to write plugin logs.
+var defaultEnvPassthrough = []string{
+	"PATH",
+	"HOME",
+	"USERPROFILE",
+	"TMPDIR",
+	"TMP",
+	"TEMP",
+	"SYSTEMROOT",
+	"TFLINT_LOG",
+}
+
+// pluginEnv returns the environment of the plugin process in the "key=value" form.
+// It returns nil if the plugin inherits the environment of TFLint as is.
+//
+// A plugin is isolated if `plugin_env_isolation` is enabled or `env_passthrough` is set.
+// Isolated plugins get only the default variables and the variables listed in `env_passthrough`.
+// Variables in `env` are always set, overriding the inherited ones.
+func pluginEnv(config *tflint.Config, pluginCfg *tflint.PluginConfig) []string {
+	isolated := config.PluginEnvIsolation || len(pluginCfg.EnvPassthrough) > 0
+	if !isolated && len(pluginCfg.Env) == 0 {
+		return nil
+	}
+
+	patterns := append(append([]string{}, defaultEnvPassthrough...), pluginCfg.EnvPassthrough...)
+
+	env := map[string]string{}
+	for _, kv := range os.Environ() {
+		key, value, found := strings.Cut(kv, "=")
+		// On Windows, there are hidden variables like "=C:=C:\foo"
+		if !found || key == "" {
+			continue
+		}
+		if isolated && !matchEnvName(patterns, key) {
+			continue
+		}
+		env[key] = value
+	}
+	for key, value := range pluginCfg.Env {
+		env[key] = value
+	}
+
+	ret := make([]string, 0, len(env))
+	for key, value := range env {
+		ret = append(ret, key+"="+value)
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+func matchEnvName(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		// Environment variable names are case-insensitive on Windows
+		if runtime.GOOS == "windows" {
+			pattern = strings.ToUpper(pattern)
+			name = strings.ToUpper(name)
+		}
+		if matched, _ := path.Match(pattern, name); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// launcherEnvKey is the environment variable to pass the plugin command to the plugin launcher.
+const launcherEnvKey = "TFLINT_PLUGIN_LAUNCH_REQUEST"
+
+// relayEnvKey is the environment variable to start the plugin launcher as an output relay.
+// The value is the name of the plugin binary.
+const relayEnvKey = "TFLINT_PLUGIN_OUTPUT_RELAY"
+
+// magicCookieKey is the environment variable set by go-plugin for the handshake.
+// See tflint-plugin-sdk/plugin/internal/host2plugin.
+const magicCookieKey = "TFLINT_RULESET_PLUGIN"
+
+// launchRequest is the plugin command passed to the plugin launcher.
+type launchRequest struct {
+	Path string   `json:"path"`
+	Args []string `json:"args"`
+	Env  []string `json:"env"`
+}
+
+// launcherCommand returns a command that starts the passed command with the passed environment.
+//
+// go-plugin appends the environment of TFLint to Cmd.Env when launching plugins,
+// and the later value wins, so Cmd.Env cannot remove or override inherited variables.
+// Instead, TFLint is started as a plugin launcher, which replaces the environment
+// with the passed one, keeping only the variables set by go-plugin, and then starts the plugin.
+// If the env is nil, the command is returned as is.
+func launcherCommand(cmd *exec.Cmd, env []string) (*exec.Cmd, error) {
+	if env == nil {
+		return cmd, nil
+	}
+	// The inherited variable would override the request, see above
+	for _, key := range []string{launcherEnvKey, relayEnvKey} {
+		if _, exists := os.LookupEnv(key); exists {
+			return nil, fmt.Errorf("%s must not be set to launch plugins with env or env_passthrough", key)
+		}
+	}
+
+	req, err := json.Marshal(launchRequest{Path: cmd.Path, Args: cmd.Args, Env: env})
+	if err != nil {
+		return nil, err
+	}
+	self, err := os.Executable()
+	if err != nil {
+		return nil, err
+	}
+
+	launcher := exec.Command(self, "--act-as-plugin-launcher")
+	launcher.Env = []string{launcherEnvKey + "=" + string(req)}
+	return launcher, nil
+}
+
+// outputName returns the name used to record the output of the plugin started by the passed command.
+//
+// go-plugin logs the stderr of the plugin process with the logger named after the command,
+// so the output of plugins started by the launcher is logged as the launcher (e.g. "tflint").
+// The launcher prefixes each line with the name of the plugin binary to distinguish them,
+// so their output is recorded as "tflint: tflint-ruleset-aws".
+func outputName(plugin *exec.Cmd, cmd *exec.Cmd) string {
+	if plugin == cmd {
+		return filepath.Base(plugin.Path)
+	}
+	return filepath.Base(cmd.Path) + ": " + filepath.Base(plugin.Path)
+}
+
+// LaunchPlugin starts the plugin requested by the launcherCommand in the plugin launcher process.
+// The stderr of the plugin is relayed with the name of the plugin binary. See outputName.
+//
+// On Windows, the plugin is started as a child process that is killed with the launcher,
+// and it returns the exit code after the plugin exits. Otherwise, the launcher process is
+// replaced with the plugin and never returns on success.
+func LaunchPlugin() (int, error) {
+	// Interrupts are handled by TFLint, which stops plugins, as in go-plugin.
+	signal.Ignore(os.Interrupt)
+
+	if name, exists := os.LookupEnv(relayEnvKey); exists {
+		if err := relayOutput(os.Stdin, os.Stderr, name); err != nil {
+			return 1, err
+		}
+		return 0, nil
+	}
+
+	raw, exists := os.LookupEnv(launcherEnvKey)
+	if !exists {
+		return 1, fmt.Errorf("%s is not set. The plugin launcher must be started by TFLint", launcherEnvKey)
+	}
+	var req launchRequest
+	if err := json.Unmarshal([]byte(raw), &req); err != nil {
+		return 1, fmt.Errorf("Failed to decode %s; %w", launcherEnvKey, err)
+	}
+
+	env := append([]string{}, req.Env...)
+	for _, kv := range os.Environ() {
+		key, _, _ := strings.Cut(kv, "=")
+		if key == magicCookieKey || strings.HasPrefix(key, "PLUGIN_") {
+			env = append(env, kv)
+		}
+	}
+
+	return execPlugin(req.Path, req.Args, env)
+}
+
+// relayOutput writes each line read from r to w with the passed name until EOF.
+func relayOutput(r io.Reader, w io.Writer, name string) error {
+	reader := bufio.NewReader(r)
+	for {
+		line, err := reader.ReadString('\n')
+		if line != "" {
+			if _, werr := fmt.Fprintln(w, relayLine(name, strings.TrimRight(line, "\r\n"))); werr != nil {
+				return werr
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// relayLine prefixes the line with the passed name. The SDK logger writes JSON lines,
+// which go-plugin parses to keep the log level, so only their message is prefixed.
+// Empty lines are relayed as the name without the separator, as the logger does.
+func relayLine(name string, line string) string {
+	if line == "" {
+		return name
+	}
+
+	decoder := json.NewDecoder(strings.NewReader(line))
+	decoder.UseNumber()
+	var entry map[string]interface{}
+	if err := decoder.Decode(&entry); err == nil {
+		if msg, ok := entry["@message"].(string); ok {
+			entry["@message"] = name + ": " + msg
+			var buf bytes.Buffer
+			encoder := json.NewEncoder(&buf)
+			encoder.SetEscapeHTML(false)
+			if err := encoder.Encode(entry); err == nil {
+				return strings.TrimRight(buf.String(), "\n")
+			}
+		}
+	}
+	return name + ": " + line
+}
@@ -0,0 +1,151 @@
+package plugin
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/terraform-linters/tflint/tflint"
+)
+
+func Test_pluginEnv(t *testing.T) {
+	// Registered first to restore the environment after the cleanups of t.Setenv
+	original := os.Environ()
+	t.Cleanup(func() {
+		os.Clearenv()
+		for _, kv := range original {
+			key, value, _ := strings.Cut(kv, "=")
+			os.Setenv(key, value)
+		}
+	})
+	os.Clearenv()
+
+	t.Setenv("PATH", "/usr/bin")
+	t.Setenv("HOME", "/home/user")
+	t.Setenv("AWS_REGION", "us-east-1")
+	t.Setenv("AWS_PROFILE", "default")
+	t.Setenv("GITHUB_TOKEN", "secret")
+
+	tests := []struct {
+		name   string
+		config *tflint.Config
+		plugin *tflint.PluginConfig
+		want   []string
+	}{
+		{
+			name:   "default",
+			config: &tflint.Config{},
+			plugin: &tflint.PluginConfig{Name: "foo"},
+			want:   nil,
+		},
+		{
+			name:   "env",
+			config: &tflint.Config{},
+			plugin: &tflint.PluginConfig{Name: "foo", Env: map[string]string{"AWS_REGION": "ap-northeast-1", "FOO": "bar"}},
+			want: []string{
+				"AWS_PROFILE=default",
+				"AWS_REGION=ap-northeast-1",
+				"FOO=bar",
+				"GITHUB_TOKEN=secret",
+				"HOME=/home/user",
+				"PATH=/usr/bin",
+			},
+		},
+		{
+			name:   "plugin_env_isolation",
+			config: &tflint.Config{PluginEnvIsolation: true},
+			plugin: &tflint.PluginConfig{Name: "foo"},
+			want: []string{
+				"HOME=/home/user",
+				"PATH=/usr/bin",
+			},
+		},
+		{
+			name:   "env_passthrough",
+			config: &tflint.Config{},
+			plugin: &tflint.PluginConfig{Name: "foo", EnvPassthrough: []string{"AWS_*"}, Env: map[string]string{"FOO": "bar"}},
+			want: []string{
+				"AWS_PROFILE=default",
+				"AWS_REGION=us-east-1",
+				"FOO=bar",
+				"HOME=/home/user",
+				"PATH=/usr/bin",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := pluginEnv(test.config, test.plugin)
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+	}
+}
+
+func Test_launcherCommand(t *testing.T) {
+	cmd := exec.Command("/path/to/tflint-ruleset-foo", "--bar")
+
+	got, err := launcherCommand(cmd, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != cmd {
+		t.Fatal("Expected the command to be returned as is without env")
+	}
+
+	got, err = launcherCommand(cmd, []string{"FOO=bar"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(got.Args[1:], []string{"--act-as-plugin-launcher"}); diff != "" {
+		t.Fatal(diff)
+	}
+	want := []string{`TFLINT_PLUGIN_LAUNCH_REQUEST={"path":"/path/to/tflint-ruleset-foo","args":["/path/to/tflint-ruleset-foo","--bar"],"env":["FOO=bar"]}`}
+	if diff := cmp.Diff(got.Env, want); diff != "" {
+		t.Fatal(diff)
+	}
+	// The environment of TFLint is never changed
+	if _, exists := os.LookupEnv("FOO"); exists {
+		t.Fatal("Expected FOO to be unset")
+	}
+}
+
+func Test_outputName(t *testing.T) {
+	cmd := exec.Command("/path/to/tflint-ruleset-foo")
+	if got := outputName(cmd, cmd); got != "tflint-ruleset-foo" {
+		t.Fatalf("unexpected name: %s", got)
+	}
+
+	launcher := exec.Command("/path/to/tflint", "--act-as-plugin-launcher")
+	if got := outputName(cmd, launcher); got != "tflint: tflint-ruleset-foo" {
+		t.Fatalf("unexpected name: %s", got)
+	}
+}
+
+func Test_relayOutput(t *testing.T) {
+	in := strings.Join([]string{
+		`{"@level":"debug","@message":"starting","@timestamp":"2024-01-01T00:00:00.000000Z","count":10000000000}`,
+		"panic: boom",
+		"",
+		"goroutine 1 [running]:",
+	}, "\n")
+
+	out := new(strings.Builder)
+	if err := relayOutput(strings.NewReader(in), out, "tflint-ruleset-foo"); err != nil {
+		t.Fatal(err)
+	}
+
+	want := strings.Join([]string{
+		`{"@level":"debug","@message":"tflint-ruleset-foo: starting","@timestamp":"2024-01-01T00:00:00.000000Z","count":10000000000}`,
+		"tflint-ruleset-foo: panic: boom",
+		"tflint-ruleset-foo",
+		"tflint-ruleset-foo: goroutine 1 [running]:",
+	}, "\n") + "\n"
+	if diff := cmp.Diff(want, out.String()); diff != "" {
+		t.Fatal(diff)
+	}
+}
@@ -0,0 +1,53 @@
+//go:build !windows
+
+package plugin
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// execPlugin replaces the current process with the plugin.
+//
+// The process ID is kept, so go-plugin can kill the plugin directly. Since the stderr
+// cannot be relayed after replacing the process, it is redirected to a relay process
+// that exits after the plugin closes the stderr.
+func execPlugin(path string, args []string, env []string) (int, error) {
+	if err := startRelay(filepath.Base(path)); err != nil {
+		return 1, err
+	}
+	if err := syscall.Exec(path, args, env); err != nil {
+		return 1, err
+	}
+	return 0, nil
+}
+
+// startRelay starts the plugin launcher as an output relay and redirects the stderr to it.
+func startRelay(name string) error {
+	r, w, err := os.Pipe()
+	if err != nil {
+		return err
+	}
+	defer w.Close()
+
+	self, err := os.Executable()
+	if err != nil {
+		r.Close()
+		return err
+	}
+	relay := exec.Command(self, "--act-as-plugin-launcher")
+	relay.Env = []string{relayEnvKey + "=" + name}
+	relay.Stdin = r
+	relay.Stderr = os.Stderr
+	err = relay.Start()
+	r.Close()
+	if err != nil {
+		return err
+	}
+
+	return unix.Dup2(int(w.Fd()), int(os.Stderr.Fd()))
+}
@@ -0,0 +1,94 @@
+//go:build windows
+
+package plugin
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// execPlugin runs the plugin as a child process and waits for it to exit,
+// since Windows cannot replace the current process.
+//
+// go-plugin only knows the launcher process, so killing it on timeouts would leave
+// the plugin running and holding the connection. To prevent this, the plugin is
+// assigned to a job object that kills the plugin when the launcher exits.
+func execPlugin(path string, args []string, env []string) (int, error) {
+	job, err := newKillOnCloseJob()
+	if err != nil {
+		return 1, err
+	}
+	// The job is closed by the system when the launcher exits, even if killed
+	defer windows.CloseHandle(job)
+
+	cmd := exec.Command(path)
+	cmd.Args = args
+	cmd.Env = env
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return 1, err
+	}
+
+	if err := cmd.Start(); err != nil {
+		return 1, err
+	}
+	if err := assignProcessToJob(job, cmd.Process.Pid); err != nil {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+		return 1, err
+	}
+
+	relayErr := relayOutput(stderr, os.Stderr, filepath.Base(path))
+	if err := cmd.Wait(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return exitErr.ExitCode(), nil
+		}
+		return 1, err
+	}
+	if relayErr != nil {
+		return 1, relayErr
+	}
+	return 0, nil
+}
+
+// newKillOnCloseJob creates a job object that kills all processes in it when the last handle is closed.
+func newKillOnCloseJob() (windows.Handle, error) {
+	job, err := windows.CreateJobObject(nil, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	info := windows.JOBOBJECT_EXTENDED_LIMIT_INFORMATION{
+		BasicLimitInformation: windows.JOBOBJECT_BASIC_LIMIT_INFORMATION{
+			LimitFlags: windows.JOB_OBJECT_LIMIT_KILL_ON_JOB_CLOSE,
+		},
+	}
+	if _, err := windows.SetInformationJobObject(
+		job,
+		windows.JobObjectExtendedLimitInformation,
+		uintptr(unsafe.Pointer(&info)),
+		uint32(unsafe.Sizeof(info)),
+	); err != nil {
+		windows.CloseHandle(job)
+		return 0, err
+	}
+	return job, nil
+}
+
+func assignProcessToJob(job windows.Handle, pid int) error {
+	process, err := windows.OpenProcess(windows.PROCESS_SET_QUOTA|windows.PROCESS_TERMINATE, false, uint32(pid))
+	if err != nil {
+		return err
+	}
+	defer windows.CloseHandle(process)
+
+	return windows.AssignProcessToJobObject(job, process)
+}
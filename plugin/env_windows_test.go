@@ -0,0 +1,43 @@
+//go:build windows
+
+package plugin
+
+import (
+	"os/exec"
+	"testing"
+	"time"
+
+	"golang.org/x/sys/windows"
+)
+
+func Test_newKillOnCloseJob(t *testing.T) {
+	job, err := newKillOnCloseJob()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A long-running process standing in for the plugin
+	cmd := exec.Command("ping", "-n", "60", "127.0.0.1")
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	if err := assignProcessToJob(job, cmd.Process.Pid); err != nil {
+		_ = cmd.Process.Kill()
+		t.Fatal(err)
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- cmd.Wait() }()
+
+	// Closing the last handle is what happens when the launcher is killed
+	if err := windows.CloseHandle(job); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		_ = cmd.Process.Kill()
+		t.Fatal("Expected the process to be killed with the job, but it is still running")
+	}
+}
@@ -26,6 +26,14 @@ type InstallConfig struct {
 	globalConfig *tflint.Config
 
 	*tflint.PluginConfig
+
+	// Lock is the locked plugin in the lock file. If set, the downloaded release is verified
+	// against the locked checksums. After installation, this is updated with the checksums
+	// of the release and the installed binary.
+	Lock *LockedPlugin
+
+	// constraints is the original version constraints if the version is resolved by Resolve.
+	constraints string
 }
 
 // NewInstallConfig returns a new InstallConfig from passed PluginConfig.
@@ -41,7 +49,7 @@ func (c *InstallConfig) ManuallyInstalled() bool {
 
 // InstallPath returns an installation path from the plugin directory.
 func (c *InstallConfig) InstallPath() string {
-	return filepath.Join(c.Source, c.Version, fmt.Sprintf("tflint-ruleset-%s", c.Name))
+	return filepath.Join(c.sourceDir(), c.Version, fmt.Sprintf("tflint-ruleset-%s", c.Name))
 }
 
 // TagName returns a tag name that the GitHub release should meet.
@@ -52,27 +60,32 @@ func (c *InstallConfig) TagName() string {
 }
 
 // AssetName returns a name that the asset contained in the release should meet.
-// The name must be in a format similar to `tflint-ruleset-aws_darwin_amd64.zip`.
+// For GitHub releases, the name must be in a format similar to `tflint-ruleset-aws_darwin_amd64.zip`.
+// For URL sources, it is the file name in the URL.
 func (c *InstallConfig) AssetName() string {
-	return fmt.Sprintf("tflint-ruleset-%s_%s_%s.zip", c.Name, runtime.GOOS, runtime.GOARCH)
+	return expandPlatform(c.assetTemplate(), runtime.GOOS, runtime.GOARCH)
 }
 
-// Install fetches the release from GitHub and puts the binary in the plugin directory.
+// Install fetches the release from the source and puts the binary in the plugin directory.
 // This installation process will automatically check the checksum of the downloaded zip file.
 // Therefore, the release must always contain a checksum file.
 // In addition, the release must meet the following conventions:
 //
-//   - The release must be tagged with a name like v1.1.1
+//   - The release must be tagged with a name like v1.1.1 (GitHub only)
 //   - The release must contain an asset with a name like tflint-ruleset-{name}_{GOOS}_{GOARCH}.zip
+//     (For URL sources, the name in the URL, where {name}, {version}, {os}, and {arch} are expanded)
 //   - The zip file must contain a binary named tflint-ruleset-{name} (tflint-ruleset-{name}.exe in Windows)
 //   - The release must contain a checksum file for the zip file with the name checksums.txt
+//     (For URL sources, the checksums_url or checksums.txt next to the asset)
 //   - The checksum file must contain a sha256 hash and filename
 //
 // For security, you can also make sure that the checksum file is signed correctly.
 // In that case, the release must additionally meet the following conventions:
 //
 //   - The release must contain a signature file for the checksum file with the name checksums.txt.sig
-//   - The signature file must be binary OpenPGP format
+//     (checksums.txt.minisig for minisign keys. For URL sources, the checksums file URL with the same suffix)
+//   - The signature file must be binary OpenPGP format, an SSH signature, or a minisign signature,
+//     according to the format of the signing key
 func (c *InstallConfig) Install() (string, error) {
 	dir, err := getPluginDir(c.globalConfig)
 	if err != nil {
@@ -85,42 +98,22 @@ func (c *InstallConfig) Install() (string, error) {
 		return "", fmt.Errorf("Failed to mkdir to %s: %w", filepath.Dir(path), err)
 	}
 
-	assets, err := c.fetchReleaseAssets()
+	source, err := c.releaseSource()
 	if err != nil {
-		return "", fmt.Errorf("Failed to fetch GitHub releases: %w", err)
+		return "", err
 	}
 
-	log.Printf("[DEBUG] Download checksums.txt")
-	checksumsFile, err := c.downloadToTempFile(assets["checksums.txt"])
-	if checksumsFile != nil {
-		defer os.Remove(checksumsFile.Name())
-	}
+	checksumsFile, signatureFile, err := c.fetchChecksums(source)
 	if err != nil {
-		return "", fmt.Errorf("Failed to download checksums.txt: %s", err)
+		return "", err
 	}
-
-	sigchecker := NewSignatureChecker(c)
-	if sigchecker.HasSigningKey() {
-		log.Printf("[DEBUG] Download checksums.txt.sig")
-		signatureFile, err := c.downloadToTempFile(assets["checksums.txt.sig"])
-		if signatureFile != nil {
-			defer os.Remove(signatureFile.Name())
-		}
-		if err != nil {
-			return "", fmt.Errorf("Failed to download checksums.txt.sig: %s", err)
-		}
-
-		if err := sigchecker.Verify(checksumsFile, signatureFile); err != nil {
-			return "", fmt.Errorf("Failed to check checksums.txt signature: %s", err)
-		}
-		if _, err := checksumsFile.Seek(0, 0); err != nil {
-			return "", fmt.Errorf("Failed to check checksums.txt signature: %s", err)
-		}
-		log.Printf("[DEBUG] Verified signature successfully")
+	defer os.Remove(checksumsFile.Name())
+	if signatureFile != nil {
+		defer os.Remove(signatureFile.Name())
 	}
 
 	log.Printf("[DEBUG] Download %s", c.AssetName())
-	zipFile, err := c.downloadToTempFile(assets[c.AssetName()])
+	zipFile, err := source.downloadAsset(runtime.GOOS, runtime.GOARCH)
 	if zipFile != nil {
 		defer os.Remove(zipFile.Name())
 	}
@@ -137,14 +130,126 @@ func (c *InstallConfig) Install() (string, error) {
 	}
 	log.Printf("[DEBUG] Matched checksum successfully")
 
+	if c.Lock == nil {
+		c.Lock = &LockedPlugin{Name: c.Name, Source: c.Source, Version: c.Version}
+	}
+	c.Lock.Constraints = c.constraints
+	if err := c.verifyLock(checksummer, zipFile); err != nil {
+		return "", fmt.Errorf("Failed to verify checksums in %s: %s", LockFileName, err)
+	}
+
 	if err = extractFileFromZipFile(zipFile, path); err != nil {
 		return "", fmt.Errorf("Failed to extract binary from %s: %s", c.AssetName(), err)
 	}
 
+	if err := c.lockBinary(path); err != nil {
+		os.Remove(path)
+		return "", fmt.Errorf("Failed to verify checksums in %s: %s", LockFileName, err)
+	}
+
 	log.Printf("[DEBUG] Installed %s successfully", path)
 	return path, nil
 }
 
+// fetchChecksums downloads the checksums file and verifies its signature if a signing key is configured.
+// The signature file is nil if no signing key is configured.
+// It is the caller's responsibility to delete the returned temp files.
+func (c *InstallConfig) fetchChecksums(source releaseSource) (checksumsFile *os.File, signatureFile *os.File, err error) {
+	defer func() {
+		if err == nil {
+			return
+		}
+		if checksumsFile != nil {
+			os.Remove(checksumsFile.Name())
+		}
+		if signatureFile != nil {
+			os.Remove(signatureFile.Name())
+		}
+	}()
+
+	log.Printf("[DEBUG] Download checksums.txt")
+	checksumsFile, err = source.downloadChecksums()
+	if err != nil {
+		return checksumsFile, nil, fmt.Errorf("Failed to download checksums.txt: %s", err)
+	}
+
+	sigchecker := NewSignatureChecker(c)
+	if !sigchecker.HasSigningKey() {
+		return checksumsFile, nil, nil
+	}
+
+	signatureName := sigchecker.SignatureFileName()
+	log.Printf("[DEBUG] Download %s", signatureName)
+	signatureFile, err = source.downloadSignature(signatureName)
+	if err != nil {
+		return checksumsFile, signatureFile, fmt.Errorf("Failed to download %s: %s", signatureName, err)
+	}
+
+	if err := sigchecker.Verify(checksumsFile, signatureFile); err != nil {
+		return checksumsFile, signatureFile, fmt.Errorf("Failed to check checksums.txt signature: %s", err)
+	}
+	for _, f := range []*os.File{checksumsFile, signatureFile} {
+		if _, err := f.Seek(0, 0); err != nil {
+			return checksumsFile, signatureFile, fmt.Errorf("Failed to check checksums.txt signature: %s", err)
+		}
+	}
+	log.Printf("[DEBUG] Verified signature successfully")
+
+	return checksumsFile, signatureFile, nil
+}
+
+// verifyLock verifies the release against the locked checksums, and then records
+// the checksums of the release. Checksums of other platforms are also compared
+// to detect a release that has been replaced since it was locked.
+func (c *InstallConfig) verifyLock(checksummer *Checksummer, zipFile *os.File) error {
+	if _, err := zipFile.Seek(0, 0); err != nil {
+		return err
+	}
+	if err := c.Lock.VerifyAsset(zipFile); err != nil {
+		return fmt.Errorf("%s: %w", c.AssetName(), err)
+	}
+
+	checksums := platformChecksums(c.assetTemplate(), checksummer)
+	for platform, locked := range c.Lock.Checksums {
+		if checksum, exists := checksums[platform]; exists && checksum != locked {
+			return fmt.Errorf("checksums.txt does not match for %s: locked=%s, actual=%s", platform, locked, checksum)
+		}
+	}
+
+	if c.Lock.Checksums == nil {
+		c.Lock.Checksums = map[string]string{}
+	}
+	for platform, checksum := range checksums {
+		c.Lock.Checksums[platform] = checksum
+	}
+	log.Printf("[DEBUG] Matched locked checksums successfully")
+	return nil
+}
+
+// lockBinary verifies the installed binary against the locked checksum for the current platform.
+// If no checksum is locked, the checksum of the binary is recorded.
+func (c *InstallConfig) lockBinary(path string) error {
+	if c.Lock.HasBinaryChecksum() {
+		return c.Lock.VerifyBinary(path)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	checksum, err := lockedChecksum(f)
+	if err != nil {
+		return err
+	}
+	if c.Lock.BinaryChecksums == nil {
+		c.Lock.BinaryChecksums = map[string]string{}
+	}
+	c.Lock.BinaryChecksums[currentPlatform()] = checksum
+	return nil
+}
+
 // fetchReleaseAssets fetches assets from the GitHub release.
 // The release is determined by the source path and tag name.
 func (c *InstallConfig) fetchReleaseAssets() (map[string]*github.ReleaseAsset, error) {
@@ -188,20 +293,9 @@ func (c *InstallConfig) downloadToTempFile(asset *github.ReleaseAsset) (*os.File
 		return nil, err
 	}
 
-	file, err := os.CreateTemp("", "tflint-download-temp-file-*")
-	if err != nil {
-		return nil, err
-	}
-	if _, err = io.Copy(file, downloader); err != nil {
-		return file, err
-	}
-	downloader.Close()
-	if _, err := file.Seek(0, 0); err != nil {
-		return file, err
-	}
+	defer downloader.Close()
 
-	log.Printf("[DEBUG] Downloaded to %s", file.Name())
-	return file, nil
+	return writeToTempFile(downloader)
 }
 
 func extractFileFromZipFile(zipFile *os.File, savePath string) error {
@@ -247,7 +341,7 @@ func extractFileFromZipFile(zipFile *os.File, savePath string) error {
 
 func newGitHubClient(ctx context.Context, config *InstallConfig) (*github.Client, error) {
 	hc := &http.Client{
-		Transport: http.DefaultTransport,
+		Transport: httpTransport,
 	}
 
 	if t := os.Getenv("GITHUB_TOKEN"); t != "" {
@@ -0,0 +1,167 @@
+package plugin
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/go-version"
+	"github.com/terraform-linters/tflint/tflint"
+)
+
+// InstalledPlugin is a plugin binary found in the plugin directory.
+type InstalledPlugin struct {
+	Name string
+	// Source is the source directory in the plugin directory (e.g. "github.com/org/repo").
+	// It is empty if the plugin is installed manually.
+	Source string
+	// Version is empty if the plugin is installed manually.
+	Version string
+	Path    string
+}
+
+// ManuallyInstalled returns whether the plugin is placed directly in the plugin directory.
+func (p *InstalledPlugin) ManuallyInstalled() bool {
+	return p.Source == ""
+}
+
+// VersionDir returns the directory of the installed version.
+// The directory contains only the plugin binary, so it can be removed as a whole.
+func (p *InstalledPlugin) VersionDir() string {
+	return filepath.Dir(p.Path)
+}
+
+// PluginDirs returns absolute paths of the plugin directories for the config, in search order.
+// The first directory is the directory where plugins are installed.
+func PluginDirs(config *tflint.Config) ([]string, error) {
+	dirs, err := getPluginDirs(config)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := []string{}
+	seen := map[string]bool{}
+	for _, dir := range dirs {
+		abs, err := filepath.Abs(dir)
+		if err != nil {
+			return nil, err
+		}
+		if seen[abs] {
+			continue
+		}
+		seen[abs] = true
+		ret = append(ret, abs)
+	}
+	return ret, nil
+}
+
+// InstalledPlugins returns plugins installed in the passed plugin directory.
+// Plugins installed by "tflint --init" are found as [source]/[version]/tflint-ruleset-[name],
+// and manually installed plugins are found as tflint-ruleset-[name].
+// If the directory does not exist, it returns an empty list.
+func InstalledPlugins(dir string) ([]*InstalledPlugin, error) {
+	plugins := []*InstalledPlugin{}
+
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && path == dir {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if d.IsDir() || !strings.HasPrefix(d.Name(), "tflint-ruleset-") {
+			return nil
+		}
+		name := strings.TrimPrefix(d.Name(), "tflint-ruleset-")
+		if runtime.GOOS == "windows" {
+			if !strings.HasSuffix(name, ".exe") {
+				return nil
+			}
+			name = strings.TrimSuffix(name, ".exe")
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		versionDir := filepath.Dir(rel)
+		if versionDir == "." {
+			plugins = append(plugins, &InstalledPlugin{Name: name, Path: path})
+			return nil
+		}
+		// Binaries in other directories are not plugins installed by "tflint --init"
+		if _, err := version.NewVersion(filepath.Base(versionDir)); err != nil || filepath.Dir(versionDir) == "." {
+			return nil
+		}
+
+		plugins = append(plugins, &InstalledPlugin{
+			Name:    name,
+			Source:  filepath.ToSlash(filepath.Dir(versionDir)),
+			Version: filepath.Base(versionDir),
+			Path:    path,
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(plugins, func(i, j int) bool {
+		if plugins[i].Name != plugins[j].Name {
+			return plugins[i].Name < plugins[j].Name
+		}
+		if plugins[i].Source != plugins[j].Source {
+			return plugins[i].Source < plugins[j].Source
+		}
+		// Newer versions first
+		vi, erri := version.NewVersion(plugins[i].Version)
+		vj, errj := version.NewVersion(plugins[j].Version)
+		if erri == nil && errj == nil && !vi.Equal(vj) {
+			return vi.GreaterThan(vj)
+		}
+		return plugins[i].Path < plugins[j].Path
+	})
+	return plugins, nil
+}
+
+// UsedPluginPaths returns absolute paths of plugin binaries that the config uses.
+// Plugins are resolved in the same way as Discovery, including locked versions.
+// Plugins that are not installed are ignored.
+func UsedPluginPaths(config *tflint.Config) ([]string, error) {
+	lock, err := LoadLockFile(LockFileName)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := []string{}
+	for _, pluginCfg := range config.Plugins {
+		path, err := FindPluginPath(resolveInstallConfig(config, pluginCfg, lock))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		abs, err := filepath.Abs(path)
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, abs)
+	}
+	return paths, nil
+}
+
+// resolveInstallConfig returns the install config to find the plugin.
+// If the version is constraints, the locked version is used.
+func resolveInstallConfig(config *tflint.Config, pluginCfg *tflint.PluginConfig, lock *LockFile) *InstallConfig {
+	installCfg := NewInstallConfig(config, pluginCfg)
+	if lock != nil && pluginCfg.VersionConstraints() != nil {
+		if locked := lock.Plugin(pluginCfg.Name); locked != nil && locked.Matches(pluginCfg) {
+			installCfg = installCfg.WithVersion(locked.Version)
+		}
+	}
+	return installCfg
+}
@@ -0,0 +1,123 @@
+package plugin
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/terraform-linters/tflint/tflint"
+)
+
+func Test_InstalledPlugins(t *testing.T) {
+	dir := t.TempDir()
+	source := "github.com/example/tflint-ruleset-foo"
+
+	files := []string{
+		filepath.Join(source, "0.1.0", "tflint-ruleset-foo"+fileExt()),
+		filepath.Join(source, "0.10.0", "tflint-ruleset-foo"+fileExt()),
+		filepath.Join(source, "0.2.0", "tflint-ruleset-foo"+fileExt()),
+		"tflint-ruleset-bar" + fileExt(),
+		// Not plugins installed by "tflint --init"
+		filepath.Join(source, "latest", "tflint-ruleset-foo"+fileExt()),
+		filepath.Join("0.1.0", "tflint-ruleset-baz"+fileExt()),
+		filepath.Join(source, "0.1.0", "README.md"),
+	}
+	for _, file := range files {
+		path := filepath.Join(dir, file)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("binary"), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err := InstalledPlugins(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []*InstalledPlugin{
+		{Name: "bar", Path: filepath.Join(dir, "tflint-ruleset-bar"+fileExt())},
+		{Name: "foo", Source: source, Version: "0.10.0", Path: filepath.Join(dir, source, "0.10.0", "tflint-ruleset-foo"+fileExt())},
+		{Name: "foo", Source: source, Version: "0.2.0", Path: filepath.Join(dir, source, "0.2.0", "tflint-ruleset-foo"+fileExt())},
+		{Name: "foo", Source: source, Version: "0.1.0", Path: filepath.Join(dir, source, "0.1.0", "tflint-ruleset-foo"+fileExt())},
+	}
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Fatal(diff)
+	}
+
+	got, err = InstalledPlugins(filepath.Join(dir, "not_found"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 0 {
+		t.Fatalf("Expected no plugins, but got %d", len(got))
+	}
+}
+
+func Test_UsedPluginPaths(t *testing.T) {
+	t.Setenv("TFLINT_PLUGIN_DIR", "")
+
+	current, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := os.Chdir(current); err != nil {
+			t.Fatal(err)
+		}
+	}()
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+
+	pluginDir := filepath.Join("custom", "plugins")
+	source := "github.com/example/tflint-ruleset-foo"
+	for _, v := range []string{"0.1.0", "0.2.0"} {
+		if err := os.MkdirAll(filepath.Join(pluginDir, source, v), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(pluginDir, source, v, "tflint-ruleset-foo"+fileExt()), []byte("binary"), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	lock := &LockFile{}
+	lock.SetPlugin(&LockedPlugin{Name: "foo", Source: source, Version: "0.1.0", Constraints: "~> 0.1"})
+	if err := lock.Write(LockFileName); err != nil {
+		t.Fatal(err)
+	}
+
+	config := &tflint.Config{
+		PluginDirs: []string{pluginDir},
+		Plugins: map[string]*tflint.PluginConfig{
+			"foo": {Name: "foo", Enabled: true, Version: "~> 0.1", Source: source},
+			// Not installed
+			"bar": {Name: "bar", Enabled: true},
+		},
+	}
+
+	dirs, err := PluginDirs(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	absDir, err := filepath.Abs(pluginDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]string{absDir}, dirs); diff != "" {
+		t.Fatal(diff)
+	}
+
+	got, err := UsedPluginPaths(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The locked version is used even if a newer version is installed
+	expected := []string{filepath.Join(absDir, source, "0.1.0", "tflint-ruleset-foo"+fileExt())}
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Fatal(diff)
+	}
+}
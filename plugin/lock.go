@@ -0,0 +1,257 @@
+package plugin
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"regexp"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/gohcl"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/terraform-linters/tflint/tflint"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// LockFileName is the name of the plugin dependency lock file.
+// The lock file is placed in the working directory, like .terraform.lock.hcl.
+const LockFileName = ".tflint.lock.hcl"
+
+const lockFileHeader = `# This file is maintained automatically by "tflint --init".
+# Manual edits may be lost in future updates.
+`
+
+// LockFile is the plugin dependency lock file.
+// It pins the source, version, and checksums of plugins installed by "tflint --init".
+type LockFile struct {
+	Plugins []*LockedPlugin `hcl:"plugin,block"`
+}
+
+// LockedPlugin is a plugin recorded in the lock file.
+//
+// Checksums are the sha256 hashes of the release assets for each platform (e.g. "linux_amd64"),
+// taken from the checksums file of the release. These are used to verify downloads on other machines.
+// BinaryChecksums are the sha256 hashes of the extracted binaries. These are recorded for each platform
+// on which the plugin is installed, and are used to verify the binary before launching it.
+type LockedPlugin struct {
+	Name            string            `hcl:"name,label"`
+	Source          string            `hcl:"source"`
+	Version         string            `hcl:"version"`
+	Constraints     string            `hcl:"constraints,optional"`
+	Checksums       map[string]string `hcl:"checksums,optional"`
+	BinaryChecksums map[string]string `hcl:"binary_checksums,optional"`
+}
+
+// LoadLockFile loads the lock file from the passed path.
+// If the file does not exist, it returns nil without errors.
+func LoadLockFile(path string) (*LockFile, error) {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	log.Printf("[INFO] Load lock file: %s", path)
+
+	file, diags := hclsyntax.ParseConfig(src, path, hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	lock := &LockFile{}
+	if diags := gohcl.DecodeBody(file.Body, nil, lock); diags.HasErrors() {
+		return nil, diags
+	}
+
+	names := map[string]bool{}
+	for _, plugin := range lock.Plugins {
+		if names[plugin.Name] {
+			return nil, fmt.Errorf(`plugin "%s" is locked more than once`, plugin.Name)
+		}
+		names[plugin.Name] = true
+	}
+
+	return lock, nil
+}
+
+// Plugin returns the locked plugin with the passed name.
+// If the plugin is not locked, it returns nil.
+func (l *LockFile) Plugin(name string) *LockedPlugin {
+	for _, plugin := range l.Plugins {
+		if plugin.Name == name {
+			return plugin
+		}
+	}
+	return nil
+}
+
+// SetPlugin adds or replaces the locked plugin.
+func (l *LockFile) SetPlugin(locked *LockedPlugin) {
+	for i, plugin := range l.Plugins {
+		if plugin.Name == locked.Name {
+			l.Plugins[i] = locked
+			return
+		}
+	}
+	l.Plugins = append(l.Plugins, locked)
+}
+
+// Prune removes locked plugins that are not installed by "tflint --init" in the passed config.
+func (l *LockFile) Prune(config *tflint.Config) {
+	plugins := []*LockedPlugin{}
+	for _, plugin := range l.Plugins {
+		if pluginCfg, exists := config.Plugins[plugin.Name]; exists && pluginCfg.Source != "" && pluginCfg.Version != "" {
+			plugins = append(plugins, plugin)
+		}
+	}
+	l.Plugins = plugins
+}
+
+// Write writes the lock file to the passed path. Plugins and platforms are sorted
+// so that the output is stable.
+func (l *LockFile) Write(path string) error {
+	sort.Slice(l.Plugins, func(i, j int) bool { return l.Plugins[i].Name < l.Plugins[j].Name })
+
+	file := hclwrite.NewEmptyFile()
+	body := file.Body()
+
+	for i, plugin := range l.Plugins {
+		if i > 0 {
+			body.AppendNewline()
+		}
+		block := body.AppendNewBlock("plugin", []string{plugin.Name}).Body()
+		block.SetAttributeValue("source", cty.StringVal(plugin.Source))
+		block.SetAttributeValue("version", cty.StringVal(plugin.Version))
+		if plugin.Constraints != "" {
+			block.SetAttributeValue("constraints", cty.StringVal(plugin.Constraints))
+		}
+		if len(plugin.Checksums) > 0 {
+			block.SetAttributeValue("checksums", checksumsVal(plugin.Checksums))
+		}
+		if len(plugin.BinaryChecksums) > 0 {
+			block.SetAttributeValue("binary_checksums", checksumsVal(plugin.BinaryChecksums))
+		}
+	}
+
+	return os.WriteFile(path, append([]byte(lockFileHeader+"\n"), file.Bytes()...), 0644)
+}
+
+func checksumsVal(checksums map[string]string) cty.Value {
+	vals := map[string]cty.Value{}
+	for platform, checksum := range checksums {
+		vals[platform] = cty.StringVal(checksum)
+	}
+	return cty.ObjectVal(vals)
+}
+
+// Matches returns whether the locked plugin matches the source and version in the config.
+// If the version in the config is constraints, the locked version must satisfy them.
+func (l *LockedPlugin) Matches(config *tflint.PluginConfig) bool {
+	if l.Source != config.Source {
+		return false
+	}
+
+	if constraints := config.VersionConstraints(); constraints != nil {
+		v, err := version.NewVersion(l.Version)
+		if err != nil {
+			return false
+		}
+		return constraints.Check(v)
+	}
+	return l.Version == config.Version
+}
+
+// VerifyAsset verifies the release asset for the current platform against the locked checksum.
+// If no checksum is locked for the current platform, it does nothing.
+func (l *LockedPlugin) VerifyAsset(f io.Reader) error {
+	expected, exists := l.Checksums[currentPlatform()]
+	if !exists {
+		return nil
+	}
+	return verifyLockedChecksum(expected, f)
+}
+
+// HasBinaryChecksum returns whether the checksum of the binary is locked for the current platform.
+func (l *LockedPlugin) HasBinaryChecksum() bool {
+	_, exists := l.BinaryChecksums[currentPlatform()]
+	return exists
+}
+
+// VerifyBinary verifies the plugin binary against the locked checksum for the current platform.
+func (l *LockedPlugin) VerifyBinary(path string) error {
+	expected, exists := l.BinaryChecksums[currentPlatform()]
+	if !exists {
+		return fmt.Errorf(`no checksum is locked for %s. Did you run "tflint --init"?`, currentPlatform())
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return verifyLockedChecksum(expected, f)
+}
+
+func verifyLockedChecksum(expected string, f io.Reader) error {
+	actual, err := lockedChecksum(f)
+	if err != nil {
+		return err
+	}
+	if actual != expected {
+		return fmt.Errorf("checksum mismatch: locked=%s, actual=%s", expected, actual)
+	}
+	return nil
+}
+
+// lockedChecksum returns the sha256 hash of the passed file in the lock file format.
+func lockedChecksum(f io.Reader) (string, error) {
+	hash := sha256.New()
+	if _, err := io.Copy(hash, f); err != nil {
+		return "", err
+	}
+	return "sha256:" + hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// platformChecksums returns checksums of the release assets for each platform.
+// The platform is determined by the asset name template like tflint-ruleset-aws_{os}_{arch}.zip.
+// If the template does not contain {os} and {arch}, only the current platform is returned.
+func platformChecksums(template string, checksummer *Checksummer) map[string]string {
+	ret := map[string]string{}
+
+	if !strings.Contains(template, "{os}") || !strings.Contains(template, "{arch}") {
+		filename := expandPlatform(template, runtime.GOOS, runtime.GOARCH)
+		if checksum, exists := checksummer.checksums[filename]; exists {
+			ret[currentPlatform()] = "sha256:" + hex.EncodeToString(checksum)
+		}
+		return ret
+	}
+
+	pattern := regexp.MustCompile("^" + strings.NewReplacer(
+		`\{os\}`, `(?P<os>[a-z0-9]+)`,
+		`\{arch\}`, `(?P<arch>[a-z0-9]+)`,
+	).Replace(regexp.QuoteMeta(template)) + "$")
+
+	for filename, checksum := range checksummer.checksums {
+		match := pattern.FindStringSubmatch(filename)
+		if match == nil {
+			continue
+		}
+		platform := fmt.Sprintf("%s_%s", match[pattern.SubexpIndex("os")], match[pattern.SubexpIndex("arch")])
+		ret[platform] = "sha256:" + hex.EncodeToString(checksum)
+	}
+	return ret
+}
+
+func currentPlatform() string {
+	return fmt.Sprintf("%s_%s", runtime.GOOS, runtime.GOARCH)
+}
@@ -0,0 +1,308 @@
+package plugin
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/terraform-linters/tflint/tflint"
+)
+
+func Test_LockFile_WriteAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), LockFileName)
+
+	lock := &LockFile{
+		Plugins: []*LockedPlugin{
+			{
+				Name:        "google",
+				Source:      "github.com/terraform-linters/tflint-ruleset-google",
+				Version:     "0.20.0",
+				Constraints: "~> 0.20",
+				Checksums: map[string]string{
+					"linux_amd64": "sha256:482419fdeed00692304e59558b5b0d915d4727868b88a5adbbbb76f5ed1b537a",
+				},
+			},
+			{
+				Name:    "aws",
+				Source:  "github.com/terraform-linters/tflint-ruleset-aws",
+				Version: "0.21.1",
+				Checksums: map[string]string{
+					"darwin_amd64": "sha256:3a61fff3689f27c89bce22893219919c629d2e10b96e7eadd5fef9f0e90bb353",
+					"linux_amd64":  "sha256:482419fdeed00692304e59558b5b0d915d4727868b88a5adbbbb76f5ed1b537a",
+				},
+				BinaryChecksums: map[string]string{
+					"linux_amd64": "sha256:db4eed4c0abcfb0b851da5bbfe8d0c71e1c2b6afe4fd627638a462c655045902",
+				},
+			},
+		},
+	}
+	if err := lock.Write(path); err != nil {
+		t.Fatal(err)
+	}
+
+	src, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `# This file is maintained automatically by "tflint --init".
+# Manual edits may be lost in future updates.
+
+plugin "aws" {
+  source  = "github.com/terraform-linters/tflint-ruleset-aws"
+  version = "0.21.1"
+  checksums = {
+    darwin_amd64 = "sha256:3a61fff3689f27c89bce22893219919c629d2e10b96e7eadd5fef9f0e90bb353"
+    linux_amd64  = "sha256:482419fdeed00692304e59558b5b0d915d4727868b88a5adbbbb76f5ed1b537a"
+  }
+  binary_checksums = {
+    linux_amd64 = "sha256:db4eed4c0abcfb0b851da5bbfe8d0c71e1c2b6afe4fd627638a462c655045902"
+  }
+}
+
+plugin "google" {
+  source      = "github.com/terraform-linters/tflint-ruleset-google"
+  version     = "0.20.0"
+  constraints = "~> 0.20"
+  checksums = {
+    linux_amd64 = "sha256:482419fdeed00692304e59558b5b0d915d4727868b88a5adbbbb76f5ed1b537a"
+  }
+}
+`
+	if diff := cmp.Diff(expected, string(src)); diff != "" {
+		t.Fatal(diff)
+	}
+
+	got, err := LoadLockFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(lock, got); diff != "" {
+		t.Fatal(diff)
+	}
+}
+
+func Test_LoadLockFile(t *testing.T) {
+	cases := []struct {
+		Name     string
+		Content  string
+		Expected *LockFile
+		Error    string
+	}{
+		{
+			Name:     "not exists",
+			Expected: nil,
+		},
+		{
+			Name: "duplicate plugins",
+			Content: `
+plugin "aws" {
+  source  = "github.com/terraform-linters/tflint-ruleset-aws"
+  version = "0.21.1"
+}
+plugin "aws" {
+  source  = "github.com/terraform-linters/tflint-ruleset-aws"
+  version = "0.22.0"
+}`,
+			Error: `plugin "aws" is locked more than once`,
+		},
+		{
+			Name: "missing version",
+			Content: `
+plugin "aws" {
+  source = "github.com/terraform-linters/tflint-ruleset-aws"
+}`,
+			Error: `:2,14-14: Missing required argument; The argument "version" is required, but no definition was found.`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), LockFileName)
+			if tc.Content != "" {
+				if err := os.WriteFile(path, []byte(tc.Content), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			got, err := LoadLockFile(path)
+			if err != nil {
+				if tc.Error == "" {
+					t.Fatal(err)
+				}
+				if !strings.HasSuffix(err.Error(), tc.Error) {
+					t.Fatalf("expected=%s, actual=%s", tc.Error, err)
+				}
+				return
+			}
+			if tc.Error != "" {
+				t.Fatalf("expected=%s, actual=no errors", tc.Error)
+			}
+			if diff := cmp.Diff(tc.Expected, got); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+	}
+}
+
+func Test_LockFile_Prune(t *testing.T) {
+	lock := &LockFile{
+		Plugins: []*LockedPlugin{
+			{Name: "aws", Source: "github.com/terraform-linters/tflint-ruleset-aws", Version: "0.21.1"},
+			{Name: "google", Source: "github.com/terraform-linters/tflint-ruleset-google", Version: "0.20.0"},
+			{Name: "azurerm", Source: "github.com/terraform-linters/tflint-ruleset-azurerm", Version: "0.19.0"},
+		},
+	}
+	config := &tflint.Config{
+		Plugins: map[string]*tflint.PluginConfig{
+			"aws":     {Name: "aws", Source: "github.com/terraform-linters/tflint-ruleset-aws", Version: "0.22.0"},
+			"azurerm": {Name: "azurerm"},
+		},
+	}
+
+	lock.Prune(config)
+
+	expected := []*LockedPlugin{
+		{Name: "aws", Source: "github.com/terraform-linters/tflint-ruleset-aws", Version: "0.21.1"},
+	}
+	if diff := cmp.Diff(expected, lock.Plugins); diff != "" {
+		t.Fatal(diff)
+	}
+}
+
+func Test_LockedPlugin_Matches(t *testing.T) {
+	locked := &LockedPlugin{Name: "aws", Source: "github.com/terraform-linters/tflint-ruleset-aws", Version: "0.21.1"}
+
+	cases := []struct {
+		Name     string
+		Config   *tflint.PluginConfig
+		Expected bool
+	}{
+		{
+			Name:     "same version",
+			Config:   &tflint.PluginConfig{Name: "aws", Source: locked.Source, Version: "0.21.1"},
+			Expected: true,
+		},
+		{
+			Name:     "different version",
+			Config:   &tflint.PluginConfig{Name: "aws", Source: locked.Source, Version: "0.22.0"},
+			Expected: false,
+		},
+		{
+			Name:     "different source",
+			Config:   &tflint.PluginConfig{Name: "aws", Source: "github.com/example/tflint-ruleset-aws", Version: "0.21.1"},
+			Expected: false,
+		},
+		{
+			Name:     "satisfied constraints",
+			Config:   &tflint.PluginConfig{Name: "aws", Source: locked.Source, Version: "~> 0.21"},
+			Expected: true,
+		},
+		{
+			Name:     "unsatisfied constraints",
+			Config:   &tflint.PluginConfig{Name: "aws", Source: locked.Source, Version: ">= 0.22, < 1.0"},
+			Expected: false,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			got := locked.Matches(tc.Config)
+			if got != tc.Expected {
+				t.Fatalf("expected=%t, actual=%t", tc.Expected, got)
+			}
+		})
+	}
+}
+
+func Test_platformChecksums(t *testing.T) {
+	checksummer, err := NewChecksummer(strings.NewReader(`3a61fff3689f27c89bce22893219919c629d2e10b96e7eadd5fef9f0e90bb353  tflint-ruleset-aws_darwin_amd64.zip
+482419fdeed00692304e59558b5b0d915d4727868b88a5adbbbb76f5ed1b537a  tflint-ruleset-aws_linux_amd64.zip
+db4eed4c0abcfb0b851da5bbfe8d0c71e1c2b6afe4fd627638a462c655045902  tflint-ruleset-aws-extras_linux_amd64.zip
+6e7c2a43d6b8e7e1ab4d8c2d4b8e7e1ab4d8c2d4b8e7e1ab4d8c2d4b8e7e1ab4  checksums.txt.sig
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]string{
+		"darwin_amd64": "sha256:3a61fff3689f27c89bce22893219919c629d2e10b96e7eadd5fef9f0e90bb353",
+		"linux_amd64":  "sha256:482419fdeed00692304e59558b5b0d915d4727868b88a5adbbbb76f5ed1b537a",
+	}
+	if diff := cmp.Diff(expected, platformChecksums("tflint-ruleset-aws_{os}_{arch}.zip", checksummer)); diff != "" {
+		t.Fatal(diff)
+	}
+}
+
+func Test_verifyLockedPlugin(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tflint-ruleset-aws")
+	if err := os.WriteFile(path, []byte("binary"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	// sha256 of "binary"
+	checksum := "sha256:9a3a45d01531a20e89ac6ae10b0b0beb0492acd7216a368aa062d1a5fecaf9cd"
+
+	config := &tflint.PluginConfig{
+		Name:    "aws",
+		Source:  "github.com/terraform-linters/tflint-ruleset-aws",
+		Version: "0.21.1",
+	}
+
+	cases := []struct {
+		Name  string
+		Lock  *LockFile
+		Error string
+	}{
+		{
+			Name: "matched",
+			Lock: &LockFile{Plugins: []*LockedPlugin{
+				{Name: "aws", Source: config.Source, Version: config.Version, BinaryChecksums: map[string]string{currentPlatform(): checksum}},
+			}},
+		},
+		{
+			Name:  "not recorded",
+			Lock:  &LockFile{},
+			Error: `Plugin "aws" is not recorded in .tflint.lock.hcl. Run "tflint --init" to update the lock file`,
+		},
+		{
+			Name: "version mismatch",
+			Lock: &LockFile{Plugins: []*LockedPlugin{
+				{Name: "aws", Source: config.Source, Version: "0.20.0", BinaryChecksums: map[string]string{currentPlatform(): checksum}},
+			}},
+			Error: `Plugin "aws" is locked to version 0.20.0 (source: github.com/terraform-linters/tflint-ruleset-aws), but version 0.21.1 (source: github.com/terraform-linters/tflint-ruleset-aws) is configured. Run "tflint --init --upgrade" to update the lock file`,
+		},
+		{
+			Name: "checksum mismatch",
+			Lock: &LockFile{Plugins: []*LockedPlugin{
+				{Name: "aws", Source: config.Source, Version: config.Version, BinaryChecksums: map[string]string{currentPlatform(): "sha256:0000"}},
+			}},
+			Error: `Plugin "aws" does not match .tflint.lock.hcl; checksum mismatch: locked=sha256:0000, actual=` + checksum,
+		},
+		{
+			Name: "no checksum for the platform",
+			Lock: &LockFile{Plugins: []*LockedPlugin{
+				{Name: "aws", Source: config.Source, Version: config.Version},
+			}},
+			Error: `Plugin "aws" does not match .tflint.lock.hcl; no checksum is locked for ` + currentPlatform() + `. Did you run "tflint --init"?`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			err := verifyLockedPlugin(tc.Lock, config, path)
+			if err != nil {
+				if tc.Error == "" {
+					t.Fatal(err)
+				}
+				if err.Error() != tc.Error {
+					t.Fatalf("expected=%s, actual=%s", tc.Error, err)
+				}
+				return
+			}
+			if tc.Error != "" {
+				t.Fatalf("expected=%s, actual=no errors", tc.Error)
+			}
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package plugin
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/terraform-linters/tflint/tflint"
+)
+
+// Mirror downloads the release from the source into the passed mirror directory.
+// The release is placed in the directory returned by MirrorPath, and it is laid out
+// like a GitHub release so that Install can use it in place of the source.
+//
+// Assets for all platforms in the checksums file are downloaded so that the mirror
+// can be used on other platforms. Each asset is verified against the checksums file,
+// and the checksums file is verified against the signature if a signing key is configured.
+// It returns the directory where the release is placed.
+func (c *InstallConfig) Mirror(dir string) (string, error) {
+	if c.SourceType == tflint.PluginSourceFile || c.SourceType == tflint.PluginSourceDir {
+		return "", fmt.Errorf(`plugin "%s" is installed from the local filesystem and cannot be mirrored`, c.Name)
+	}
+
+	// Mirror the newest version that satisfies the constraints
+	if c.VersionConstraints() != nil {
+		resolved, err := c.resolveUpstream()
+		if err != nil {
+			return "", err
+		}
+		return resolved.Mirror(dir)
+	}
+
+	dest := filepath.Join(dir, c.MirrorPath())
+	log.Printf("[DEBUG] Mkdir mirror dir: %s", dest)
+	if err := os.MkdirAll(dest, 0755); err != nil {
+		return "", fmt.Errorf("Failed to mkdir to %s: %w", dest, err)
+	}
+
+	source, err := c.upstreamSource()
+	if err != nil {
+		return "", err
+	}
+
+	checksumsFile, signatureFile, err := c.fetchChecksums(source)
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(checksumsFile.Name())
+	if signatureFile != nil {
+		defer os.Remove(signatureFile.Name())
+	}
+
+	checksummer, err := NewChecksummer(checksumsFile)
+	if err != nil {
+		return "", fmt.Errorf("Failed to parse checksums file: %s", err)
+	}
+
+	platforms := []string{}
+	for platform := range platformChecksums(c.assetTemplate(), checksummer) {
+		platforms = append(platforms, platform)
+	}
+	if len(platforms) == 0 {
+		return "", fmt.Errorf("no assets are found in the checksums file. Does the checksums file contain %s?", c.AssetName())
+	}
+	sort.Strings(platforms)
+
+	for _, platform := range platforms {
+		goos, goarch, _ := strings.Cut(platform, "_")
+		name := expandPlatform(c.assetTemplate(), goos, goarch)
+
+		if err := c.mirrorAsset(source, checksummer, goos, goarch, filepath.Join(dest, name)); err != nil {
+			return "", fmt.Errorf("Failed to mirror %s: %s", name, err)
+		}
+	}
+
+	if _, err := checksumsFile.Seek(0, 0); err != nil {
+		return "", err
+	}
+	if err := copyToFile(checksumsFile, filepath.Join(dest, "checksums.txt")); err != nil {
+		return "", fmt.Errorf("Failed to write checksums.txt: %s", err)
+	}
+	if signatureFile != nil {
+		signatureName := NewSignatureChecker(c).SignatureFileName()
+		if err := copyToFile(signatureFile, filepath.Join(dest, signatureName)); err != nil {
+			return "", fmt.Errorf("Failed to write %s: %s", signatureName, err)
+		}
+	}
+
+	return dest, nil
+}
+
+func (c *InstallConfig) mirrorAsset(source releaseSource, checksummer *Checksummer, goos string, goarch string, path string) error {
+	name := filepath.Base(path)
+
+	// Skip assets that are already mirrored
+	if f, err := os.Open(path); err == nil {
+		err := checksummer.Verify(name, f)
+		f.Close()
+		if err == nil {
+			log.Printf("[DEBUG] %s is already mirrored", name)
+			return nil
+		}
+	}
+
+	log.Printf("[DEBUG] Download %s", name)
+	asset, err := source.downloadAsset(goos, goarch)
+	if asset != nil {
+		defer os.Remove(asset.Name())
+	}
+	if err != nil {
+		return err
+	}
+	if err := checksummer.Verify(name, asset); err != nil {
+		return err
+	}
+	if _, err := asset.Seek(0, 0); err != nil {
+		return err
+	}
+
+	return copyToFile(asset, path)
+}
+
+func copyToFile(r io.Reader, path string) error {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = io.Copy(file, r)
+	return err
+}
@@ -0,0 +1,147 @@
+package plugin
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/terraform-linters/tflint/tflint"
+	//nolint:staticcheck
+	"golang.org/x/crypto/openpgp"
+)
+
+func Test_Mirror(t *testing.T) {
+	original := PluginRoot
+	defer func() { PluginRoot = original }()
+
+	entity, err := openpgp.NewEntity("test", "", "test@example.com", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Other platforms are also mirrored so that the mirror can be synced to other environments
+	otherPlatform := "plan9_386"
+	if currentPlatform() == otherPlatform {
+		otherPlatform = "linux_amd64"
+	}
+	releaseDir := t.TempDir()
+	writeRelease(
+		t,
+		releaseDir,
+		entity,
+		fmt.Sprintf("foo_%s_%s.zip", runtime.GOOS, runtime.GOARCH),
+		fmt.Sprintf("foo_%s.zip", otherPlatform),
+	)
+
+	server := httptest.NewTLSServer(http.FileServer(http.Dir(releaseDir)))
+	originalTransport := httpTransport
+	httpTransport = server.Client().Transport
+	defer func() { httpTransport = originalTransport }()
+
+	mirrorDir := t.TempDir()
+	pluginCfg := &tflint.PluginConfig{
+		Name:       "foo",
+		Enabled:    true,
+		Version:    "0.1.0",
+		Source:     server.URL + "/{name}_{os}_{arch}.zip",
+		SourceType: tflint.PluginSourceURL,
+		SourceHost: server.Listener.Addr().String(),
+		SigningKey: armoredPublicKey(t, entity),
+	}
+	config := NewInstallConfig(&tflint.Config{PluginMirror: mirrorDir}, pluginCfg)
+
+	dir, err := config.Mirror(mirrorDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dir != filepath.Join(mirrorDir, config.MirrorPath()) {
+		t.Fatalf("expected=%s, actual=%s", filepath.Join(mirrorDir, config.MirrorPath()), dir)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := []string{}
+	for _, entry := range entries {
+		got = append(got, entry.Name())
+	}
+	expected := []string{
+		"checksums.txt",
+		"checksums.txt.sig",
+		fmt.Sprintf("foo_%s.zip", otherPlatform),
+		fmt.Sprintf("foo_%s_%s.zip", runtime.GOOS, runtime.GOARCH),
+	}
+	sort.Strings(expected)
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Fatal(diff)
+	}
+
+	// Mirroring again skips assets already mirrored
+	if _, err := config.Mirror(mirrorDir); err != nil {
+		t.Fatal(err)
+	}
+
+	// Install from the mirror without access to the source
+	server.Close()
+
+	cases := []struct {
+		Name   string
+		Config *tflint.Config
+		Env    string
+	}{
+		{
+			Name:   "plugin_mirror",
+			Config: &tflint.Config{PluginMirror: mirrorDir},
+		},
+		{
+			Name:   "TFLINT_PLUGIN_MIRROR",
+			Config: tflint.EmptyConfig(),
+			Env:    mirrorDir,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			PluginRoot = t.TempDir()
+			t.Setenv("TFLINT_PLUGIN_MIRROR", tc.Env)
+
+			path, err := NewInstallConfig(tc.Config, pluginCfg).Install()
+			if err != nil {
+				t.Fatal(err)
+			}
+			content, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(content) != "binary" {
+				t.Fatalf("Installed binary is invalid: %s", content)
+			}
+		})
+	}
+}
+
+func Test_Mirror_local(t *testing.T) {
+	config := NewInstallConfig(tflint.EmptyConfig(), &tflint.PluginConfig{
+		Name:       "foo",
+		Enabled:    true,
+		Version:    "0.1.0",
+		Source:     "./plugins",
+		SourceType: tflint.PluginSourceDir,
+	})
+
+	_, err := config.Mirror(t.TempDir())
+	if err == nil {
+		t.Fatal("Expected an error, but got nil")
+	}
+	expected := `plugin "foo" is installed from the local filesystem and cannot be mirrored`
+	if err.Error() != expected {
+		t.Fatalf("expected=%s, actual=%s", expected, err)
+	}
+}
@@ -0,0 +1,133 @@
+package plugin
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/terraform-linters/tflint-plugin-sdk/logger"
+)
+
+// maxOutputLines is the number of the last lines of the plugin output kept for error messages.
+const maxOutputLines = 100
+
+// outputs records the stderr of plugin processes.
+//
+// go-plugin does not expose the stderr of plugin processes, but writes each line
+// to the SDK logger named after the plugin binary (e.g. "tflint-ruleset-aws: panic: ...").
+// The output of plugins started by the launcher has a nested name (e.g. "tflint: tflint-ruleset-aws: panic: ...").
+// The logger output is intercepted so that panic traces can be included in error messages.
+// Logs are still written to the stderr according to TFLINT_LOG.
+var outputs = &outputRecorder{buffers: map[string]*outputBuffer{}}
+
+var captureOutputOnce sync.Once
+
+// captureOutput starts intercepting the SDK logger output. It must be called before launching plugins.
+func captureOutput() {
+	captureOutputOnce.Do(func() {
+		l := logger.Logger()
+		resettable, ok := l.(hclog.OutputResettable)
+		if !ok {
+			return
+		}
+
+		outputs.out = os.Stderr
+		outputs.level = l.GetLevel()
+		if err := resettable.ResetOutput(&hclog.LoggerOptions{Output: outputs}); err != nil {
+			return
+		}
+		// Non-JSON lines such as panic traces are logged at the debug level
+		if outputs.level > hclog.Debug {
+			l.SetLevel(hclog.Debug)
+		}
+	})
+}
+
+type outputRecorder struct {
+	out   io.Writer
+	level hclog.Level
+
+	mu      sync.Mutex
+	buffers map[string]*outputBuffer
+}
+
+var _ hclog.LevelWriter = (*outputRecorder)(nil)
+
+// register starts recording the output logged with the passed name. See outputName.
+func (r *outputRecorder) register(name string) *outputBuffer {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	buf := &outputBuffer{name: name}
+	r.buffers[name] = buf
+	return buf
+}
+
+func (r *outputRecorder) unregister(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.buffers, name)
+}
+
+func (r *outputRecorder) Write(p []byte) (int, error) {
+	return r.LevelWrite(hclog.NoLevel, p)
+}
+
+func (r *outputRecorder) LevelWrite(level hclog.Level, p []byte) (int, error) {
+	r.mu.Lock()
+	// Names can be prefixes of each other, like "tflint" (the bundled plugin) and
+	// "tflint: tflint-ruleset-aws" (a plugin started by the launcher), so the longest one wins.
+	var matched *outputBuffer
+	var line string
+	for name, buf := range r.buffers {
+		if matched != nil && len(name) <= len(matched.name) {
+			continue
+		}
+		if idx := bytes.Index(p, []byte(" "+name+": ")); idx >= 0 {
+			matched, line = buf, string(bytes.TrimRight(p[idx+len(name)+3:], "\n"))
+			continue
+		}
+		// Empty lines are logged without the separator
+		if bytes.HasSuffix(p, []byte(" "+name+"\n")) {
+			matched, line = buf, ""
+		}
+	}
+	if matched != nil {
+		matched.append(line)
+	}
+	r.mu.Unlock()
+
+	if level < r.level {
+		return len(p), nil
+	}
+	return r.out.Write(p)
+}
+
+// outputBuffer keeps the last lines of the plugin output.
+type outputBuffer struct {
+	name string
+
+	mu    sync.Mutex
+	lines []string
+}
+
+func (b *outputBuffer) append(line string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.lines = append(b.lines, line)
+	if len(b.lines) > maxOutputLines {
+		b.lines = b.lines[len(b.lines)-maxOutputLines:]
+	}
+}
+
+func (b *outputBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return strings.Join(b.lines, "\n")
+}
@@ -0,0 +1,94 @@
+package plugin
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+)
+
+func Test_outputRecorder(t *testing.T) {
+	out := new(bytes.Buffer)
+	recorder := &outputRecorder{out: out, level: hclog.Warn, buffers: map[string]*outputBuffer{}}
+
+	// Same options as the SDK logger
+	logger := hclog.New(&hclog.LoggerOptions{
+		Level:           hclog.Debug,
+		Output:          recorder,
+		TimeFormat:      "15:04:05",
+		IncludeLocation: true,
+	})
+
+	foo := recorder.register("tflint-ruleset-foo")
+	bar := recorder.register("tflint-ruleset-bar")
+
+	// go-plugin logs the stderr of plugin processes with the logger named after the binary
+	logger.Named("tflint-ruleset-foo").Debug("panic: boom")
+	logger.Named("tflint-ruleset-foo").Debug("")
+	logger.Named("tflint-ruleset-foo").Debug("goroutine 1 [running]:")
+	logger.Named("tflint-ruleset-bar").Warn("[WARN] deprecated")
+	logger.Debug("starting plugin", "path", "/path/to/tflint-ruleset-foo")
+
+	if got := foo.String(); got != "panic: boom\n\ngoroutine 1 [running]:" {
+		t.Fatalf("unexpected output of foo: %q", got)
+	}
+	if got := bar.String(); got != "[WARN] deprecated" {
+		t.Fatalf("unexpected output of bar: %q", got)
+	}
+
+	// Only logs at the original level or higher are written
+	if strings.Contains(out.String(), "panic: boom") {
+		t.Fatalf("Expected debug logs to be discarded, but got %q", out)
+	}
+	if !strings.Contains(out.String(), "[WARN] deprecated") {
+		t.Fatalf("Expected warning logs to be written, but got %q", out)
+	}
+
+	recorder.unregister("tflint-ruleset-foo")
+	logger.Named("tflint-ruleset-foo").Debug("after unregister")
+	if strings.Contains(foo.String(), "after unregister") {
+		t.Fatalf("Expected not to record after unregister, but got %q", foo)
+	}
+}
+
+func Test_outputRecorder_launcher(t *testing.T) {
+	recorder := &outputRecorder{out: new(bytes.Buffer), level: hclog.Warn, buffers: map[string]*outputBuffer{}}
+	logger := hclog.New(&hclog.LoggerOptions{
+		Level:           hclog.Debug,
+		Output:          recorder,
+		TimeFormat:      "15:04:05",
+		IncludeLocation: true,
+	})
+
+	bundled := recorder.register("tflint")
+	foo := recorder.register("tflint: tflint-ruleset-foo")
+
+	// The launcher prefixes the output of plugins with the binary name, see relayLine
+	logger.Named("tflint").Debug("tflint-ruleset-foo: panic: boom")
+	logger.Named("tflint").Debug("tflint-ruleset-foo")
+	logger.Named("tflint").Debug("panic: bundled")
+
+	if got := foo.String(); got != "panic: boom\n" {
+		t.Fatalf("unexpected output of foo: %q", got)
+	}
+	if got := bundled.String(); got != "panic: bundled" {
+		t.Fatalf("unexpected output of the bundled plugin: %q", got)
+	}
+}
+
+func Test_outputBuffer(t *testing.T) {
+	buf := &outputBuffer{}
+	for i := 0; i < maxOutputLines+10; i++ {
+		buf.append(fmt.Sprint(i))
+	}
+
+	lines := strings.Split(buf.String(), "\n")
+	if len(lines) != maxOutputLines {
+		t.Fatalf("expected=%d lines, actual=%d lines", maxOutputLines, len(lines))
+	}
+	if lines[0] != "10" {
+		t.Fatalf("Expected to keep the last lines, but the first line is %s", lines[0])
+	}
+}
@@ -1,6 +1,9 @@
 package plugin
 
 import (
+	"context"
+	"fmt"
+
 	plugin "github.com/hashicorp/go-plugin"
 	"github.com/hashicorp/go-version"
 	"github.com/terraform-linters/tflint-plugin-sdk/plugin/host2plugin"
@@ -22,6 +25,16 @@ type Plugin struct {
 	RuleSets map[string]*host2plugin.Client
 
 	clients map[string]*plugin.Client
+	// outputs is the recorded stderr of plugin processes
+	outputs map[string]*outputBuffer
+	// paths is the resolved binary paths of plugins, except for the bundled plugin
+	paths map[string]string
+}
+
+// Path returns the path of the plugin binary resolved from the plugin directories.
+// It returns an empty string for the bundled plugin.
+func (p *Plugin) Path(name string) string {
+	return p.paths[name]
 }
 
 // Clean is a helper for ending plugin processes
@@ -29,4 +42,76 @@ func (p *Plugin) Clean() {
 	for _, client := range p.clients {
 		client.Kill()
 	}
+	for _, buf := range p.outputs {
+		outputs.unregister(buf.name)
+	}
+}
+
+// Check calls the check of the ruleset with the passed server.
+//
+// The gRPC call cannot be canceled from the host side, so if the context is done
+// before the check finishes, the plugin process is killed to abort the check.
+// If the plugin process crashes, the returned error includes the output of the plugin process
+// such as a panic trace. The plugin cannot be used after it is killed or crashed.
+func (p *Plugin) Check(ctx context.Context, name string, server *GRPCServer) error {
+	ruleset, exists := p.RuleSets[name]
+	if !exists {
+		return fmt.Errorf(`Plugin "%s" is not running`, name)
+	}
+
+	ch := make(chan error, 1)
+	go func() {
+		ch <- ruleset.Check(server)
+	}()
+
+	select {
+	case err := <-ch:
+		if err == nil {
+			return nil
+		}
+		if !p.alive(name) {
+			// Wait for the process to exit to read the rest of the output
+			p.kill(name)
+			return p.crashError(name, err)
+		}
+		return err
+
+	case <-ctx.Done():
+		p.kill(name)
+		// Wait for the call to return so that the runner is not touched after the check
+		<-ch
+
+		return fmt.Errorf(`Plugin "%s" was killed; %w`, name, context.Cause(ctx))
+	}
+}
+
+// alive returns whether the plugin process is still reachable.
+func (p *Plugin) alive(name string) bool {
+	client, exists := p.clients[name]
+	if !exists || client.Exited() {
+		return false
+	}
+	rpcClient, err := client.Client()
+	if err != nil {
+		return false
+	}
+	return rpcClient.Ping() == nil
+}
+
+func (p *Plugin) kill(name string) {
+	if client, exists := p.clients[name]; exists {
+		client.Kill()
+	}
+}
+
+func (p *Plugin) crashError(name string, err error) error {
+	output := ""
+	if buf, exists := p.outputs[name]; exists {
+		output = buf.String()
+	}
+
+	if output == "" {
+		return fmt.Errorf(`Plugin "%s" crashed; %w`, name, err)
+	}
+	return fmt.Errorf("Plugin \"%s\" crashed; %w\n\nPlugin output:\n%s", name, err, output)
 }
@@ -0,0 +1,88 @@
+package plugin
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/terraform-linters/tflint/tflint"
+)
+
+func TestMain(m *testing.M) {
+	// Plugins with env are started by the test binary as the launcher
+	if len(os.Args) > 1 && os.Args[1] == "--act-as-plugin-launcher" {
+		code, err := LaunchPlugin()
+		if err != nil {
+			os.Stderr.WriteString(err.Error() + "\n")
+		}
+		os.Exit(code)
+	}
+	os.Exit(m.Run())
+}
+
+func TestCheck_crash(t *testing.T) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	original := PluginRoot
+	PluginRoot = filepath.Join(cwd, "test-fixtures", "plugins")
+	defer func() { PluginRoot = original }()
+
+	tests := []struct {
+		name      string
+		isolation bool
+	}{
+		{
+			name:      "plugin",
+			isolation: false,
+		},
+		{
+			name:      "isolated plugin",
+			isolation: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			plugin, err := Discovery(&tflint.Config{
+				PluginEnvIsolation: test.isolation,
+				Plugins: map[string]*tflint.PluginConfig{
+					"crash": {
+						Name:    "crash",
+						Enabled: true,
+					},
+				},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer plugin.Clean()
+
+			// The version constraints must be checked before applying the config
+			if _, err := plugin.RuleSets["crash"].VersionConstraints(); err != nil {
+				t.Fatal(err)
+			}
+			if err := plugin.RuleSets["crash"].ApplyGlobalConfig(tflint.EmptyConfig().ToPluginConfig()); err != nil {
+				t.Fatal(err)
+			}
+
+			runner := tflint.TestRunner(t, map[string]string{"main.tf": ""})
+			server := NewGRPCServer(runner, runner, runner.Files(), SDKVersion)
+
+			err = plugin.Check(context.Background(), "crash", server)
+			if err == nil {
+				t.Fatal("Expected the plugin to crash, but no error occurred")
+			}
+			if !strings.Contains(err.Error(), `Plugin "crash" crashed`) {
+				t.Fatalf("Expected a crash error, but got %s", err)
+			}
+			if !strings.Contains(err.Error(), "panic: crash plugin panicked") {
+				t.Fatalf("Expected the panic trace in the error, but got %s", err)
+			}
+		})
+	}
+}
@@ -9,8 +9,10 @@ import (
 	hcl "github.com/hashicorp/hcl/v2"
 	"github.com/terraform-linters/tflint-plugin-sdk/hclext"
 	"github.com/terraform-linters/tflint-plugin-sdk/plugin/plugin2host"
+	"github.com/terraform-linters/tflint-plugin-sdk/terraform/lang/marks"
 	sdk "github.com/terraform-linters/tflint-plugin-sdk/tflint"
 	"github.com/terraform-linters/tflint/terraform"
+	tfmarks "github.com/terraform-linters/tflint/terraform/lang/marks"
 	"github.com/terraform-linters/tflint/tflint"
 	"github.com/zclconf/go-cty/cty"
 )
@@ -21,6 +23,9 @@ type GRPCServer struct {
 	rootRunner       *tflint.Runner
 	files            map[string]*hcl.File
 	clientSDKVersion *version.Version
+
+	profiler   *tflint.Profiler
+	pluginName string
 }
 
 var _ plugin2host.Server = (*GRPCServer)(nil)
@@ -30,6 +35,21 @@ func NewGRPCServer(runner *tflint.Runner, rootRunner *tflint.Runner, files map[s
 	return &GRPCServer{runner: runner, rootRunner: rootRunner, files: files, clientSDKVersion: sdkVersion}
 }
 
+// WithProfiler sets the profiler to record requests from the plugin with the passed name.
+func (s *GRPCServer) WithProfiler(profiler *tflint.Profiler, name string) *GRPCServer {
+	s.profiler = profiler
+	s.pluginName = name
+	return s
+}
+
+// profile starts recording the request if the profiler is set.
+func (s *GRPCServer) profile(request string) func() {
+	if s.profiler == nil {
+		return func() {}
+	}
+	return s.profiler.Start(request, "plugin", s.pluginName, "module", s.runner.ModuleName())
+}
+
 // GetOriginalwd returns the original working directory.
 func (s *GRPCServer) GetOriginalwd() string {
 	return s.runner.Ctx.Meta.OriginalWorkingDir
@@ -42,6 +62,8 @@ func (s *GRPCServer) GetModulePath() []string {
 
 // GetModuleContent returns module content based on the passed schema and options.
 func (s *GRPCServer) GetModuleContent(bodyS *hclext.BodySchema, opts sdk.GetModuleContentOption) (*hclext.BodyContent, hcl.Diagnostics) {
+	defer s.profile("GetModuleContent")()
+
 	var module *terraform.Module
 	var ctx *terraform.Evaluator
 
@@ -72,8 +94,9 @@ func (s *GRPCServer) GetModuleContent(bodyS *hclext.BodySchema, opts sdk.GetModu
 
 // GetFile returns the hcl.File based on passed the file name.
 func (s *GRPCServer) GetFile(name string) (*hcl.File, error) {
-	// Considering that autofix has been applied, prioritize returning the value of runner.Files().
-	if file, exists := s.runner.Files()[name]; exists {
+	// Considering that autofix has been applied, prioritize returning the value of runner.File().
+	// Test files are not included in GetFiles, but can be retrieved by name.
+	if file := s.runner.File(name); file != nil {
 		return file, nil
 	}
 	// If the file is not found in the current module, it may be in other modules (e.g. root module).
@@ -122,6 +145,8 @@ func (s *GRPCServer) GetRuleConfigContent(name string, bodyS *hclext.BodySchema)
 
 // EvaluateExpr returns the value of the passed expression.
 func (s *GRPCServer) EvaluateExpr(expr hcl.Expression, opts sdk.EvaluateExprOption) (cty.Value, error) {
+	defer s.profile("EvaluateExpr")()
+
 	var runner *tflint.Runner
 	switch opts.ModuleCtx {
 	case sdk.SelfModuleCtxType:
@@ -134,10 +159,27 @@ func (s *GRPCServer) EvaluateExpr(expr hcl.Expression, opts sdk.EvaluateExprOpti
 	if diags.HasErrors() {
 		return val, diags
 	}
+	// Record expressions that plugins will skip to explain them later.
+	if reason := tflint.UnevaluatedReasonOf(val, *opts.WantType); reason != "" {
+		runner.RecordUnevaluated(expr, reason)
+	}
 
 	// SDK v0.16+ introduces client-side handling of unknown/NULL/sensitive values.
 	if s.clientSDKVersion != nil && s.clientSDKVersion.GreaterThanOrEqual(version.Must(version.NewVersion("0.16.0"))) {
-		return val, nil
+		// The plugin protocol cannot represent ephemeral marks, so ephemeral values
+		// are sent as sensitive values to ensure that plugins ignore them as well.
+		return ephemeralAsSensitive(val), nil
+	}
+
+	if tfmarks.Contains(val, tfmarks.Ephemeral) {
+		err := fmt.Errorf(
+			"ephemeral value found in %s:%d%w",
+			expr.Range().Filename,
+			expr.Range().Start.Line,
+			sdk.ErrSensitive,
+		)
+		log.Printf("[INFO] %s. TFLint ignores expressions with ephemeral values.", err)
+		return cty.NullVal(cty.NilType), err
 	}
 
 	if val.ContainsMarked() {
@@ -187,6 +229,22 @@ func (s *GRPCServer) EvaluateExpr(expr hcl.Expression, opts sdk.EvaluateExprOpti
 	return val, nil
 }
 
+// ephemeralAsSensitive replaces ephemeral marks in the given value with sensitive marks.
+func ephemeralAsSensitive(val cty.Value) cty.Value {
+	if !tfmarks.Contains(val, tfmarks.Ephemeral) {
+		return val
+	}
+
+	unmarked, pvm := val.UnmarkDeepWithPaths()
+	for _, pv := range pvm {
+		if _, exists := pv.Marks[tfmarks.Ephemeral]; exists {
+			delete(pv.Marks, tfmarks.Ephemeral)
+			pv.Marks[marks.Sensitive] = struct{}{}
+		}
+	}
+	return unmarked.MarkWithPaths(pvm)
+}
+
 // EmitIssue stores an issue in the server based on passed rule, message, and location.
 func (s *GRPCServer) EmitIssue(rule sdk.Rule, message string, location hcl.Range, fixable bool) (bool, error) {
 	// If the issue range represents an expression, it is emitted based on that context.
@@ -228,6 +228,12 @@ resource "aws_instance" "bar" {
 resource "aws_instance" "foo" {
 	instance_type = "t2.nano"
 }`,
+		},
+		{
+			Name: "get test file",
+			Arg:  "main.tftest.hcl",
+			Want: `
+run "foo" {}`,
 		},
 		{
 			Name: "get autofixed file",
@@ -256,6 +262,8 @@ resource "aws_instance" "foo" {
 resource "aws_instance" "bar" {
 	instance_type = "m5.2xlarge"
 }`,
+				"main.tftest.hcl": `
+run "foo" {}`,
 			})
 			rootRunner := tflint.TestRunner(t, map[string]string{
 				"test_on_root1.tf": `
@@ -292,10 +300,14 @@ resource "aws_instance" "foo" {
 }
 
 func TestGetFiles(t *testing.T) {
-	runner := tflint.TestRunner(t, map[string]string{"main.tf": `
+	runner := tflint.TestRunner(t, map[string]string{
+		"main.tf": `
 resource "aws_instance" "foo" {
 	instance_type = "t2.micro"
-}`})
+}`,
+		"main.tftest.hcl": `
+run "foo" {}`,
+	})
 	rootRunner := tflint.TestRunner(t, map[string]string{"main.tf": `
 resource "aws_instance" "bar" {
 	instance_type = "m5.2xlarge"
@@ -311,10 +323,13 @@ resource "aws_instance" "bar" {
 		{
 			Name: "self module context",
 			Arg:  sdk.SelfModuleCtxType,
-			Want: map[string]string{"main.tf": `
+			// Test files are not included
+			Want: map[string]string{
+				"main.tf": `
 resource "aws_instance" "foo" {
 	instance_type = "t2.micro"
-}`},
+}`,
+			},
 		},
 		{
 			Name: "root module context",
@@ -457,6 +472,11 @@ variable "sensitive" {
 	default   = "foo"
 }
 
+variable "ephemeral" {
+	ephemeral = true
+	default   = "foo"
+}
+
 variable "no_default" {}
 
 variable "null" {
@@ -542,6 +562,34 @@ variable "foo" {
 				return err == nil || !errors.Is(err, sdk.ErrSensitive)
 			},
 		},
+		{
+			Name: "ephemeral value",
+			Args: func() (hcl.Expression, sdk.EvaluateExprOption) {
+				return hclExpr(`var.ephemeral`), sdk.EvaluateExprOption{WantType: &cty.String, ModuleCtx: sdk.SelfModuleCtxType}
+			},
+			Want:     cty.StringVal("foo").Mark(marks.Sensitive),
+			ErrCheck: neverHappend,
+		},
+		{
+			Name: "ephemeral value in object",
+			Args: func() (hcl.Expression, sdk.EvaluateExprOption) {
+				ty := cty.Object(map[string]cty.Type{"value": cty.String})
+				return hclExpr(`{ value = var.ephemeral }`), sdk.EvaluateExprOption{WantType: &ty, ModuleCtx: sdk.SelfModuleCtxType}
+			},
+			Want:     cty.ObjectVal(map[string]cty.Value{"value": cty.StringVal("foo").Mark(marks.Sensitive)}),
+			ErrCheck: neverHappend,
+		},
+		{
+			Name: "ephemeral value (SDK v0.15)",
+			Args: func() (hcl.Expression, sdk.EvaluateExprOption) {
+				return hclExpr(`var.ephemeral`), sdk.EvaluateExprOption{WantType: &cty.String, ModuleCtx: sdk.SelfModuleCtxType}
+			},
+			Want:       cty.NullVal(cty.NilType),
+			SDKVersion: sdkv15,
+			ErrCheck: func(err error) bool {
+				return err == nil || !errors.Is(err, sdk.ErrSensitive)
+			},
+		},
 		{
 			Name: "no default",
 			Args: func() (hcl.Expression, sdk.EvaluateExprOption) {
@@ -643,6 +691,62 @@ variable "foo" {
 	}
 }
 
+func TestEvaluateExpr_recordUnevaluated(t *testing.T) {
+	runner := tflint.TestRunner(t, map[string]string{"main.tf": `
+variable "foo" {
+	default = "bar"
+}
+
+variable "sensitive" {
+	sensitive = true
+	default   = "foo"
+}
+
+variable "no_default" {}
+
+variable "null" {
+	type    = string
+	default = null
+}
+`})
+
+	server := NewGRPCServer(runner, runner, runner.Files(), SDKVersion)
+
+	evaluate := func(src string, wantType cty.Type) {
+		file, diags := hclsyntax.ParseConfig([]byte(src), "test.tf", hcl.InitialPos)
+		if diags.HasErrors() {
+			t.Fatal(diags)
+		}
+		attributes, diags := file.Body.JustAttributes()
+		if diags.HasErrors() {
+			t.Fatal(diags)
+		}
+		if _, err := server.EvaluateExpr(attributes["expr"].Expr, sdk.EvaluateExprOption{WantType: &wantType, ModuleCtx: sdk.SelfModuleCtxType}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	evaluate(`expr = var.foo`, cty.String)
+	evaluate(`expr = var.no_default`, cty.String)
+	evaluate("\nexpr = var.sensitive", cty.String)
+	evaluate("\n\nexpr = var.null", cty.String)
+	// Plugins handle unknown values by themselves if they want cty.DynamicPseudoType
+	evaluate("\n\n\nexpr = var.no_default", cty.DynamicPseudoType)
+
+	got := map[int]tflint.UnevaluatedReason{}
+	for _, expr := range runner.LookupUnevaluated() {
+		got[expr.Range.Start.Line] = expr.Reason
+	}
+	want := map[int]tflint.UnevaluatedReason{
+		1: tflint.UnevaluatedUnknown,
+		2: tflint.UnevaluatedSensitive,
+		3: tflint.UnevaluatedNull,
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Error(diff)
+	}
+}
+
 type testRule struct {
 	sdk.DefaultRule
 }
@@ -20,7 +20,7 @@ func NewSignatureChecker(config *InstallConfig) *SignatureChecker {
 	return &SignatureChecker{config: config}
 }
 
-// GetSigningKey returns an ASCII armored signing key.
+// GetSigningKey returns a signing key.
 // If the plugin is under the terraform-linters organization, you can use the built-in key even if the signing_key is omitted.
 func (c *SignatureChecker) GetSigningKey() string {
 	if c.config.SigningKey != "" {
@@ -38,14 +38,62 @@ func (c *SignatureChecker) HasSigningKey() bool {
 	return c.GetSigningKey() != ""
 }
 
+// SignatureFileName returns the name of the signature file of the checksums file.
+// This is checksums.txt.sig except for schemes with another convention, like checksums.txt.minisig.
+func (c *SignatureChecker) SignatureFileName() string {
+	return "checksums.txt" + detectSignatureScheme(c.GetSigningKey()).signatureExt()
+}
+
 // Verify returns the results of signature verification.
-// The signing key must be ASCII armored and the signature must be in binary OpenPGP format.
+// The scheme is detected from the signing key. See signatureSchemes for supported schemes.
 func (c *SignatureChecker) Verify(target, signature io.Reader) error {
 	key := c.GetSigningKey()
 	if key == "" {
 		return fmt.Errorf("No signing key configured")
 	}
 
+	return detectSignatureScheme(key).verify(key, target, signature)
+}
+
+// signatureScheme is a kind of signing keys and signatures.
+type signatureScheme interface {
+	// detect returns whether the signing key is for this scheme.
+	detect(key string) bool
+	// signatureExt returns the conventional suffix of signature files, like ".sig".
+	signatureExt() string
+	// verify verifies the signature of the target with the signing key.
+	verify(key string, target, signature io.Reader) error
+}
+
+// signatureSchemes are supported signature schemes, in order of detection.
+// Signing keys that do not match any of them are treated as OpenPGP keys.
+var signatureSchemes = []signatureScheme{
+	openPGPScheme{},
+	sshScheme{},
+	minisignScheme{},
+}
+
+func detectSignatureScheme(key string) signatureScheme {
+	for _, scheme := range signatureSchemes {
+		if scheme.detect(key) {
+			return scheme
+		}
+	}
+	return openPGPScheme{}
+}
+
+// openPGPScheme verifies binary OpenPGP signatures with an ASCII armored public key.
+type openPGPScheme struct{}
+
+func (openPGPScheme) detect(key string) bool {
+	return strings.Contains(key, "-----BEGIN PGP PUBLIC KEY BLOCK-----")
+}
+
+func (openPGPScheme) signatureExt() string {
+	return ".sig"
+}
+
+func (openPGPScheme) verify(key string, target, signature io.Reader) error {
 	reader := strings.NewReader(key)
 	keyring, err := openpgp.ReadArmoredKeyRing(reader)
 	if err != nil {
@@ -0,0 +1,126 @@
+package plugin
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"strings"
+
+	"golang.org/x/crypto/blake2b"
+)
+
+// minisignScheme verifies minisign signatures.
+// The signing key is a minisign public key, with or without the untrusted comment line, like:
+//
+//	untrusted comment: minisign public key 0123456789ABCDEF
+//	RWTvzauJZ0UjAQ...
+//
+// Both legacy ("Ed") and pre-hashed ("ED") signatures are supported.
+// The trusted comment is also verified by the global signature.
+type minisignScheme struct{}
+
+const (
+	minisignAlgorithm       = "Ed"
+	minisignHashedAlgorithm = "ED"
+	minisignKeyIDSize       = 8
+)
+
+func (minisignScheme) detect(key string) bool {
+	lines := minisignLines(key)
+	if len(lines) == 0 {
+		return false
+	}
+	if strings.HasPrefix(lines[0], "untrusted comment:") {
+		return true
+	}
+	// Public keys without comments are 42 bytes starting with "Ed", so they always start with "RW"
+	return len(lines) == 1 && len(lines[0]) == 56 && strings.HasPrefix(lines[0], "RW")
+}
+
+func (minisignScheme) signatureExt() string {
+	return ".minisig"
+}
+
+func (minisignScheme) verify(key string, target, signature io.Reader) error {
+	keyID, pub, err := parseMinisignPublicKey(key)
+	if err != nil {
+		return err
+	}
+
+	content, err := io.ReadAll(signature)
+	if err != nil {
+		return err
+	}
+	lines := minisignLines(string(content))
+	if len(lines) != 4 || !strings.HasPrefix(lines[0], "untrusted comment:") || !strings.HasPrefix(lines[2], "trusted comment: ") {
+		return fmt.Errorf(`minisign: signature is not a minisign signature. Sign with "minisign -S"`)
+	}
+
+	sig, err := base64.StdEncoding.DecodeString(lines[1])
+	if err != nil || len(sig) != 2+minisignKeyIDSize+ed25519.SignatureSize {
+		return fmt.Errorf("minisign: invalid signature")
+	}
+	algorithm, sigKeyID, sig := string(sig[:2]), sig[2:2+minisignKeyIDSize], sig[2+minisignKeyIDSize:]
+	if !bytes.Equal(sigKeyID, keyID) {
+		return fmt.Errorf("minisign: signature made by unknown key %016X", binary.LittleEndian.Uint64(sigKeyID))
+	}
+
+	message, err := io.ReadAll(target)
+	if err != nil {
+		return err
+	}
+	switch algorithm {
+	case minisignAlgorithm:
+	case minisignHashedAlgorithm:
+		sum := blake2b.Sum512(message)
+		message = sum[:]
+	default:
+		return fmt.Errorf(`minisign: unsupported signature algorithm "%s"`, algorithm)
+	}
+	if !ed25519.Verify(pub, message, sig) {
+		return fmt.Errorf("minisign: invalid signature")
+	}
+
+	globalSig, err := base64.StdEncoding.DecodeString(lines[3])
+	if err != nil || len(globalSig) != ed25519.SignatureSize {
+		return fmt.Errorf("minisign: invalid global signature")
+	}
+	trustedComment := strings.TrimPrefix(lines[2], "trusted comment: ")
+	if !ed25519.Verify(pub, append(append([]byte{}, sig...), trustedComment...), globalSig) {
+		return fmt.Errorf("minisign: invalid global signature")
+	}
+	return nil
+}
+
+func parseMinisignPublicKey(key string) (keyID []byte, pub ed25519.PublicKey, err error) {
+	lines := minisignLines(key)
+	if len(lines) > 0 && strings.HasPrefix(lines[0], "untrusted comment:") {
+		lines = lines[1:]
+	}
+	if len(lines) != 1 {
+		return nil, nil, fmt.Errorf("minisign: invalid public key. Must be the content of the public key file like \"RW...\"")
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(lines[0])
+	if err != nil || len(decoded) != 2+minisignKeyIDSize+ed25519.PublicKeySize {
+		return nil, nil, fmt.Errorf("minisign: invalid public key. Must be the content of the public key file like \"RW...\"")
+	}
+	if string(decoded[:2]) != minisignAlgorithm {
+		return nil, nil, fmt.Errorf(`minisign: unsupported public key algorithm "%s"`, decoded[:2])
+	}
+	return decoded[2 : 2+minisignKeyIDSize], ed25519.PublicKey(decoded[2+minisignKeyIDSize:]), nil
+}
+
+// minisignLines returns non-empty lines without surrounding spaces.
+func minisignLines(s string) []string {
+	lines := []string{}
+	for _, line := range strings.Split(s, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
@@ -0,0 +1,222 @@
+package plugin
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"fmt"
+	"hash"
+	"io"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// sshSignatureNamespace is the namespace of signatures.
+// Signatures must be created by "ssh-keygen -Y sign -n file".
+const sshSignatureNamespace = "file"
+
+// sshScheme verifies SSH signatures created by "ssh-keygen -Y sign".
+// The signing key is in the allowed signers format of ssh-keygen(1), like:
+//
+//	release@example.com namespaces="file" ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAA...
+//
+// Principals are not checked because there is no identity to verify against.
+// A public key without principals, like "ssh-ed25519 AAAA...", is also accepted.
+type sshScheme struct{}
+
+func (sshScheme) detect(key string) bool {
+	for _, line := range strings.Split(key, "\n") {
+		for _, field := range strings.Fields(line) {
+			if isSSHKeyType(field) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (sshScheme) signatureExt() string {
+	return ".sig"
+}
+
+func (sshScheme) verify(key string, target, signature io.Reader) error {
+	signers, err := parseAllowedSigners(key)
+	if err != nil {
+		return err
+	}
+
+	sig, err := parseSSHSignature(signature)
+	if err != nil {
+		return err
+	}
+	if sig.Namespace != sshSignatureNamespace {
+		return fmt.Errorf(`ssh: signature namespace is "%s", but must be "%s". Sign with "ssh-keygen -Y sign -n %s"`, sig.Namespace, sshSignatureNamespace, sshSignatureNamespace)
+	}
+
+	pub, err := ssh.ParsePublicKey(sig.PublicKey)
+	if err != nil {
+		return fmt.Errorf("ssh: invalid public key in signature: %w", err)
+	}
+	var signer *allowedSigner
+	for _, s := range signers {
+		if bytes.Equal(s.key.Marshal(), pub.Marshal()) {
+			signer = s
+			break
+		}
+	}
+	if signer == nil {
+		return fmt.Errorf("ssh: signature made by unknown key %s", ssh.FingerprintSHA256(pub))
+	}
+	if !signer.allows(sig.Namespace) {
+		return fmt.Errorf(`ssh: key %s is not allowed to sign in the "%s" namespace`, ssh.FingerprintSHA256(pub), sig.Namespace)
+	}
+
+	var h hash.Hash
+	switch sig.HashAlgorithm {
+	case "sha256":
+		h = sha256.New()
+	case "sha512":
+		h = sha512.New()
+	default:
+		return fmt.Errorf(`ssh: unsupported hash algorithm "%s"`, sig.HashAlgorithm)
+	}
+	if _, err := io.Copy(h, target); err != nil {
+		return err
+	}
+
+	var blob ssh.Signature
+	if err := ssh.Unmarshal(sig.Signature, &blob); err != nil {
+		return fmt.Errorf("ssh: invalid signature: %w", err)
+	}
+	// SHA-1 is not allowed as well as ssh-keygen
+	if blob.Format == ssh.KeyAlgoRSA {
+		return fmt.Errorf(`ssh: unsupported signature algorithm "%s"`, blob.Format)
+	}
+
+	signed := append([]byte("SSHSIG"), ssh.Marshal(struct {
+		Namespace     string
+		Reserved      string
+		HashAlgorithm string
+		Hash          []byte
+	}{
+		Namespace:     sig.Namespace,
+		Reserved:      sig.Reserved,
+		HashAlgorithm: sig.HashAlgorithm,
+		Hash:          h.Sum(nil),
+	})...)
+	if err := pub.Verify(signed, &blob); err != nil {
+		return fmt.Errorf("ssh: invalid signature: %w", err)
+	}
+	return nil
+}
+
+// allowedSigner is an entry of the allowed signers.
+type allowedSigner struct {
+	key ssh.PublicKey
+	// namespaces is nil if the key is allowed to sign in any namespaces.
+	namespaces []string
+}
+
+func (s *allowedSigner) allows(namespace string) bool {
+	if s.namespaces == nil {
+		return true
+	}
+	for _, ns := range s.namespaces {
+		if ns == namespace {
+			return true
+		}
+	}
+	return false
+}
+
+func parseAllowedSigners(key string) ([]*allowedSigner, error) {
+	signers := []*allowedSigner{}
+
+	for i, line := range strings.Split(key, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		// Skip principals, the first field, unless the line starts with a key type
+		if fields := strings.Fields(line); !isSSHKeyType(fields[0]) {
+			line = strings.TrimSpace(strings.TrimPrefix(line, fields[0]))
+		}
+
+		pub, _, options, _, err := ssh.ParseAuthorizedKey([]byte(line))
+		if err != nil {
+			return nil, fmt.Errorf("ssh: invalid allowed signers at line %d: %w", i+1, err)
+		}
+
+		signer := &allowedSigner{key: pub}
+		for _, option := range options {
+			name, value, _ := strings.Cut(option, "=")
+			switch strings.ToLower(name) {
+			case "namespaces":
+				signer.namespaces = strings.Split(strings.Trim(value, `"`), ",")
+			default:
+				return nil, fmt.Errorf(`ssh: invalid allowed signers at line %d: option "%s" is not supported`, i+1, name)
+			}
+		}
+		signers = append(signers, signer)
+	}
+
+	if len(signers) == 0 {
+		return nil, fmt.Errorf("ssh: no keys found in allowed signers")
+	}
+	return signers, nil
+}
+
+func isSSHKeyType(s string) bool {
+	for _, prefix := range []string{"ssh-", "ecdsa-sha2-", "sk-ssh-", "sk-ecdsa-sha2-"} {
+		if strings.HasPrefix(s, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// sshSignature is the signature blob described in PROTOCOL.sshsig of OpenSSH.
+type sshSignature struct {
+	Version       uint32
+	PublicKey     []byte
+	Namespace     string
+	Reserved      string
+	HashAlgorithm string
+	Signature     []byte
+}
+
+func parseSSHSignature(r io.Reader) (*sshSignature, error) {
+	const (
+		header = "-----BEGIN SSH SIGNATURE-----"
+		footer = "-----END SSH SIGNATURE-----"
+	)
+
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	armored := strings.TrimSpace(string(content))
+	if !strings.HasPrefix(armored, header) || !strings.HasSuffix(armored, footer) {
+		return nil, fmt.Errorf(`ssh: signature is not an SSH signature. Sign with "ssh-keygen -Y sign"`)
+	}
+	encoded := strings.Join(strings.Fields(armored[len(header):len(armored)-len(footer)]), "")
+	blob, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("ssh: invalid signature: %w", err)
+	}
+
+	if !bytes.HasPrefix(blob, []byte("SSHSIG")) {
+		return nil, fmt.Errorf("ssh: invalid signature: magic preamble not found")
+	}
+	var sig sshSignature
+	if err := ssh.Unmarshal(blob[len("SSHSIG"):], &sig); err != nil {
+		return nil, fmt.Errorf("ssh: invalid signature: %w", err)
+	}
+	if sig.Version != 1 {
+		return nil, fmt.Errorf("ssh: unsupported signature version %d", sig.Version)
+	}
+	return &sig, nil
+}
@@ -1,6 +1,9 @@
 package plugin
 
 import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
 	"fmt"
 	"io"
 	"os"
@@ -9,6 +12,7 @@ import (
 	"testing"
 
 	"github.com/terraform-linters/tflint/tflint"
+	"golang.org/x/crypto/blake2b"
 )
 
 func Test_GetSigningKey(t *testing.T) {
@@ -102,22 +106,7 @@ func Test_SignatureChecker_Verify(t *testing.T) {
 	}
 	defer signature.Close()
 
-	target := `003432556f0380963e6802e701d40a5ea303cfd8ad0cd34719b00c796abfe90e  tflint-ruleset-aws_netbsd_amd64.zip
-02caedcd1f0e9c331862b0babce83792d95021977595be9712b01dc0164c488d  tflint-ruleset-aws_netbsd_arm.zip
-03ed11c1deeb4dbfc1656b030e4172d1f6b03a1257f219f6265bfc100b20c7a8  tflint-ruleset-aws_netbsd_386.zip
-043416079a7ea9e0f7888915278aecda7d6268b61066deacc50feefb6e57836c  tflint-ruleset-aws_linux_arm.zip
-30fb5e2d8d8ca3be7247cb0f9e644e3accf0b334d473ce6a5b83151302183e22  tflint-ruleset-aws_openbsd_amd64.zip
-3a61fff3689f27c89bce22893219919c629d2e10b96e7eadd5fef9f0e90bb353  tflint-ruleset-aws_darwin_amd64.zip
-482419fdeed00692304e59558b5b0d915d4727868b88a5adbbbb76f5ed1b537a  tflint-ruleset-aws_linux_amd64.zip
-7147440b689291870ffafd40652a9a623df6c43557da3d2c90e712065e0d093f  tflint-ruleset-aws_freebsd_386.zip
-7c7154183f3faf4c80c6703969f5f4903f795eebb881b06da40caa4827eb48e4  tflint-ruleset-aws_windows_386.zip
-9df843eb85785246df1e24886b1112b324444dd02329ff45c5cffa597f674b6c  tflint-ruleset-aws_openbsd_arm.zip
-b6231a2b94a71841409bb28be0f22eb6ed9de744f0c06ca3a9dd6f80cbc63956  tflint-ruleset-aws_linux_386.zip
-bd804df0ff957cda8210c74017211face850cd62445a7d0102493ab5cfdc4276  tflint-ruleset-aws_freebsd_amd64.zip
-bee3591d764729769fd32dae7dc8147a890aadc45c00260fa6dafe2df1585a02  tflint-ruleset-aws_openbsd_386.zip
-db4eed4c0abcfb0b851da5bbfe8d0c71e1c2b6afe4fd627638a462c655045902  tflint-ruleset-aws_windows_amd64.zip
-dd536fed0ebe4c1115240574c5dd7a31b563d67bfe0d1111750438718f995d43  tflint-ruleset-aws_freebsd_arm.zip
-`
+	target := testChecksums
 	reader := strings.NewReader(target)
 
 	sigchecker := NewSignatureChecker(NewInstallConfig(tflint.EmptyConfig(), &tflint.PluginConfig{SigningKey: builtinSigningKey}))
@@ -138,22 +127,7 @@ func Test_SignatureChecker_Verify_errors(t *testing.T) {
 	defer signature.Close()
 	brokenSignature := strings.NewReader("broken")
 
-	target := `003432556f0380963e6802e701d40a5ea303cfd8ad0cd34719b00c796abfe90e  tflint-ruleset-aws_netbsd_amd64.zip
-02caedcd1f0e9c331862b0babce83792d95021977595be9712b01dc0164c488d  tflint-ruleset-aws_netbsd_arm.zip
-03ed11c1deeb4dbfc1656b030e4172d1f6b03a1257f219f6265bfc100b20c7a8  tflint-ruleset-aws_netbsd_386.zip
-043416079a7ea9e0f7888915278aecda7d6268b61066deacc50feefb6e57836c  tflint-ruleset-aws_linux_arm.zip
-30fb5e2d8d8ca3be7247cb0f9e644e3accf0b334d473ce6a5b83151302183e22  tflint-ruleset-aws_openbsd_amd64.zip
-3a61fff3689f27c89bce22893219919c629d2e10b96e7eadd5fef9f0e90bb353  tflint-ruleset-aws_darwin_amd64.zip
-482419fdeed00692304e59558b5b0d915d4727868b88a5adbbbb76f5ed1b537a  tflint-ruleset-aws_linux_amd64.zip
-7147440b689291870ffafd40652a9a623df6c43557da3d2c90e712065e0d093f  tflint-ruleset-aws_freebsd_386.zip
-7c7154183f3faf4c80c6703969f5f4903f795eebb881b06da40caa4827eb48e4  tflint-ruleset-aws_windows_386.zip
-9df843eb85785246df1e24886b1112b324444dd02329ff45c5cffa597f674b6c  tflint-ruleset-aws_openbsd_arm.zip
-b6231a2b94a71841409bb28be0f22eb6ed9de744f0c06ca3a9dd6f80cbc63956  tflint-ruleset-aws_linux_386.zip
-bd804df0ff957cda8210c74017211face850cd62445a7d0102493ab5cfdc4276  tflint-ruleset-aws_freebsd_amd64.zip
-bee3591d764729769fd32dae7dc8147a890aadc45c00260fa6dafe2df1585a02  tflint-ruleset-aws_openbsd_386.zip
-db4eed4c0abcfb0b851da5bbfe8d0c71e1c2b6afe4fd627638a462c655045902  tflint-ruleset-aws_windows_amd64.zip
-dd536fed0ebe4c1115240574c5dd7a31b563d67bfe0d1111750438718f995d43  tflint-ruleset-aws_freebsd_arm.zip
-`
+	target := testChecksums
 
 	cases := []struct {
 		Name      string
@@ -208,6 +182,303 @@ dd536fed0ebe4c1115240574c5dd7a31b563d67bfe0d1111750438718f995d43  tflint-ruleset
 	}
 }
 
+func Test_SignatureChecker_SignatureFileName(t *testing.T) {
+	allowedSigners, err := os.ReadFile(filepath.Join("test-fixtures", "signatures", "allowed_signers"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	minisignKey, _ := newMinisignKey(t, minisignKeyID)
+
+	cases := []struct {
+		Name       string
+		SigningKey string
+		Expected   string
+	}{
+		{
+			Name:       "OpenPGP",
+			SigningKey: testSigningKey,
+			Expected:   "checksums.txt.sig",
+		},
+		{
+			Name:       "SSH",
+			SigningKey: string(allowedSigners),
+			Expected:   "checksums.txt.sig",
+		},
+		{
+			Name:       "minisign",
+			SigningKey: minisignKey,
+			Expected:   "checksums.txt.minisig",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			sigchecker := NewSignatureChecker(NewInstallConfig(tflint.EmptyConfig(), &tflint.PluginConfig{SigningKey: tc.SigningKey}))
+
+			got := sigchecker.SignatureFileName()
+			if got != tc.Expected {
+				t.Errorf("expected=%s, got=%s", tc.Expected, got)
+			}
+		})
+	}
+}
+
+func Test_SignatureChecker_Verify_ssh(t *testing.T) {
+	allowedSigners, err := os.ReadFile(filepath.Join("test-fixtures", "signatures", "allowed_signers"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Keys without principals are also accepted
+	ed25519Key := strings.Fields(strings.Split(string(allowedSigners), "\n")[1])
+	publicKey := strings.Join(ed25519Key[2:], " ")
+
+	cases := []struct {
+		Name       string
+		SigningKey string
+		Target     string
+		Signature  string
+		Expected   string
+	}{
+		{
+			Name:       "ed25519",
+			SigningKey: string(allowedSigners),
+			Target:     testChecksums,
+			Signature:  "checksums.txt.ed25519.sig",
+		},
+		{
+			Name:       "rsa",
+			SigningKey: string(allowedSigners),
+			Target:     testChecksums,
+			Signature:  "checksums.txt.rsa.sig",
+		},
+		{
+			Name:       "public key",
+			SigningKey: publicKey,
+			Target:     testChecksums,
+			Signature:  "checksums.txt.ed25519.sig",
+		},
+		{
+			Name:       "invalid signature",
+			SigningKey: string(allowedSigners),
+			Target:     "broken",
+			Signature:  "checksums.txt.ed25519.sig",
+			Expected:   "ssh: invalid signature: ssh: signature did not verify",
+		},
+		{
+			Name:       "unknown key",
+			SigningKey: publicKey,
+			Target:     testChecksums,
+			Signature:  "checksums.txt.rsa.sig",
+			Expected:   "ssh: signature made by unknown key SHA256:eTaNV0GFcIApv6gT8Bc1frdMtWoMVJ81/DqJb+wtbAU",
+		},
+		{
+			Name:       "namespace mismatch",
+			SigningKey: string(allowedSigners),
+			Target:     testChecksums,
+			Signature:  "checksums.txt.git.sig",
+			Expected:   `ssh: signature namespace is "git", but must be "file". Sign with "ssh-keygen -Y sign -n file"`,
+		},
+		{
+			Name:       "OpenPGP signature",
+			SigningKey: string(allowedSigners),
+			Target:     testChecksums,
+			Signature:  "checksums.txt.sig",
+			Expected:   `ssh: signature is not an SSH signature. Sign with "ssh-keygen -Y sign"`,
+		},
+		{
+			Name:       "unsupported option",
+			SigningKey: "release@example.com cert-authority " + publicKey,
+			Target:     testChecksums,
+			Signature:  "checksums.txt.ed25519.sig",
+			Expected:   `ssh: invalid allowed signers at line 1: option "cert-authority" is not supported`,
+		},
+		{
+			Name:       "broken key",
+			SigningKey: "release@example.com ssh-ed25519 broken",
+			Target:     testChecksums,
+			Signature:  "checksums.txt.ed25519.sig",
+			Expected:   "ssh: invalid allowed signers at line 1: ssh: no key found",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			signature, err := os.Open(filepath.Join("test-fixtures", "signatures", tc.Signature))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer signature.Close()
+
+			sigchecker := NewSignatureChecker(NewInstallConfig(tflint.EmptyConfig(), &tflint.PluginConfig{SigningKey: tc.SigningKey}))
+			err = sigchecker.Verify(strings.NewReader(tc.Target), signature)
+			if tc.Expected == "" {
+				if err != nil {
+					t.Fatalf("Verify failed: %s", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("expected=%s, actual=no errors", tc.Expected)
+			}
+			if err.Error() != tc.Expected {
+				t.Errorf("expected=%s, actual=%s", tc.Expected, err)
+			}
+		})
+	}
+}
+
+func Test_SignatureChecker_Verify_minisign(t *testing.T) {
+	publicKey, privateKey := newMinisignKey(t, minisignKeyID)
+	otherKey, _ := newMinisignKey(t, []byte{8, 7, 6, 5, 4, 3, 2, 1})
+	sameIDKey, _ := newMinisignKey(t, minisignKeyID)
+
+	legacy := minisignSign(t, privateKey, "Ed", testChecksums, "timestamp:1700000000")
+	hashed := minisignSign(t, privateKey, "ED", testChecksums, "timestamp:1700000000")
+	lines := strings.Split(hashed, "\n")
+	tampered := strings.Join([]string{lines[0], lines[1], "trusted comment: tampered", lines[3]}, "\n")
+
+	cases := []struct {
+		Name       string
+		SigningKey string
+		Target     string
+		Signature  string
+		Expected   string
+	}{
+		{
+			Name:       "legacy",
+			SigningKey: publicKey,
+			Target:     testChecksums,
+			Signature:  legacy,
+		},
+		{
+			Name:       "pre-hashed",
+			SigningKey: publicKey,
+			Target:     testChecksums,
+			Signature:  hashed,
+		},
+		{
+			Name:       "without comment",
+			SigningKey: strings.Split(publicKey, "\n")[1],
+			Target:     testChecksums,
+			Signature:  hashed,
+		},
+		{
+			Name:       "invalid signature",
+			SigningKey: publicKey,
+			Target:     "broken",
+			Signature:  hashed,
+			Expected:   "minisign: invalid signature",
+		},
+		{
+			Name:       "tampered trusted comment",
+			SigningKey: publicKey,
+			Target:     testChecksums,
+			Signature:  tampered,
+			Expected:   "minisign: invalid global signature",
+		},
+		{
+			Name:       "unknown key",
+			SigningKey: otherKey,
+			Target:     testChecksums,
+			Signature:  hashed,
+			Expected:   "minisign: signature made by unknown key 0807060504030201",
+		},
+		{
+			Name:       "wrong key with the same ID",
+			SigningKey: sameIDKey,
+			Target:     testChecksums,
+			Signature:  hashed,
+			Expected:   "minisign: invalid signature",
+		},
+		{
+			Name:       "broken signature",
+			SigningKey: publicKey,
+			Target:     testChecksums,
+			Signature:  "broken",
+			Expected:   `minisign: signature is not a minisign signature. Sign with "minisign -S"`,
+		},
+		{
+			Name:       "broken key",
+			SigningKey: "untrusted comment: minisign public key\nRWbroken",
+			Target:     testChecksums,
+			Signature:  hashed,
+			Expected:   `minisign: invalid public key. Must be the content of the public key file like "RW..."`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			sigchecker := NewSignatureChecker(NewInstallConfig(tflint.EmptyConfig(), &tflint.PluginConfig{SigningKey: tc.SigningKey}))
+			err := sigchecker.Verify(strings.NewReader(tc.Target), strings.NewReader(tc.Signature))
+			if tc.Expected == "" {
+				if err != nil {
+					t.Fatalf("Verify failed: %s", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("expected=%s, actual=no errors", tc.Expected)
+			}
+			if err.Error() != tc.Expected {
+				t.Errorf("expected=%s, actual=%s", tc.Expected, err)
+			}
+		})
+	}
+}
+
+// minisignKeyID is the key ID of keys generated by newMinisignKey.
+var minisignKeyID = []byte{1, 2, 3, 4, 5, 6, 7, 8}
+
+// newMinisignKey returns a new minisign public key in the public key file format, and its private key.
+func newMinisignKey(t *testing.T, keyID []byte) (string, ed25519.PrivateKey) {
+	t.Helper()
+
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := append(append([]byte("Ed"), keyID...), pub...)
+	return "untrusted comment: minisign public key\n" + base64.StdEncoding.EncodeToString(key) + "\n", priv
+}
+
+// minisignSign returns a signature in the same format as "minisign -S".
+func minisignSign(t *testing.T, priv ed25519.PrivateKey, algorithm string, message string, trustedComment string) string {
+	t.Helper()
+
+	signed := []byte(message)
+	if algorithm == "ED" {
+		sum := blake2b.Sum512(signed)
+		signed = sum[:]
+	}
+	sig := ed25519.Sign(priv, signed)
+	globalSig := ed25519.Sign(priv, append(append([]byte{}, sig...), trustedComment...))
+
+	return strings.Join([]string{
+		"untrusted comment: signature from minisign secret key",
+		base64.StdEncoding.EncodeToString(append(append([]byte(algorithm), minisignKeyID...), sig...)),
+		"trusted comment: " + trustedComment,
+		base64.StdEncoding.EncodeToString(globalSig),
+	}, "\n") + "\n"
+}
+
+// testChecksums is the checksums file signed by test-fixtures/signatures/checksums.txt*.sig
+var testChecksums = `003432556f0380963e6802e701d40a5ea303cfd8ad0cd34719b00c796abfe90e  tflint-ruleset-aws_netbsd_amd64.zip
+02caedcd1f0e9c331862b0babce83792d95021977595be9712b01dc0164c488d  tflint-ruleset-aws_netbsd_arm.zip
+03ed11c1deeb4dbfc1656b030e4172d1f6b03a1257f219f6265bfc100b20c7a8  tflint-ruleset-aws_netbsd_386.zip
+043416079a7ea9e0f7888915278aecda7d6268b61066deacc50feefb6e57836c  tflint-ruleset-aws_linux_arm.zip
+30fb5e2d8d8ca3be7247cb0f9e644e3accf0b334d473ce6a5b83151302183e22  tflint-ruleset-aws_openbsd_amd64.zip
+3a61fff3689f27c89bce22893219919c629d2e10b96e7eadd5fef9f0e90bb353  tflint-ruleset-aws_darwin_amd64.zip
+482419fdeed00692304e59558b5b0d915d4727868b88a5adbbbb76f5ed1b537a  tflint-ruleset-aws_linux_amd64.zip
+7147440b689291870ffafd40652a9a623df6c43557da3d2c90e712065e0d093f  tflint-ruleset-aws_freebsd_386.zip
+7c7154183f3faf4c80c6703969f5f4903f795eebb881b06da40caa4827eb48e4  tflint-ruleset-aws_windows_386.zip
+9df843eb85785246df1e24886b1112b324444dd02329ff45c5cffa597f674b6c  tflint-ruleset-aws_openbsd_arm.zip
+b6231a2b94a71841409bb28be0f22eb6ed9de744f0c06ca3a9dd6f80cbc63956  tflint-ruleset-aws_linux_386.zip
+bd804df0ff957cda8210c74017211face850cd62445a7d0102493ab5cfdc4276  tflint-ruleset-aws_freebsd_amd64.zip
+bee3591d764729769fd32dae7dc8147a890aadc45c00260fa6dafe2df1585a02  tflint-ruleset-aws_openbsd_386.zip
+db4eed4c0abcfb0b851da5bbfe8d0c71e1c2b6afe4fd627638a462c655045902  tflint-ruleset-aws_windows_amd64.zip
+dd536fed0ebe4c1115240574c5dd7a31b563d67bfe0d1111750438718f995d43  tflint-ruleset-aws_freebsd_arm.zip
+`
+
 var testSigningKey string = `
 -----BEGIN PGP PUBLIC KEY BLOCK-----
 
@@ -0,0 +1,298 @@
+package plugin
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/google/go-github/v53/github"
+	"github.com/mitchellh/go-homedir"
+	"github.com/terraform-linters/tflint/tflint"
+)
+
+// httpTransport is the transport for requests to GitHub and HTTPS sources.
+// This is a variable so that tests can trust the certificate of a test server.
+var httpTransport http.RoundTripper = http.DefaultTransport
+
+// releaseSource is the location where a plugin release is downloaded from.
+// A release consists of an asset for each platform, a checksums file, and an optional
+// signature of the checksums file. Downloaded files are always verified by the caller.
+type releaseSource interface {
+	// downloadAsset downloads the asset for the passed platform to a temp file.
+	downloadAsset(goos string, goarch string) (*os.File, error)
+	// downloadChecksums downloads the checksums file to a temp file.
+	downloadChecksums() (*os.File, error)
+	// downloadSignature downloads the signature of the checksums file to a temp file.
+	// The name is the file name of the signature, like "checksums.txt.sig".
+	downloadSignature(name string) (*os.File, error)
+}
+
+// releaseSource returns the location of the release. If the release exists
+// in the plugin mirror, it takes precedence over the source.
+func (c *InstallConfig) releaseSource() (releaseSource, error) {
+	mirror, err := c.mirrorSource()
+	if err != nil {
+		return nil, err
+	}
+	if mirror != nil {
+		return mirror, nil
+	}
+	return c.upstreamSource()
+}
+
+// upstreamSource returns the location of the release based on the source type.
+func (c *InstallConfig) upstreamSource() (releaseSource, error) {
+	switch c.SourceType {
+	case tflint.PluginSourceGitHub:
+		assets, err := c.fetchReleaseAssets()
+		if err != nil {
+			return nil, fmt.Errorf("Failed to fetch GitHub releases: %w", err)
+		}
+		return &githubSource{config: c, assets: assets}, nil
+
+	case tflint.PluginSourceURL, tflint.PluginSourceFile, tflint.PluginSourceDir:
+		assetURL := c.expandTemplate(c.Source)
+		if c.SourceType == tflint.PluginSourceDir {
+			return dirSource(c.Source, c.assetTemplate())
+		}
+
+		checksumsURL := c.expandTemplate(c.ChecksumsURL)
+		if checksumsURL == "" {
+			base, err := url.Parse(assetURL)
+			if err != nil {
+				return nil, err
+			}
+			checksumsURL = base.ResolveReference(&url.URL{Path: "checksums.txt"}).String()
+		}
+
+		return &urlSource{assetURL: assetURL, checksumsURL: checksumsURL}, nil
+
+	default:
+		panic(fmt.Sprintf("unknown source type: %d", c.SourceType))
+	}
+}
+
+// mirrorSource returns the release in the plugin mirror.
+// If the mirror is not configured or does not have the release, it returns nil.
+func (c *InstallConfig) mirrorSource() (releaseSource, error) {
+	mirror, err := getPluginMirror(c.globalConfig)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get plugin mirror: %w", err)
+	}
+	if mirror == "" {
+		return nil, nil
+	}
+
+	dir := filepath.Join(mirror, c.MirrorPath())
+	if _, err := os.Stat(dir); err != nil {
+		if os.IsNotExist(err) {
+			log.Printf(`[DEBUG] Plugin "%s" is not found in the mirror: %s`, c.Name, dir)
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	log.Printf(`[INFO] Install plugin "%s" from the mirror: %s`, c.Name, dir)
+	return dirSource(dir, c.assetTemplate())
+}
+
+// dirSource returns a release in the local directory.
+// The directory must contain the assets, checksums.txt, and its signature such as checksums.txt.sig.
+func dirSource(dir string, assetTemplate string) (releaseSource, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	dirPath := filepath.ToSlash(dir)
+	// Windows paths like "C:/path" need a leading slash
+	if !strings.HasPrefix(dirPath, "/") {
+		dirPath = "/" + dirPath
+	}
+	// Build URLs without escaping so as not to break the placeholders
+	return &urlSource{
+		assetURL:     "file://" + path.Join(dirPath, assetTemplate),
+		checksumsURL: "file://" + path.Join(dirPath, "checksums.txt"),
+	}, nil
+}
+
+// getPluginMirror returns the plugin mirror directory.
+// Adopted with the following priorities:
+//
+//  1. `plugin_mirror` in a global config
+//  2. `TFLINT_PLUGIN_MIRROR` environment variable
+//
+// If neither is set, it returns an empty string.
+func getPluginMirror(cfg *tflint.Config) (string, error) {
+	if cfg.PluginMirror != "" {
+		return homedir.Expand(cfg.PluginMirror)
+	}
+	return os.Getenv("TFLINT_PLUGIN_MIRROR"), nil
+}
+
+// MirrorPath returns the path of the release in the plugin mirror.
+// For GitHub sources, this is like "github.com/owner/repo/1.0.0".
+func (c *InstallConfig) MirrorPath() string {
+	return filepath.Join(c.sourceDir(), c.Version)
+}
+
+// githubAssetTemplate is the name of assets in GitHub releases and local directories.
+const githubAssetTemplate = "tflint-ruleset-{name}_{os}_{arch}.zip"
+
+// assetTemplate returns the name of the release asset, with {os} and {arch} placeholders
+// left unexpanded. This is the name written in the checksums file.
+func (c *InstallConfig) assetTemplate() string {
+	switch c.SourceType {
+	case tflint.PluginSourceURL, tflint.PluginSourceFile:
+		u, err := url.Parse(c.expandTemplate(c.Source))
+		if err != nil {
+			// The source is already validated when loading the config
+			panic(err)
+		}
+		return path.Base(u.Path)
+	default:
+		return c.expandTemplate(githubAssetTemplate)
+	}
+}
+
+// expandTemplate expands {name} and {version} placeholders in the passed string.
+func (c *InstallConfig) expandTemplate(s string) string {
+	return strings.NewReplacer("{name}", c.Name, "{version}", c.Version).Replace(s)
+}
+
+// expandPlatform expands {os} and {arch} placeholders in the passed string.
+func expandPlatform(s string, goos string, goarch string) string {
+	return strings.NewReplacer("{os}", goos, "{arch}", goarch).Replace(s)
+}
+
+// sourceDir returns a directory name for the source in the plugin directory.
+// GitHub sources are used as they are, but other sources may contain characters
+// that cannot be used in paths, so they are represented by a hash.
+func (c *InstallConfig) sourceDir() string {
+	switch c.SourceType {
+	case tflint.PluginSourceURL:
+		return filepath.Join(c.SourceHost, sourceHash(c.Source))
+	case tflint.PluginSourceFile:
+		return filepath.Join("local", sourceHash(c.Source))
+	case tflint.PluginSourceDir:
+		// Relative paths are distinguished by the absolute path
+		source, err := filepath.Abs(c.Source)
+		if err != nil {
+			source = c.Source
+		}
+		return filepath.Join("local", sourceHash(source))
+	default:
+		return c.Source
+	}
+}
+
+func sourceHash(source string) string {
+	sum := sha256.Sum256([]byte(source))
+	return hex.EncodeToString(sum[:8])
+}
+
+// githubSource is a GitHub release.
+type githubSource struct {
+	config *InstallConfig
+	assets map[string]*github.ReleaseAsset
+}
+
+func (s *githubSource) downloadAsset(goos string, goarch string) (*os.File, error) {
+	return s.config.downloadToTempFile(s.assets[expandPlatform(s.config.assetTemplate(), goos, goarch)])
+}
+
+func (s *githubSource) downloadChecksums() (*os.File, error) {
+	return s.config.downloadToTempFile(s.assets["checksums.txt"])
+}
+
+func (s *githubSource) downloadSignature(name string) (*os.File, error) {
+	return s.config.downloadToTempFile(s.assets[name])
+}
+
+// urlSource is a release hosted on an HTTPS server or a local filesystem.
+// The asset URL may contain {os} and {arch} placeholders. The signature is
+// expected to be next to the checksums file with the suffix of the signature name, like ".sig".
+type urlSource struct {
+	assetURL     string
+	checksumsURL string
+}
+
+func (s *urlSource) downloadAsset(goos string, goarch string) (*os.File, error) {
+	return downloadURL(expandPlatform(s.assetURL, goos, goarch))
+}
+
+func (s *urlSource) downloadChecksums() (*os.File, error) {
+	return downloadURL(expandPlatform(s.checksumsURL, runtime.GOOS, runtime.GOARCH))
+}
+
+func (s *urlSource) downloadSignature(name string) (*os.File, error) {
+	return downloadURL(expandPlatform(s.checksumsURL, runtime.GOOS, runtime.GOARCH) + strings.TrimPrefix(name, "checksums.txt"))
+}
+
+// downloadURL downloads the file at the passed URL to a temp file.
+// It is the caller's responsibility to delete the generated the temp file.
+func downloadURL(rawURL string) (*os.File, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	var body io.ReadCloser
+	switch u.Scheme {
+	case "https":
+		client := &http.Client{Transport: &requestLoggingTransport{httpTransport}}
+		resp, err := client.Get(u.String())
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			return nil, fmt.Errorf("%s returned %s", u.Redacted(), resp.Status)
+		}
+		body = resp.Body
+
+	case "file":
+		path := u.Path
+		// Windows paths like "/C:/path" must not have a leading slash
+		if runtime.GOOS == "windows" {
+			path = strings.TrimPrefix(path, "/")
+		}
+		log.Printf("[DEBUG] Open %s", path)
+		body, err = os.Open(filepath.FromSlash(path))
+		if err != nil {
+			return nil, err
+		}
+
+	default:
+		return nil, fmt.Errorf(`unsupported URL scheme "%s"`, u.Scheme)
+	}
+	defer body.Close()
+
+	return writeToTempFile(body)
+}
+
+// writeToTempFile copies the passed reader to a temp file, and rewinds the file.
+// It is the caller's responsibility to delete the generated the temp file.
+func writeToTempFile(r io.Reader) (*os.File, error) {
+	file, err := os.CreateTemp("", "tflint-download-temp-file-*")
+	if err != nil {
+		return nil, err
+	}
+	if _, err = io.Copy(file, r); err != nil {
+		return file, err
+	}
+	if _, err := file.Seek(0, 0); err != nil {
+		return file, err
+	}
+
+	log.Printf("[DEBUG] Downloaded to %s", file.Name())
+	return file, nil
+}
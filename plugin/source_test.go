@@ -0,0 +1,278 @@
+package plugin
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/terraform-linters/tflint/tflint"
+	//nolint:staticcheck
+	"golang.org/x/crypto/openpgp"
+	//nolint:staticcheck
+	"golang.org/x/crypto/openpgp/armor"
+)
+
+func Test_Install_sources(t *testing.T) {
+	original := PluginRoot
+	defer func() { PluginRoot = original }()
+
+	entity, err := openpgp.NewEntity("test", "", "test@example.com", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signingKey := armoredPublicKey(t, entity)
+
+	// Release files are served under /releases, and the same files are placed in the local directory
+	releaseDir := t.TempDir()
+	assetName := fmt.Sprintf("foo_%s_%s.zip", runtime.GOOS, runtime.GOARCH)
+	writeRelease(t, releaseDir, entity, assetName)
+	writeRelease(t, filepath.Join(releaseDir, "standard"), entity, fmt.Sprintf("tflint-ruleset-foo_%s_%s.zip", runtime.GOOS, runtime.GOARCH))
+	if err := os.WriteFile(filepath.Join(releaseDir, "broken.zip"), []byte("broken"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Release signed with minisign
+	minisignDir := filepath.Join(releaseDir, "minisign")
+	writeRelease(t, minisignDir, entity, fmt.Sprintf("tflint-ruleset-foo_%s_%s.zip", runtime.GOOS, runtime.GOARCH))
+	minisignKey, minisignPrivateKey := newMinisignKey(t, minisignKeyID)
+	checksums, err := os.ReadFile(filepath.Join(minisignDir, "checksums.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	minisig := minisignSign(t, minisignPrivateKey, "ED", string(checksums), "timestamp:1700000000")
+	if err := os.WriteFile(filepath.Join(minisignDir, "checksums.txt.minisig"), []byte(minisig), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	server := httptest.NewTLSServer(http.StripPrefix("/releases/", http.FileServer(http.Dir(releaseDir))))
+	defer server.Close()
+	originalTransport := httpTransport
+	httpTransport = server.Client().Transport
+	defer func() { httpTransport = originalTransport }()
+
+	cases := []struct {
+		Name   string
+		Config *tflint.PluginConfig
+		Error  string
+	}{
+		{
+			Name: "HTTPS URL template",
+			Config: &tflint.PluginConfig{
+				Source:     server.URL + "/releases/{name}_{os}_{arch}.zip",
+				SourceType: tflint.PluginSourceURL,
+				SigningKey: signingKey,
+			},
+		},
+		{
+			Name: "HTTPS URL template with checksums URL",
+			Config: &tflint.PluginConfig{
+				Source:       server.URL + "/releases/{name}_{os}_{arch}.zip",
+				SourceType:   tflint.PluginSourceURL,
+				ChecksumsURL: server.URL + "/releases/v{version}/checksums.txt",
+			},
+			Error: "Failed to download checksums.txt: " + server.URL + "/releases/v0.1.0/checksums.txt returned 404 Not Found",
+		},
+		{
+			Name: "file URL",
+			Config: &tflint.PluginConfig{
+				Source:     "file://" + filepath.ToSlash(filepath.Join(releaseDir, assetName)),
+				SourceType: tflint.PluginSourceFile,
+				SigningKey: signingKey,
+			},
+		},
+		{
+			Name: "local directory",
+			Config: &tflint.PluginConfig{
+				Source:     filepath.Join(releaseDir, "standard"),
+				SourceType: tflint.PluginSourceDir,
+				SigningKey: signingKey,
+			},
+		},
+		{
+			Name: "local directory with minisign",
+			Config: &tflint.PluginConfig{
+				Source:     minisignDir,
+				SourceType: tflint.PluginSourceDir,
+				SigningKey: minisignKey,
+			},
+		},
+		{
+			Name: "checksum mismatch",
+			Config: &tflint.PluginConfig{
+				Source:       server.URL + "/releases/broken.zip",
+				SourceType:   tflint.PluginSourceURL,
+				ChecksumsURL: server.URL + "/releases/checksums.txt",
+			},
+			Error: "Failed to verify checksums: Failed to match checksums: expected=, actual=f526795c95399cea27c055c842c3d6ab018ed0fa4f66f701c28ab22dec28237b",
+		},
+		{
+			Name: "invalid signature",
+			Config: &tflint.PluginConfig{
+				Source:     server.URL + "/releases/{name}_{os}_{arch}.zip",
+				SourceType: tflint.PluginSourceURL,
+				SigningKey: testSigningKey,
+			},
+			Error: "Failed to check checksums.txt signature: openpgp: signature made by unknown entity",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			PluginRoot = t.TempDir()
+
+			tc.Config.Name = "foo"
+			tc.Config.Enabled = true
+			tc.Config.Version = "0.1.0"
+			config := NewInstallConfig(tflint.EmptyConfig(), tc.Config)
+
+			path, err := config.Install()
+			if err != nil {
+				if tc.Error == "" {
+					t.Fatal(err)
+				}
+				if err.Error() != tc.Error {
+					t.Fatalf("expected=%s, actual=%s", tc.Error, err)
+				}
+				return
+			}
+			if tc.Error != "" {
+				t.Fatalf("expected=%s, actual=no errors", tc.Error)
+			}
+
+			content, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(content) != "binary" {
+				t.Fatalf("Installed binary is invalid: %s", content)
+			}
+			found, err := FindPluginPath(config)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if found != path {
+				t.Fatalf("expected=%s, actual=%s", path, found)
+			}
+			if _, exists := config.Lock.Checksums[currentPlatform()]; !exists {
+				t.Fatalf("Expected to lock the checksum for %s, but got %#v", currentPlatform(), config.Lock.Checksums)
+			}
+		})
+	}
+}
+
+func Test_InstallPath_sources(t *testing.T) {
+	cases := []struct {
+		Name     string
+		Config   *tflint.PluginConfig
+		Expected string
+	}{
+		{
+			Name: "GitHub",
+			Config: &tflint.PluginConfig{
+				Name:       "foo",
+				Version:    "0.1.0",
+				Source:     "github.com/example/tflint-ruleset-foo",
+				SourceType: tflint.PluginSourceGitHub,
+			},
+			Expected: filepath.Join("github.com", "example", "tflint-ruleset-foo", "0.1.0", "tflint-ruleset-foo"),
+		},
+		{
+			Name: "HTTPS URL",
+			Config: &tflint.PluginConfig{
+				Name:       "foo",
+				Version:    "0.1.0",
+				Source:     "https://artifactory.example.com/tflint/{name}_{os}_{arch}.zip",
+				SourceType: tflint.PluginSourceURL,
+				SourceHost: "artifactory.example.com",
+			},
+			Expected: filepath.Join("artifactory.example.com", sourceHash("https://artifactory.example.com/tflint/{name}_{os}_{arch}.zip"), "0.1.0", "tflint-ruleset-foo"),
+		},
+		{
+			Name: "file URL",
+			Config: &tflint.PluginConfig{
+				Name:       "foo",
+				Version:    "0.1.0",
+				Source:     "file:///mnt/tflint/foo.zip",
+				SourceType: tflint.PluginSourceFile,
+			},
+			Expected: filepath.Join("local", sourceHash("file:///mnt/tflint/foo.zip"), "0.1.0", "tflint-ruleset-foo"),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			got := NewInstallConfig(tflint.EmptyConfig(), tc.Config).InstallPath()
+			if got != tc.Expected {
+				t.Fatalf("expected=%s, actual=%s", tc.Expected, got)
+			}
+		})
+	}
+}
+
+// writeRelease writes release assets containing tflint-ruleset-foo, a checksums file, and its signature.
+func writeRelease(t *testing.T, dir string, entity *openpgp.Entity, assetNames ...string) {
+	t.Helper()
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	var asset bytes.Buffer
+	w := zip.NewWriter(&asset)
+	f, err := w.Create("tflint-ruleset-foo" + fileExt())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.Write([]byte("binary")); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	sum := sha256.Sum256(asset.Bytes())
+
+	checksums := ""
+	for _, assetName := range assetNames {
+		if err := os.WriteFile(filepath.Join(dir, assetName), asset.Bytes(), 0644); err != nil {
+			t.Fatal(err)
+		}
+		checksums += fmt.Sprintf("%s  %s\n", hex.EncodeToString(sum[:]), assetName)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "checksums.txt"), []byte(checksums), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var signature bytes.Buffer
+	if err := openpgp.DetachSign(&signature, entity, strings.NewReader(checksums), nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "checksums.txt.sig"), signature.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func armoredPublicKey(t *testing.T, entity *openpgp.Entity) string {
+	t.Helper()
+
+	var buf bytes.Buffer
+	w, err := armor.Encode(&buf, openpgp.PublicKeyType, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := entity.Serialize(w); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.String()
+}
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"github.com/terraform-linters/tflint-plugin-sdk/plugin"
+	"github.com/terraform-linters/tflint-plugin-sdk/tflint"
+)
+
+// crashRuleSet panics when the check starts to test crash handling.
+type crashRuleSet struct {
+	tflint.BuiltinRuleSet
+}
+
+func (r *crashRuleSet) NewRunner(runner tflint.Runner) (tflint.Runner, error) {
+	panic("crash plugin panicked")
+}
+
+func main() {
+	plugin.Serve(&plugin.ServeOpts{
+		RuleSet: &crashRuleSet{
+			BuiltinRuleSet: tflint.BuiltinRuleSet{
+				Name:    "crash",
+				Version: "0.1.0",
+				Rules:   []tflint.Rule{},
+			},
+		},
+	})
+}
@@ -0,0 +1,196 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/google/go-github/v53/github"
+	"github.com/hashicorp/go-version"
+)
+
+// WithVersion returns a copy of the install config with the passed exact version.
+// The original version constraints are kept to record them in the lock file.
+func (c *InstallConfig) WithVersion(v string) *InstallConfig {
+	constraints := c.constraints
+	if c.VersionConstraints() != nil {
+		constraints = c.Version
+	}
+
+	pluginCfg := *c.PluginConfig
+	pluginCfg.Version = v
+
+	return &InstallConfig{
+		globalConfig: c.globalConfig,
+		PluginConfig: &pluginCfg,
+		Lock:         c.Lock,
+		constraints:  constraints,
+	}
+}
+
+// Resolve returns the install config with an exact version.
+// If the version is not constraints, it returns the config as it is.
+//
+// If the locked plugin satisfies the constraints, the locked version is used so that
+// the installation is reproducible. Otherwise, the newest version that satisfies the
+// constraints is selected from the plugin mirror, or from the releases of the source
+// if the mirror does not have any.
+func (c *InstallConfig) Resolve(locked *LockedPlugin) (*InstallConfig, error) {
+	constraints := c.VersionConstraints()
+	if constraints == nil {
+		return c, nil
+	}
+
+	if locked != nil && locked.Matches(c.PluginConfig) {
+		log.Printf(`[DEBUG] Plugin "%s" is resolved to the locked version %s`, c.Name, locked.Version)
+		return c.WithVersion(locked.Version), nil
+	}
+
+	mirror, err := getPluginMirror(c.globalConfig)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get plugin mirror: %w", err)
+	}
+	if mirror != "" {
+		versions, err := listVersionDirs(filepath.Join(mirror, c.sourceDir()))
+		if err != nil {
+			return nil, err
+		}
+		if v := newestVersion(versions, constraints); v != nil {
+			log.Printf(`[DEBUG] Plugin "%s" is resolved to %s in the mirror`, c.Name, v.Original())
+			return c.WithVersion(v.Original()), nil
+		}
+	}
+
+	return c.resolveUpstream()
+}
+
+// resolveUpstream returns the install config with the newest version that satisfies
+// the constraints in the releases of the source.
+func (c *InstallConfig) resolveUpstream() (*InstallConfig, error) {
+	constraints := c.VersionConstraints()
+	if constraints == nil {
+		return c, nil
+	}
+
+	versions, err := c.fetchReleaseVersions()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to fetch GitHub releases: %w", err)
+	}
+	v := newestVersion(versions, constraints)
+	if v == nil {
+		return nil, fmt.Errorf(`no releases of plugin "%s" satisfy the version constraints "%s"`, c.Name, c.Version)
+	}
+
+	log.Printf(`[DEBUG] Plugin "%s" is resolved to %s`, c.Name, v.Original())
+	return c.WithVersion(v.Original()), nil
+}
+
+// fetchReleaseVersions fetches versions of the GitHub releases.
+// Releases must be tagged with a name like v1.1.1. Draft releases are ignored.
+func (c *InstallConfig) fetchReleaseVersions() ([]*version.Version, error) {
+	ctx := context.Background()
+
+	client, err := newGitHubClient(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+
+	versions := []*version.Version{}
+	opts := &github.ListOptions{PerPage: 100}
+	for {
+		releases, resp, err := client.Repositories.ListReleases(ctx, c.SourceOwner, c.SourceRepo, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, release := range releases {
+			if release.GetDraft() || !strings.HasPrefix(release.GetTagName(), "v") {
+				continue
+			}
+			v, err := version.NewVersion(strings.TrimPrefix(release.GetTagName(), "v"))
+			if err != nil {
+				log.Printf("[DEBUG] Skip release %s: %s", release.GetTagName(), err)
+				continue
+			}
+			versions = append(versions, v)
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	return versions, nil
+}
+
+// findConstrainedPluginPath returns the path of the newest installed version
+// that satisfies the constraints.
+func findConstrainedPluginPath(pluginDir string, config *InstallConfig) (string, error) {
+	dir := filepath.Join(pluginDir, config.sourceDir())
+
+	versions, err := listVersionDirs(dir)
+	if err != nil {
+		return "", err
+	}
+	constraints := config.VersionConstraints()
+	sort.Sort(sort.Reverse(version.Collection(versions)))
+
+	for _, v := range versions {
+		if !constraints.Check(v) {
+			continue
+		}
+		path, err := findPluginPath(filepath.Join(dir, v.Original(), fmt.Sprintf("tflint-ruleset-%s", config.Name)))
+		if os.IsNotExist(err) {
+			continue
+		}
+		return path, err
+	}
+
+	return "", &fs.PathError{Op: "find", Path: dir, Err: os.ErrNotExist}
+}
+
+// listVersionDirs returns versions of subdirectories in the passed directory.
+// If the directory does not exist, it returns an empty list.
+func listVersionDirs(dir string) ([]*version.Version, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []*version.Version{}, nil
+		}
+		return nil, err
+	}
+
+	versions := []*version.Version{}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		v, err := version.NewVersion(entry.Name())
+		if err != nil {
+			continue
+		}
+		versions = append(versions, v)
+	}
+	return versions, nil
+}
+
+// newestVersion returns the newest version that satisfies the constraints.
+// If no versions satisfy the constraints, it returns nil.
+func newestVersion(versions []*version.Version, constraints version.Constraints) *version.Version {
+	var newest *version.Version
+	for _, v := range versions {
+		if !constraints.Check(v) {
+			continue
+		}
+		if newest == nil || v.GreaterThan(newest) {
+			newest = v
+		}
+	}
+	return newest
+}
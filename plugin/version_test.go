@@ -0,0 +1,235 @@
+package plugin
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/terraform-linters/tflint/tflint"
+)
+
+func Test_Resolve(t *testing.T) {
+	mirror := t.TempDir()
+	pluginCfg := &tflint.PluginConfig{
+		Name:        "foo",
+		Enabled:     true,
+		Version:     "~> 0.2.0",
+		Source:      "github.com/example/tflint-ruleset-foo",
+		SourceHost:  "github.com",
+		SourceOwner: "example",
+		SourceRepo:  "tflint-ruleset-foo",
+	}
+	for _, v := range []string{"0.1.0", "0.2.0", "0.2.3", "0.3.0"} {
+		if err := os.MkdirAll(filepath.Join(mirror, pluginCfg.Source, v), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cases := []struct {
+		Name     string
+		Config   *tflint.PluginConfig
+		Locked   *LockedPlugin
+		Expected string
+	}{
+		{
+			Name:     "exact version",
+			Config:   &tflint.PluginConfig{Name: "foo", Version: "0.1.0", Source: pluginCfg.Source},
+			Expected: "0.1.0",
+		},
+		{
+			Name:     "locked version",
+			Config:   pluginCfg,
+			Locked:   &LockedPlugin{Name: "foo", Source: pluginCfg.Source, Version: "0.2.0"},
+			Expected: "0.2.0",
+		},
+		{
+			Name:     "locked version does not satisfy constraints",
+			Config:   pluginCfg,
+			Locked:   &LockedPlugin{Name: "foo", Source: pluginCfg.Source, Version: "0.1.0"},
+			Expected: "0.2.3",
+		},
+		{
+			Name:     "newest version in the mirror",
+			Config:   pluginCfg,
+			Expected: "0.2.3",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			config := NewInstallConfig(&tflint.Config{PluginMirror: mirror}, tc.Config)
+
+			got, err := config.Resolve(tc.Locked)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Version != tc.Expected {
+				t.Fatalf("expected=%s, actual=%s", tc.Expected, got.Version)
+			}
+			if tc.Config.Version != pluginCfg.Version {
+				return
+			}
+			if got.constraints != pluginCfg.Version {
+				t.Fatalf("Expected to keep the constraints, but got %s", got.constraints)
+			}
+		})
+	}
+}
+
+func Test_Resolve_upstream(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v3/repos/example/tflint-ruleset-foo/releases" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, `[
+  {"tag_name": "v0.3.0", "draft": true},
+  {"tag_name": "v0.2.1"},
+  {"tag_name": "v0.2.2-beta"},
+  {"tag_name": "v0.2.0"},
+  {"tag_name": "nightly"},
+  {"tag_name": "v0.1.0"}
+]`)
+	}))
+	defer server.Close()
+	originalTransport := httpTransport
+	httpTransport = server.Client().Transport
+	defer func() { httpTransport = originalTransport }()
+
+	u, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		Name     string
+		Version  string
+		Expected string
+		Error    string
+	}{
+		{
+			Name:     "pessimistic constraint",
+			Version:  "~> 0.2",
+			Expected: "0.2.1",
+		},
+		{
+			Name:     "range constraints",
+			Version:  ">= 0.1, < 0.2",
+			Expected: "0.1.0",
+		},
+		{
+			Name:    "no releases",
+			Version: ">= 0.3",
+			Error:   `no releases of plugin "foo" satisfy the version constraints ">= 0.3"`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Setenv("TFLINT_PLUGIN_MIRROR", "")
+
+			config := NewInstallConfig(tflint.EmptyConfig(), &tflint.PluginConfig{
+				Name:        "foo",
+				Enabled:     true,
+				Version:     tc.Version,
+				Source:      u.Host + "/example/tflint-ruleset-foo",
+				SourceHost:  u.Host,
+				SourceOwner: "example",
+				SourceRepo:  "tflint-ruleset-foo",
+			})
+
+			got, err := config.Resolve(nil)
+			if err != nil {
+				if tc.Error == "" {
+					t.Fatal(err)
+				}
+				if err.Error() != tc.Error {
+					t.Fatalf("expected=%s, actual=%s", tc.Error, err)
+				}
+				return
+			}
+			if tc.Error != "" {
+				t.Fatalf("expected=%s, actual=no errors", tc.Error)
+			}
+			if got.Version != tc.Expected {
+				t.Fatalf("expected=%s, actual=%s", tc.Expected, got.Version)
+			}
+			if got.TagName() != "v"+tc.Expected {
+				t.Fatalf("expected=v%s, actual=%s", tc.Expected, got.TagName())
+			}
+		})
+	}
+}
+
+func Test_FindPluginPath_constraints(t *testing.T) {
+	original := PluginRoot
+	PluginRoot = t.TempDir()
+	defer func() { PluginRoot = original }()
+
+	source := "github.com/example/tflint-ruleset-foo"
+	for _, v := range []string{"0.1.0", "0.2.0", "0.2.3", "0.3.0"} {
+		dir := filepath.Join(PluginRoot, source, v)
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		// 0.2.3 is not installed completely
+		if v == "0.2.3" {
+			continue
+		}
+		if err := os.WriteFile(filepath.Join(dir, "tflint-ruleset-foo"+fileExt()), []byte("binary"), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cases := []struct {
+		Name     string
+		Version  string
+		Expected string
+	}{
+		{
+			Name:     "newest installed version",
+			Version:  "~> 0.2.0",
+			Expected: filepath.Join(PluginRoot, source, "0.2.0", "tflint-ruleset-foo"+fileExt()),
+		},
+		{
+			Name:     "range constraints",
+			Version:  ">= 0.1, < 1.0",
+			Expected: filepath.Join(PluginRoot, source, "0.3.0", "tflint-ruleset-foo"+fileExt()),
+		},
+		{
+			Name:    "not installed",
+			Version: ">= 1.0",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Setenv("TFLINT_PLUGIN_DIR", "")
+
+			config := NewInstallConfig(tflint.EmptyConfig(), &tflint.PluginConfig{
+				Name:    "foo",
+				Enabled: true,
+				Version: tc.Version,
+				Source:  source,
+			})
+
+			got, err := FindPluginPath(config)
+			if tc.Expected == "" {
+				if !os.IsNotExist(err) {
+					t.Fatalf("Expected a not exist error, but got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tc.Expected {
+				t.Fatalf("expected=%s, actual=%s", tc.Expected, got)
+			}
+		})
+	}
+}
@@ -78,6 +78,25 @@ func (m ModuleInstance) String() string {
 	return buf.String()
 }
 
+// Child returns the address of a child module instance of the receiver,
+// identified by the given name and key.
+func (m ModuleInstance) Child(name string, key InstanceKey) ModuleInstance {
+	ret := make(ModuleInstance, 0, len(m)+1)
+	ret = append(ret, m...)
+	return append(ret, ModuleInstanceStep{Name: name, InstanceKey: key})
+}
+
+// IsKeyed returns true if any of the module instances in the receiver has
+// an instance key, i.e. it is called with "count" or "for_each".
+func (m ModuleInstance) IsKeyed() bool {
+	for _, step := range m {
+		if step.InstanceKey != NoKey {
+			return true
+		}
+	}
+	return false
+}
+
 func (s ModuleInstanceStep) String() string {
 	if s.InstanceKey != NoKey {
 		return s.Name + s.InstanceKey.String()
@@ -105,6 +105,19 @@ func parseRef(traversal hcl.Traversal) (*Reference, hcl.Diagnostics) {
 		remain := traversal[1:] // trim off "data" so we can use our shared resource reference parser
 		return parseResourceRef(DataResourceMode, rootRange, remain)
 
+	case "ephemeral":
+		if len(traversal) < 3 {
+			diags = diags.Append(&hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid reference",
+				Detail:   `The "ephemeral" object must be followed by two attribute names: the ephemeral resource type and the resource name.`,
+				Subject:  traversal.SourceRange().Ptr(),
+			})
+			return nil, diags
+		}
+		remain := traversal[1:] // trim off "ephemeral" so we can use our shared resource reference parser
+		return parseResourceRef(EphemeralResourceMode, rootRange, remain)
+
 	case "resource":
 		// This is an alias for the normal case of just using a managed resource
 		// type as a top-level symbol, which will serve as an escape mechanism
@@ -275,11 +288,11 @@ func parseResourceRef(mode ResourceMode, startRange hcl.Range, traversal hcl.Tra
 	case hcl.TraverseAttr:
 		typeName = tt.Name
 	default:
-		// If it isn't a TraverseRoot then it must be a "data" reference.
+		// If it isn't a TraverseRoot then it must be a "data" or "ephemeral" reference.
 		diags = diags.Append(&hcl.Diagnostic{
 			Severity: hcl.DiagError,
 			Summary:  "Invalid reference",
-			Detail:   `The "data" object does not support this operation.`,
+			Detail:   fmt.Sprintf(`The %q object does not support this operation.`, modeObjectName(mode)),
 			Subject:  traversal[0].SourceRange().Ptr(),
 		})
 		return nil, diags
@@ -291,6 +304,8 @@ func parseResourceRef(mode ResourceMode, startRange hcl.Range, traversal hcl.Tra
 		switch mode {
 		case DataResourceMode:
 			what = "data source"
+		case EphemeralResourceMode:
+			what = "ephemeral resource type"
 		default:
 			what = "resource type"
 		}
@@ -375,3 +390,14 @@ func parseSingleAttrRef(traversal hcl.Traversal) (string, hcl.Range, hcl.Travers
 	})
 	return "", hcl.Range{}, nil, diags
 }
+
+// modeObjectName returns the name of the top-level object used to refer
+// to resources of the given mode.
+func modeObjectName(mode ResourceMode) string {
+	switch mode {
+	case EphemeralResourceMode:
+		return "ephemeral"
+	default:
+		return "data"
+	}
+}
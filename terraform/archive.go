@@ -0,0 +1,200 @@
+package terraform
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/spf13/afero"
+)
+
+// Limits of the extracted archive. Since all files are extracted to memory,
+// archives exceeding the limits are rejected rather than exhausting memory.
+var (
+	maxArchiveSize    int64 = 256 << 20 // 256 MiB
+	maxArchiveEntries       = 100000
+)
+
+// archiveExtractor writes archive entries to the filesystem while tracking the limits.
+type archiveExtractor struct {
+	fs      afero.Afero
+	size    int64
+	entries int
+}
+
+// NewArchiveFs reads a module archive (zip, tar, or tar.gz) and returns a read-only
+// filesystem whose root is the root of the archive. The format is determined by
+// the file extension.
+//
+// All files are extracted to memory, so relative paths from the current directory
+// are resolved as paths from the archive root. An error is returned if the extracted
+// files exceed 256 MiB in total or 100,000 entries.
+func NewArchiveFs(archive string) (afero.Fs, error) {
+	fs := afero.Afero{Fs: afero.NewMemMapFs()}
+	ex := &archiveExtractor{fs: fs}
+
+	var err error
+	switch {
+	case strings.HasSuffix(archive, ".zip"):
+		err = ex.extractZip(archive)
+	case strings.HasSuffix(archive, ".tar"):
+		err = ex.extractTar(archive, false)
+	case strings.HasSuffix(archive, ".tar.gz"), strings.HasSuffix(archive, ".tgz"):
+		err = ex.extractTar(archive, true)
+	default:
+		return nil, fmt.Errorf(`"%s" is not a supported archive. Supported formats are: zip, tar, tar.gz`, archive)
+	}
+	if err != nil {
+		return nil, fmt.Errorf(`failed to read "%s": %w`, archive, err)
+	}
+
+	return afero.NewReadOnlyFs(fs.Fs), nil
+}
+
+func (ex *archiveExtractor) extractZip(archive string) error {
+	r, err := zip.OpenReader(archive)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	for _, file := range r.File {
+		name, err := archiveEntryName(file.Name)
+		if err != nil {
+			return err
+		}
+		if name == "" {
+			continue
+		}
+		if err := ex.addEntry(); err != nil {
+			return err
+		}
+
+		if file.FileInfo().IsDir() {
+			if err := ex.fs.MkdirAll(name, os.ModePerm); err != nil {
+				return err
+			}
+			continue
+		}
+		if !file.Mode().IsRegular() {
+			log.Printf("[DEBUG] Skip non-regular file in the archive: %s", file.Name)
+			continue
+		}
+
+		src, err := file.Open()
+		if err != nil {
+			return err
+		}
+		err = ex.writeEntry(name, src)
+		src.Close()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (ex *archiveExtractor) extractTar(archive string, gzipped bool) error {
+	f, err := os.Open(archive)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var r io.Reader = f
+	if gzipped {
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return err
+		}
+		defer gz.Close()
+		r = gz
+	}
+
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		name, err := archiveEntryName(hdr.Name)
+		if err != nil {
+			return err
+		}
+		if name == "" {
+			continue
+		}
+		if err := ex.addEntry(); err != nil {
+			return err
+		}
+
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := ex.fs.MkdirAll(name, os.ModePerm); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := ex.writeEntry(name, tr); err != nil {
+				return err
+			}
+		default:
+			log.Printf("[DEBUG] Skip non-regular file in the archive: %s", hdr.Name)
+		}
+	}
+}
+
+// archiveEntryName returns the normalized path of the entry relative to the archive root.
+// An empty string is returned for the root itself. Entries outside the root are rejected.
+func archiveEntryName(name string) (string, error) {
+	name = strings.ReplaceAll(name, `\`, "/")
+	for _, segment := range strings.Split(name, "/") {
+		if segment == ".." {
+			return "", fmt.Errorf(`invalid entry "%s" in the archive`, name)
+		}
+	}
+	return strings.TrimPrefix(path.Clean("/"+name), "/"), nil
+}
+
+func (ex *archiveExtractor) addEntry() error {
+	ex.entries++
+	if ex.entries > maxArchiveEntries {
+		return fmt.Errorf("the archive has too many entries (limit: %d)", maxArchiveEntries)
+	}
+	return nil
+}
+
+// writeEntry writes the entry to the filesystem. The declared size in the archive header
+// is not trusted, so the size is checked while reading the decompressed content.
+func (ex *archiveExtractor) writeEntry(name string, src io.Reader) error {
+	if err := ex.fs.MkdirAll(path.Dir(name), os.ModePerm); err != nil {
+		return err
+	}
+
+	f, err := ex.fs.Create(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	remaining := maxArchiveSize - ex.size
+	n, err := io.Copy(f, io.LimitReader(src, remaining+1))
+	ex.size += n
+	if err != nil {
+		return err
+	}
+	if n > remaining {
+		return fmt.Errorf("the extracted size of the archive exceeds the limit of %d bytes", maxArchiveSize)
+	}
+	return nil
+}
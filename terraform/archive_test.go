@@ -0,0 +1,242 @@
+package terraform
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/afero"
+)
+
+var archiveFiles = []struct {
+	name    string
+	content string
+}{
+	{name: "main.tf", content: `module "child" { source = "./modules/child" }`},
+	{name: "modules/child/main.tf", content: `variable "foo" {}`},
+}
+
+func TestNewArchiveFs(t *testing.T) {
+	tests := []struct {
+		name  string
+		write func(t *testing.T, path string)
+	}{
+		{name: "module.zip", write: writeTestZip},
+		{name: "module.tar", write: func(t *testing.T, path string) { writeTestTar(t, path, false) }},
+		{name: "module.tar.gz", write: func(t *testing.T, path string) { writeTestTar(t, path, true) }},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), test.name)
+			test.write(t, path)
+
+			fs, err := NewArchiveFs(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			wd, err := os.Getwd()
+			if err != nil {
+				t.Fatal(err)
+			}
+			loader, err := NewLoader(afero.Afero{Fs: fs}, wd)
+			if err != nil {
+				t.Fatal(err)
+			}
+			config, diags := loader.LoadConfig(".", CallLocalModule)
+			if diags.HasErrors() {
+				t.Fatal(diags)
+			}
+
+			if _, exists := config.Module.ModuleCalls["child"]; !exists {
+				t.Fatalf("module call is not loaded: %#v", config.Module.ModuleCalls)
+			}
+			testChildModule(t, config, "child", "modules/child")
+
+			// The filesystem is read-only
+			if err := afero.WriteFile(fs, "main.tf", []byte{}, os.ModePerm); err == nil {
+				t.Fatal("expected an error, but got nil")
+			}
+		})
+	}
+}
+
+func TestNewArchiveFs_errors(t *testing.T) {
+	dir := t.TempDir()
+
+	_, err := NewArchiveFs(filepath.Join(dir, "module.rar"))
+	expected := `"` + filepath.Join(dir, "module.rar") + `" is not a supported archive. Supported formats are: zip, tar, tar.gz`
+	if err == nil || err.Error() != expected {
+		t.Fatalf("expected=%s, got=%s", expected, err)
+	}
+
+	path := filepath.Join(dir, "traversal.zip")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := zip.NewWriter(f)
+	if _, err := w.Create("../main.tf"); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	_, err = NewArchiveFs(path)
+	expected = `failed to read "` + path + `": invalid entry "../main.tf" in the archive`
+	if err == nil || err.Error() != expected {
+		t.Fatalf("expected=%s, got=%s", expected, err)
+	}
+}
+
+func TestNewArchiveFs_limits(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "module.zip")
+	writeTestZip(t, path)
+
+	t.Run("size", func(t *testing.T) {
+		original := maxArchiveSize
+		defer func() { maxArchiveSize = original }()
+		maxArchiveSize = 50
+
+		_, err := NewArchiveFs(path)
+		expected := `failed to read "` + path + `": the extracted size of the archive exceeds the limit of 50 bytes`
+		if err == nil || err.Error() != expected {
+			t.Fatalf("expected=%s, got=%s", expected, err)
+		}
+	})
+
+	t.Run("entries", func(t *testing.T) {
+		original := maxArchiveEntries
+		defer func() { maxArchiveEntries = original }()
+		maxArchiveEntries = 1
+
+		_, err := NewArchiveFs(path)
+		expected := `failed to read "` + path + `": the archive has too many entries (limit: 1)`
+		if err == nil || err.Error() != expected {
+			t.Fatalf("expected=%s, got=%s", expected, err)
+		}
+	})
+}
+
+func TestLoadValuesFiles_archive(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "module.zip")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := zip.NewWriter(f)
+	for name, content := range map[string]string{
+		"main.tf":          `variable "foo" {}`,
+		"terraform.tfvars": `foo = "archive"`,
+	} {
+		dst, err := w.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := io.WriteString(dst, content); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	varFile := filepath.Join(dir, "local.tfvars")
+	if err := os.WriteFile(varFile, []byte(`foo = "local"`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	fs, err := NewArchiveFs(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	loader, err := NewLoader(afero.Afero{Fs: fs}, wd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	loader.SetVarFileFs(afero.NewOsFs())
+
+	values, diags := loader.LoadValuesFiles(".", varFile)
+	if diags.HasErrors() {
+		t.Fatal(diags)
+	}
+	if len(values) != 2 {
+		t.Fatalf("want 2 values files, got %d", len(values))
+	}
+	// terraform.tfvars is read from the archive, and --var-file is read from the local disk
+	if got := values[0]["foo"].Value.AsString(); got != "archive" {
+		t.Errorf("terraform.tfvars: want=archive, got=%s", got)
+	}
+	if got := values[1]["foo"].Value.AsString(); got != "local" {
+		t.Errorf("%s: want=local, got=%s", varFile, got)
+	}
+}
+
+// writeTestZip writes a zip archive without directory entries
+func writeTestZip(t *testing.T, path string) {
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	w := zip.NewWriter(f)
+	for _, file := range archiveFiles {
+		dst, err := w.Create(file.name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := io.WriteString(dst, file.content); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func writeTestTar(t *testing.T, path string, gzipped bool) {
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	var dst io.Writer = f
+	if gzipped {
+		gz := gzip.NewWriter(f)
+		defer gz.Close()
+		dst = gz
+	}
+
+	w := tar.NewWriter(dst)
+	for _, dir := range []string{"./", "./modules/", "./modules/child/"} {
+		if err := w.WriteHeader(&tar.Header{Name: dir, Typeflag: tar.TypeDir, Mode: 0o755}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, file := range archiveFiles {
+		if err := w.WriteHeader(&tar.Header{Name: "./" + file.name, Typeflag: tar.TypeReg, Mode: 0o644, Size: int64(len(file.content))}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := io.WriteString(w, file.content); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
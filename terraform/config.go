@@ -5,6 +5,7 @@ package terraform
 
 import (
 	"fmt"
+	"path/filepath"
 	"sort"
 
 	"github.com/hashicorp/go-version"
@@ -54,15 +55,24 @@ func NewEmptyConfig() *Config {
 // file-level invariants validated. If the returned diagnostics contains errors,
 // the returned module tree may be incomplete but can still be used carefully
 // for static analysis.
-func BuildConfig(root *Module, walker ModuleWalker) (*Config, hcl.Diagnostics) {
+//
+// In OpenTofu, module sources and versions can refer to input values. The passed
+// values are used as input values of the root module, and arguments of module calls
+// are used as input values of child modules. The last one passed takes precedence.
+func BuildConfig(root *Module, walker ModuleWalker, values ...InputValues) (*Config, hcl.Diagnostics) {
 	var diags hcl.Diagnostics
+	if root.language == LanguageOpenTofu && len(values) > 0 {
+		root, diags = root.withInputs(InputValues{}.Override(values...))
+	}
+
+	var childDiags hcl.Diagnostics
 	cfg := &Config{
 		Module: root,
 	}
 	cfg.Root = cfg // Root module is self-referential.
-	cfg.Children, diags = buildChildModules(cfg, walker)
+	cfg.Children, childDiags = buildChildModules(cfg, walker)
 
-	return cfg, diags
+	return cfg, diags.Extend(childDiags)
 }
 
 func buildChildModules(parent *Config, walker ModuleWalker) (map[string]*Config, hcl.Diagnostics) {
@@ -96,11 +106,12 @@ func buildChildModules(parent *Config, walker ModuleWalker) (map[string]*Config,
 		}
 
 		req := ModuleRequest{
-			Name:       call.Name,
-			Path:       path,
-			SourceAddr: call.SourceAddr,
-			Parent:     parent,
-			CallRange:  call.DeclRange,
+			Name:              call.Name,
+			Path:              path,
+			SourceAddr:        call.SourceAddr,
+			VersionConstraint: call.Version,
+			Parent:            parent,
+			CallRange:         call.DeclRange,
 		}
 
 		mod, _, modDiags := walker.LoadModule(&req)
@@ -111,6 +122,10 @@ func buildChildModules(parent *Config, walker ModuleWalker) (map[string]*Config,
 			// returned at least one error diagnostic in that case.
 			continue
 		}
+		if mod.language == LanguageOpenTofu && len(mod.moduleBlocks) > 0 {
+			mod, modDiags = mod.withInputs(parent.Module.moduleCallArguments(call.Name, mod.Variables))
+			diags = append(diags, modDiags...)
+		}
 
 		child := &Config{
 			Root:   parent.Root,
@@ -140,6 +155,32 @@ func (c *Config) DescendentForInstance(path addrs.ModuleInstance) *Config {
 	return current
 }
 
+// ResolvedSource returns the source address of the module after resolution.
+// For local modules, this is the module directory relative to the current directory.
+// For remote modules, this is the normalized address, such as "registry.terraform.io/hashicorp/consul/aws".
+// An empty string is returned for the root module.
+func (c *Config) ResolvedSource() string {
+	if c.Path.IsRoot() {
+		return ""
+	}
+
+	parent := c.Root.DescendentForInstance(c.Path[:len(c.Path)-1].UnkeyedInstanceShim())
+	if parent == nil {
+		return ""
+	}
+	call, exists := parent.Module.ModuleCalls[c.Path[len(c.Path)-1]]
+	if !exists || call.SourceAddr == nil {
+		return ""
+	}
+
+	switch source := call.SourceAddr.(type) {
+	case addrs.ModuleSourceLocal:
+		return filepath.ToSlash(c.Module.SourceDir)
+	default:
+		return canonicalModuleSource(source.String(), c.Module.language)
+	}
+}
+
 // A ModuleWalker knows how to find and load a child module given details about
 // the module to be loaded and a reference to its partially-loaded parent
 // Config.
@@ -186,6 +227,10 @@ type ModuleRequest struct {
 	// configuration.
 	SourceAddr addrs.ModuleSource
 
+	// VersionConstraint is the version constraint applied to the module in
+	// configuration. This is only meaningful for registry modules.
+	VersionConstraint version.Constraints
+
 	// Parent is the partially-constructed module tree node that the loaded
 	// module will be added to. Callers may refer to any field of this
 	// structure except Children, which is still under construction when
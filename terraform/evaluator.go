@@ -5,6 +5,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 
 	"github.com/agext/levenshtein"
 	"github.com/hashicorp/hcl/v2"
@@ -12,6 +13,7 @@ import (
 	"github.com/terraform-linters/tflint-plugin-sdk/terraform/lang/marks"
 	"github.com/terraform-linters/tflint/terraform/addrs"
 	"github.com/terraform-linters/tflint/terraform/lang"
+	tfmarks "github.com/terraform-linters/tflint/terraform/lang/marks"
 	"github.com/zclconf/go-cty/cty"
 	"github.com/zclconf/go-cty/cty/convert"
 )
@@ -79,6 +81,43 @@ type Evaluator struct {
 	Config         *Config
 	VariableValues map[string]map[string]cty.Value
 	CallStack      *CallStack
+
+	// Local values and input variables are memoized per evaluator because
+	// their expressions are re-evaluated every time they are referenced.
+	// The scope is also kept so that the function table is built only once.
+	cacheMu     sync.Mutex
+	cache       map[string]evaluatedValue
+	cachedScope *lang.Scope
+}
+
+type evaluatedValue struct {
+	val   cty.Value
+	diags hcl.Diagnostics
+}
+
+// ClearCache discards memoized values. This must be called when the configuration
+// referenced by the evaluator is changed, for example, after applying autofixes.
+func (e *Evaluator) ClearCache() {
+	e.cacheMu.Lock()
+	defer e.cacheMu.Unlock()
+	e.cache = nil
+	e.cachedScope = nil
+}
+
+func (e *Evaluator) cachedValue(key string) (evaluatedValue, bool) {
+	e.cacheMu.Lock()
+	defer e.cacheMu.Unlock()
+	ret, exists := e.cache[key]
+	return ret, exists
+}
+
+func (e *Evaluator) storeValue(key string, val cty.Value, diags hcl.Diagnostics) {
+	e.cacheMu.Lock()
+	defer e.cacheMu.Unlock()
+	if e.cache == nil {
+		e.cache = map[string]evaluatedValue{}
+	}
+	e.cache[key] = evaluatedValue{val: val, diags: diags}
 }
 
 // EvaluateExpr takes the given HCL expression and evaluates it to produce a value.
@@ -102,12 +141,28 @@ func (e *Evaluator) ExpandBlock(body hcl.Body, schema *hclext.BodySchema) (hcl.B
 }
 
 func (e *Evaluator) scope() *lang.Scope {
-	return &lang.Scope{
+	e.cacheMu.Lock()
+	defer e.cacheMu.Unlock()
+	if e.cachedScope != nil {
+		return e.cachedScope
+	}
+
+	e.cachedScope = &lang.Scope{
 		Data: &evaluationData{
 			Evaluator:  e,
 			ModulePath: e.ModulePath,
 		},
+		ExpandProviders: e.Config != nil && e.Config.Root.Module.language == LanguageOpenTofu,
 	}
+	// Provider-defined functions are available if they are called in the module.
+	if e.Config != nil {
+		if cfg := e.Config.DescendentForInstance(e.ModulePath); cfg != nil {
+			for _, file := range cfg.Module.Files {
+				e.cachedScope.FunctionCalls = append(e.cachedScope.FunctionCalls, lang.FunctionCallsInBody(file.Body)...)
+			}
+		}
+	}
+	return e.cachedScope
 }
 
 type evaluationData struct {
@@ -177,6 +232,11 @@ func (d *evaluationData) GetInputVariable(addr addrs.InputVariable, rng hcl.Rang
 		return cty.UnknownVal(config.Type), diags
 	}
 
+	cacheKey := moduleAddrStr + "." + addr.String()
+	if cached, exists := d.Evaluator.cachedValue(cacheKey); exists {
+		return cached.val, cached.diags
+	}
+
 	// In Terraform, it is the responsibility of the VariableTransformer
 	// to convert the variable to the "final value", including the type conversion.
 	// However, since TFLint does not preprocess variables by Graph Builder,
@@ -220,7 +280,12 @@ func (d *evaluationData) GetInputVariable(addr addrs.InputVariable, rng hcl.Rang
 	if config.Sensitive {
 		val = val.Mark(marks.Sensitive)
 	}
+	// Mark if ephemeral
+	if config.Ephemeral {
+		val = val.Mark(tfmarks.Ephemeral)
+	}
 
+	d.Evaluator.storeValue(cacheKey, val, diags)
 	return val, diags
 }
 
@@ -256,6 +321,11 @@ func (d *evaluationData) GetLocalValue(addr addrs.LocalValue, rng hcl.Range) (ct
 		return cty.DynamicVal, diags
 	}
 
+	cacheKey := d.ModulePath.String() + "." + addr.String()
+	if cached, exists := d.Evaluator.cachedValue(cacheKey); exists {
+		return cached.val, cached.diags
+	}
+
 	// Build a call stack for circular reference detection only when getting a local value.
 	if diags := d.Evaluator.CallStack.Push(addrs.Reference{Subject: addr, SourceRange: rng}); diags.HasErrors() {
 		return cty.UnknownVal(cty.DynamicPseudoType), diags
@@ -264,6 +334,10 @@ func (d *evaluationData) GetLocalValue(addr addrs.LocalValue, rng hcl.Range) (ct
 	val, diags := d.Evaluator.EvaluateExpr(config.Expr, cty.DynamicPseudoType)
 
 	d.Evaluator.CallStack.Pop()
+	if !diags.HasErrors() {
+		// Errors may depend on the call stack (e.g. circular references), so they are not memoized.
+		d.Evaluator.storeValue(cacheKey, val, diags)
+	}
 	return val, diags
 }
 
@@ -336,6 +410,22 @@ func (d *evaluationData) GetPathAttr(addr addrs.PathAttr, rng hcl.Range) (cty.Va
 	}
 }
 
+func (d *evaluationData) GetEphemeralResource(addr addrs.Resource, rng hcl.Range) (cty.Value, hcl.Diagnostics) {
+	moduleConfig := d.Evaluator.Config.DescendentForInstance(d.ModulePath)
+	if moduleConfig == nil {
+		// should never happen, since we can't be evaluating in a module
+		// that wasn't mentioned in configuration.
+		panic(fmt.Sprintf("ephemeral resource read from %s, which has no configuration", d.ModulePath))
+	}
+
+	// Like managed resources, ephemeral resources are always unknown.
+	// Undeclared resources are not reported as errors, as with managed resources.
+	if _, exists := moduleConfig.Module.EphemeralResources[addr.Type][addr.Name]; !exists {
+		return cty.DynamicVal, nil
+	}
+	return cty.DynamicVal.Mark(tfmarks.Ephemeral), nil
+}
+
 func (d *evaluationData) GetTerraformAttr(addr addrs.TerraformAttr, rng hcl.Range) (cty.Value, hcl.Diagnostics) {
 	var diags hcl.Diagnostics
 	switch addr.Name {
@@ -344,6 +434,11 @@ func (d *evaluationData) GetTerraformAttr(addr addrs.TerraformAttr, rng hcl.Rang
 		workspaceName := d.Evaluator.Meta.Env
 		return cty.StringVal(workspaceName), diags
 
+	case "applying":
+		// TFLint never applies, so terraform.applying is always false.
+		// As in Terraform, this value is ephemeral.
+		return cty.False.Mark(tfmarks.Ephemeral), diags
+
 	case "env":
 		// Prior to Terraform 0.12 there was an attribute "env", which was
 		// an alias name for "workspace". This was deprecated and is now
@@ -360,7 +455,7 @@ func (d *evaluationData) GetTerraformAttr(addr addrs.TerraformAttr, rng hcl.Rang
 		diags = diags.Append(&hcl.Diagnostic{
 			Severity: hcl.DiagError,
 			Summary:  `Invalid "terraform" attribute`,
-			Detail:   fmt.Sprintf(`The "terraform" object does not have an attribute named %q. The only supported attribute is terraform.workspace, the name of the currently-selected workspace.`, addr.Name),
+			Detail:   fmt.Sprintf(`The "terraform" object does not have an attribute named %q. The only supported attributes are terraform.workspace, the name of the currently-selected workspace, and terraform.applying, a boolean which is true only during apply.`, addr.Name),
 			Subject:  rng.Ptr(),
 		})
 		return cty.DynamicVal, diags
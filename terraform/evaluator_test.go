@@ -421,6 +421,86 @@ variable "foo" {
 			want:     `cty.StringVal("bar")`,
 			errCheck: neverHappend,
 		},
+		{
+			name: "ephemeral variable",
+			config: `
+variable "foo" {
+  ephemeral = true
+  default   = "bar"
+}`,
+			expr:     expr(`var.foo`),
+			ty:       cty.String,
+			want:     `cty.StringVal("bar").Mark(marks.Ephemeral)`,
+			errCheck: neverHappend,
+		},
+		{
+			name: "interpolation with ephemeral variable",
+			config: `
+variable "foo" {
+  ephemeral = true
+  default   = "bar"
+}`,
+			expr:     expr(`"${var.foo}-baz"`),
+			ty:       cty.String,
+			want:     `cty.StringVal("bar-baz").Mark(marks.Ephemeral)`,
+			errCheck: neverHappend,
+		},
+		{
+			name: "ephemeral resource",
+			config: `
+ephemeral "random_password" "main" {}`,
+			expr:     expr(`ephemeral.random_password.main`),
+			ty:       cty.DynamicPseudoType,
+			want:     `cty.DynamicVal.Mark(marks.Ephemeral)`,
+			errCheck: neverHappend,
+		},
+		{
+			name: "ephemeral resource attribute",
+			config: `
+ephemeral "random_password" "main" {}`,
+			expr:     expr(`ephemeral.random_password.main.result`),
+			ty:       cty.String,
+			want:     `cty.UnknownVal(cty.String).Mark(marks.Ephemeral)`,
+			errCheck: neverHappend,
+		},
+		{
+			name: "interpolation with ephemeral resource",
+			config: `
+ephemeral "random_password" "main" {
+  count = 2
+}`,
+			expr:     expr(`"${ephemeral.random_password.main[0].result}-foo"`),
+			ty:       cty.String,
+			want:     `cty.UnknownVal(cty.String).RefineNotNull().Mark(marks.Ephemeral)`,
+			errCheck: neverHappend,
+		},
+		{
+			name:     "undeclared ephemeral resource",
+			expr:     expr(`ephemeral.random_password.main.result`),
+			ty:       cty.String,
+			want:     `cty.UnknownVal(cty.String)`,
+			errCheck: neverHappend,
+		},
+
+		{
+			name: "ephemeralasnull",
+			config: `
+variable "foo" {
+  ephemeral = true
+  default   = "bar"
+}`,
+			expr:     expr(`ephemeralasnull(var.foo)`),
+			ty:       cty.String,
+			want:     `cty.NullVal(cty.String)`,
+			errCheck: neverHappend,
+		},
+		{
+			name:     "terraform applying",
+			expr:     expr(`terraform.applying`),
+			ty:       cty.Bool,
+			want:     `cty.False.Mark(marks.Ephemeral)`,
+			errCheck: neverHappend,
+		},
 		{
 			name: "module variable optional attributes",
 			config: `
@@ -2104,3 +2184,115 @@ resource "aws_instance" "main" {
 		})
 	}
 }
+
+func TestEvaluateExpr_providerFunctions(t *testing.T) {
+	fs := afero.Afero{Fs: afero.NewMemMapFs()}
+	files := map[string]string{
+		"main.tf": `
+locals {
+  account_id = provider::aws::arn_parse(var.arn).account_id
+}`,
+		"variables.tf.json": `{
+  "variable": {
+    "arn": {
+      "default": "${provider::aws::arn_build(\"aws\", \"iam\", \"\", \"123456789012\", \"user/foo\")}"
+    }
+  }
+}`,
+	}
+	for name, content := range files {
+		if err := fs.WriteFile(name, []byte(content), os.ModePerm); err != nil {
+			t.Fatal(err)
+		}
+	}
+	mod, diags := NewParser(fs).LoadConfigDir(".", ".")
+	if diags.HasErrors() {
+		t.Fatal(diags)
+	}
+	config, diags := BuildConfig(mod, ModuleWalkerFunc(func(req *ModuleRequest) (*Module, *version.Version, hcl.Diagnostics) { return nil, nil, nil }))
+	if diags.HasErrors() {
+		t.Fatal(diags)
+	}
+
+	evaluator := &Evaluator{
+		Meta:           &ContextMeta{Env: "default"},
+		ModulePath:     config.Path.UnkeyedInstanceShim(),
+		Config:         config,
+		VariableValues: map[string]map[string]cty.Value{"": {"arn": cty.StringVal("arn:aws:iam::123456789012:user/foo")}},
+		CallStack:      NewCallStack(),
+	}
+
+	// Provider-defined functions called in the module are available in any expression,
+	// including expressions in JSON files and expressions passed by plugins.
+	for _, src := range []string{
+		`provider::aws::arn_parse(var.arn).account_id`,
+		`provider::aws::arn_build("aws", "iam", "", "123456789012", "user/foo")`,
+	} {
+		expr, diags := hclsyntax.ParseExpression([]byte(src), "", hcl.InitialPos)
+		if diags.HasErrors() {
+			t.Fatal(diags)
+		}
+		got, diags := evaluator.EvaluateExpr(expr, cty.DynamicPseudoType)
+		if diags.HasErrors() {
+			t.Fatalf("%s: %s", src, diags)
+		}
+		if got.IsKnown() {
+			t.Errorf("%s: want unknown, got %#v", src, got)
+		}
+	}
+
+	// Functions that are not called in the module are unknown
+	expr, diags := hclsyntax.ParseExpression([]byte(`provider::aws::trim_iam_role_path("foo")`), "", hcl.InitialPos)
+	if diags.HasErrors() {
+		t.Fatal(diags)
+	}
+	if _, diags := evaluator.EvaluateExpr(expr, cty.DynamicPseudoType); !diags.HasErrors() {
+		t.Error("want an error, got no error")
+	}
+}
+
+// BenchmarkEvaluateExpr_locals evaluates a local value that transitively depends on
+// many other local values. Each local value refers to the previous two, so without
+// memoization the number of evaluations grows exponentially.
+func BenchmarkEvaluateExpr_locals(b *testing.B) {
+	src := "variable \"input\" {\n  default = \"foo\"\n}\n\nlocals {\n  local_0 = var.input\n  local_1 = var.input\n"
+	for i := 2; i < 24; i++ {
+		src += fmt.Sprintf("  local_%d = \"${local.local_%d}-${local.local_%d}\"\n", i, i-1, i-2)
+	}
+	src += "}\n"
+
+	fs := afero.Afero{Fs: afero.NewMemMapFs()}
+	if err := fs.WriteFile("main.tf", []byte(src), os.ModePerm); err != nil {
+		b.Fatal(err)
+	}
+	mod, diags := NewParser(fs).LoadConfigDir(".", ".")
+	if diags.HasErrors() {
+		b.Fatal(diags)
+	}
+	config, diags := BuildConfig(mod, ModuleWalkerFunc(func(req *ModuleRequest) (*Module, *version.Version, hcl.Diagnostics) { return nil, nil, nil }))
+	if diags.HasErrors() {
+		b.Fatal(diags)
+	}
+	variableValues, diags := VariableValues(config)
+	if diags.HasErrors() {
+		b.Fatal(diags)
+	}
+	expr, diags := hclsyntax.ParseExpression([]byte(`local.local_23`), "", hcl.InitialPos)
+	if diags.HasErrors() {
+		b.Fatal(diags)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		evaluator := &Evaluator{
+			Meta:           &ContextMeta{Env: "default"},
+			ModulePath:     config.Path.UnkeyedInstanceShim(),
+			Config:         config,
+			VariableValues: variableValues,
+			CallStack:      NewCallStack(),
+		}
+		if _, diags := evaluator.EvaluateExpr(expr, cty.String); diags.HasErrors() {
+			b.Fatal(diags)
+		}
+	}
+}
@@ -26,4 +26,5 @@ type Data interface {
 	GetPathAttr(addrs.PathAttr, hcl.Range) (cty.Value, hcl.Diagnostics)
 	GetTerraformAttr(addrs.TerraformAttr, hcl.Range) (cty.Value, hcl.Diagnostics)
 	GetInputVariable(addrs.InputVariable, hcl.Range) (cty.Value, hcl.Diagnostics)
+	GetEphemeralResource(addrs.Resource, hcl.Range) (cty.Value, hcl.Diagnostics)
 }
@@ -13,6 +13,8 @@ type dataForTests struct {
 	PathAttrs      map[string]cty.Value
 	TerraformAttrs map[string]cty.Value
 	InputVariables map[string]cty.Value
+	// EphemeralResources is keyed by "type.name"
+	EphemeralResources map[string]cty.Value
 }
 
 var _ Data = &dataForTests{}
@@ -40,3 +42,10 @@ func (d *dataForTests) GetPathAttr(addr addrs.PathAttr, rng hcl.Range) (cty.Valu
 func (d *dataForTests) GetTerraformAttr(addr addrs.TerraformAttr, rng hcl.Range) (cty.Value, hcl.Diagnostics) {
 	return d.TerraformAttrs[addr.Name], nil
 }
+
+func (d *dataForTests) GetEphemeralResource(addr addrs.Resource, rng hcl.Range) (cty.Value, hcl.Diagnostics) {
+	if val, exists := d.EphemeralResources[addr.String()]; exists {
+		return val, nil
+	}
+	return cty.DynamicVal, nil
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/hashicorp/hcl/v2"
 	"github.com/terraform-linters/tflint-plugin-sdk/hclext"
 	"github.com/terraform-linters/tflint/terraform/addrs"
+	"github.com/terraform-linters/tflint/terraform/lang/marks"
 	"github.com/terraform-linters/tflint/terraform/tfdiags"
 	"github.com/terraform-linters/tflint/terraform/tfhcl"
 	"github.com/zclconf/go-cty/cty"
@@ -13,6 +14,7 @@ import (
 )
 
 // ExpandBlock expands "dynamic" blocks and resources/modules with count/for_each.
+// Providers with for_each are also expanded if ExpandProviders is set.
 // Note that Terraform only expands dynamic blocks, but TFLint also expands
 // count/for_each here.
 //
@@ -25,6 +27,9 @@ func (s *Scope) ExpandBlock(body hcl.Body, schema *hclext.BodySchema) (hcl.Body,
 	ctx, ctxDiags := s.EvalContext(refs)
 	diags = diags.Extend(ctxDiags)
 
+	if s.ExpandProviders {
+		return tfhcl.ExpandWithProviders(body, ctx), diags
+	}
 	return tfhcl.Expand(body, ctx), diags
 }
 
@@ -52,6 +57,13 @@ func (s *Scope) EvalExpr(expr hcl.Expression, wantType cty.Type) (cty.Value, hcl
 	val, evalDiags := expr.Value(ctx)
 	diags = diags.Extend(evalDiags)
 
+	// HCL drops marks when traversing unknown values of dynamic type, so attributes
+	// of ephemeral resources (e.g. ephemeral.random_password.main.result) lose the mark.
+	// As in Terraform, unknown values derived from ephemeral resources are marked as ephemeral.
+	if ephemeral, exists := ctx.Variables["ephemeral"]; exists && !val.IsWhollyKnown() && marks.Contains(ephemeral, marks.Ephemeral) {
+		val = val.Mark(marks.Ephemeral)
+	}
+
 	if wantType != cty.DynamicPseudoType {
 		var convErr error
 		val, convErr = convert.Convert(val, wantType)
@@ -108,6 +120,7 @@ func (s *Scope) evalContext(refs []*addrs.Reference, selfAddr addrs.Referenceabl
 	// warnings, but once we've gathered all the data we'll then skip anything
 	// that's redundant in the process of populating our values map.
 	managedResources := map[string]cty.Value{}
+	ephemeralResources := map[string]map[string]cty.Value{}
 	inputVariables := map[string]cty.Value{}
 	localValues := map[string]cty.Value{}
 	pathAttrs := map[string]cty.Value{}
@@ -132,10 +145,17 @@ func (s *Scope) evalContext(refs []*addrs.Reference, selfAddr addrs.Referenceabl
 		case addrs.Resource:
 			// Managed resources are not supported by TFLint, but it does support arbitrary
 			// key names, so it gathers the referenced resource names.
-			if subj.Mode != addrs.ManagedResourceMode {
-				continue
+			switch subj.Mode {
+			case addrs.ManagedResourceMode:
+				managedResources[subj.Type] = cty.UnknownVal(cty.DynamicPseudoType)
+			case addrs.EphemeralResourceMode:
+				val, valDiags := normalizeRefValue(s.Data.GetEphemeralResource(subj, rng))
+				diags = diags.Extend(valDiags)
+				if _, exists := ephemeralResources[subj.Type]; !exists {
+					ephemeralResources[subj.Type] = map[string]cty.Value{}
+				}
+				ephemeralResources[subj.Type][subj.Name] = val
 			}
-			managedResources[subj.Type] = cty.UnknownVal(cty.DynamicPseudoType)
 
 		case addrs.InputVariable:
 			val, valDiags := normalizeRefValue(s.Data.GetInputVariable(subj, rng))
@@ -182,6 +202,11 @@ func (s *Scope) evalContext(refs []*addrs.Reference, selfAddr addrs.Referenceabl
 	vals["terraform"] = cty.ObjectVal(terraformAttrs)
 	vals["count"] = cty.ObjectVal(countAttrs)
 	vals["each"] = cty.ObjectVal(forEachAttrs)
+	ephemeralVals := map[string]cty.Value{}
+	for typeName, resources := range ephemeralResources {
+		ephemeralVals[typeName] = cty.ObjectVal(resources)
+	}
+	vals["ephemeral"] = cty.ObjectVal(ephemeralVals)
 
 	// The following are unknown values as they are not supported by TFLint.
 	vals["resource"] = cty.UnknownVal(cty.DynamicPseudoType)
@@ -161,6 +161,20 @@ func TestScopeEvalContext(t *testing.T) {
 				"self":          cty.DynamicVal,
 			},
 		},
+		{
+			`ephemeral.random_password.foo.result`,
+			map[string]cty.Value{
+				"ephemeral": cty.ObjectVal(map[string]cty.Value{
+					"random_password": cty.ObjectVal(map[string]cty.Value{
+						"foo": cty.DynamicVal,
+					}),
+				}),
+				"resource": cty.DynamicVal,
+				"data":     cty.DynamicVal,
+				"module":   cty.DynamicVal,
+				"self":     cty.DynamicVal,
+			},
+		},
 		{
 			`path.module`,
 			map[string]cty.Value{
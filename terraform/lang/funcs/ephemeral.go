@@ -0,0 +1,50 @@
+package funcs
+
+import (
+	"github.com/terraform-linters/tflint/terraform/lang/marks"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/function"
+)
+
+// EphemeralAsNullFunc returns a value identical to its argument except that
+// any ephemeral values within it are replaced with null values.
+var EphemeralAsNullFunc = function.New(&function.Spec{
+	Params: []function.Parameter{
+		{
+			Name:             "value",
+			Type:             cty.DynamicPseudoType,
+			AllowUnknown:     true,
+			AllowNull:        true,
+			AllowMarked:      true,
+			AllowDynamicType: true,
+		},
+	},
+	Type: func(args []cty.Value) (cty.Type, error) {
+		// This function only affects the value's content, so the result
+		// type is always the same as the argument type.
+		return args[0].Type(), nil
+	},
+	Impl: func(args []cty.Value, retType cty.Type) (ret cty.Value, err error) {
+		return cty.Transform(args[0], func(p cty.Path, v cty.Value) (cty.Value, error) {
+			_, givenMarks := v.Unmark()
+			if _, isEphemeral := givenMarks[marks.Ephemeral]; !isEphemeral {
+				return v, nil
+			}
+
+			// Strip the ephemeral mark but retain any other marks
+			// that might be present on the input.
+			delete(givenMarks, marks.Ephemeral)
+			if !v.IsKnown() {
+				// If the source value is unknown then we must leave it
+				// unknown because its final type might be more precise
+				// than the associated type constraint.
+				return cty.UnknownVal(v.Type()).WithMarks(givenMarks), nil
+			}
+			return cty.NullVal(v.Type()).WithMarks(givenMarks), nil
+		})
+	},
+})
+
+func EphemeralAsNull(v cty.Value) (cty.Value, error) {
+	return EphemeralAsNullFunc.Call([]cty.Value{v})
+}
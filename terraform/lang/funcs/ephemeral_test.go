@@ -0,0 +1,68 @@
+package funcs
+
+import (
+	"fmt"
+	"testing"
+
+	sdkmarks "github.com/terraform-linters/tflint-plugin-sdk/terraform/lang/marks"
+	"github.com/terraform-linters/tflint/terraform/lang/marks"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestEphemeralAsNull(t *testing.T) {
+	tests := []struct {
+		Input cty.Value
+		Want  cty.Value
+	}{
+		{
+			cty.StringVal("foo"),
+			cty.StringVal("foo"),
+		},
+		{
+			cty.StringVal("foo").Mark(marks.Ephemeral),
+			cty.NullVal(cty.String),
+		},
+		{
+			cty.UnknownVal(cty.String).Mark(marks.Ephemeral),
+			cty.UnknownVal(cty.String),
+		},
+		{
+			// Other marks are retained
+			cty.StringVal("foo").Mark(marks.Ephemeral).Mark(sdkmarks.Sensitive),
+			cty.NullVal(cty.String).Mark(sdkmarks.Sensitive),
+		},
+		{
+			cty.ObjectVal(map[string]cty.Value{
+				"foo": cty.StringVal("bar").Mark(marks.Ephemeral),
+				"baz": cty.StringVal("qux"),
+			}),
+			cty.ObjectVal(map[string]cty.Value{
+				"foo": cty.NullVal(cty.String),
+				"baz": cty.StringVal("qux"),
+			}),
+		},
+		{
+			cty.ListVal([]cty.Value{
+				cty.StringVal("foo"),
+				cty.StringVal("bar").Mark(marks.Ephemeral),
+			}),
+			cty.ListVal([]cty.Value{
+				cty.StringVal("foo"),
+				cty.NullVal(cty.String),
+			}),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("ephemeralasnull(%#v)", test.Input), func(t *testing.T) {
+			got, err := EphemeralAsNull(test.Input)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if !got.RawEquals(test.Want) {
+				t.Errorf("wrong result\ngot:  %#v\nwant: %#v", got, test.Want)
+			}
+		})
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strings"
 	"unicode/utf8"
 
 	"github.com/bmatcuk/doublestar"
@@ -96,60 +97,8 @@ func MakeTemplateFileFunc(baseDir string, funcsCb func() map[string]function.Fun
 	}
 
 	renderTmpl := func(expr hcl.Expression, varsVal cty.Value) (cty.Value, error) {
-		if varsTy := varsVal.Type(); !(varsTy.IsMapType() || varsTy.IsObjectType()) {
-			return cty.DynamicVal, function.NewArgErrorf(1, "invalid vars value: must be a map") // or an object, but we don't strongly distinguish these most of the time
-		}
-
-		ctx := &hcl.EvalContext{
-			Variables: varsVal.AsValueMap(),
-		}
-
-		// We require all of the variables to be valid HCL identifiers, because
-		// otherwise there would be no way to refer to them in the template
-		// anyway. Rejecting this here gives better feedback to the user
-		// than a syntax error somewhere in the template itself.
-		for n := range ctx.Variables {
-			if !hclsyntax.ValidIdentifier(n) {
-				// This error message intentionally doesn't describe _all_ of
-				// the different permutations that are technically valid as an
-				// HCL identifier, but rather focuses on what we might
-				// consider to be an "idiomatic" variable name.
-				return cty.DynamicVal, function.NewArgErrorf(1, "invalid template variable name %q: must start with a letter, followed by zero or more letters, digits, and underscores", n)
-			}
-		}
-
-		// We'll pre-check references in the template here so we can give a
-		// more specialized error message than HCL would by default, so it's
-		// clearer that this problem is coming from a templatefile call.
-		for _, traversal := range expr.Variables() {
-			root := traversal.RootName()
-			if _, ok := ctx.Variables[root]; !ok {
-				return cty.DynamicVal, function.NewArgErrorf(1, "vars map does not contain key %q, referenced at %s", root, traversal[0].SourceRange())
-			}
-		}
-
-		givenFuncs := funcsCb() // this callback indirection is to avoid chicken/egg problems
-		funcs := make(map[string]function.Function, len(givenFuncs))
-		for name, fn := range givenFuncs {
-			if name == "templatefile" {
-				// We stub this one out to prevent recursive calls.
-				funcs[name] = function.New(&function.Spec{
-					Params: params,
-					Type: func(args []cty.Value) (cty.Type, error) {
-						return cty.NilType, fmt.Errorf("cannot recursively call templatefile from inside templatefile call")
-					},
-				})
-				continue
-			}
-			funcs[name] = fn
-		}
-		ctx.Functions = funcs
-
-		val, diags := expr.Value(ctx)
-		if diags.HasErrors() {
-			return cty.DynamicVal, diags
-		}
-		return val, nil
+		// this callback indirection is to avoid chicken/egg problems
+		return renderTemplate(expr, varsVal, templateFuncs(funcsCb()))
 	}
 
 	return function.New(&function.Spec{
@@ -498,3 +447,70 @@ func Dirname(path cty.Value) (cty.Value, error) {
 func Pathexpand(path cty.Value) (cty.Value, error) {
 	return PathExpandFunc.Call([]cty.Value{path})
 }
+
+// renderTemplate renders the given template expression with the given
+// variables and functions. It is shared by templatefile and templatestring.
+func renderTemplate(expr hcl.Expression, varsVal cty.Value, funcs map[string]function.Function) (cty.Value, error) {
+	if varsTy := varsVal.Type(); !(varsTy.IsMapType() || varsTy.IsObjectType()) {
+		return cty.DynamicVal, function.NewArgErrorf(1, "invalid vars value: must be a map") // or an object, but we don't strongly distinguish these most of the time
+	}
+
+	ctx := &hcl.EvalContext{
+		Variables: varsVal.AsValueMap(),
+		Functions: funcs,
+	}
+
+	// We require all of the variables to be valid HCL identifiers, because
+	// otherwise there would be no way to refer to them in the template
+	// anyway. Rejecting this here gives better feedback to the user
+	// than a syntax error somewhere in the template itself.
+	for n := range ctx.Variables {
+		if !hclsyntax.ValidIdentifier(n) {
+			// This error message intentionally doesn't describe _all_ of
+			// the different permutations that are technically valid as an
+			// HCL identifier, but rather focuses on what we might
+			// consider to be an "idiomatic" variable name.
+			return cty.DynamicVal, function.NewArgErrorf(1, "invalid template variable name %q: must start with a letter, followed by zero or more letters, digits, and underscores", n)
+		}
+	}
+
+	// We'll pre-check references in the template here so we can give a
+	// more specialized error message than HCL would by default, so it's
+	// clearer that this problem is coming from a template function call.
+	for _, traversal := range expr.Variables() {
+		root := traversal.RootName()
+		if _, ok := ctx.Variables[root]; !ok {
+			return cty.DynamicVal, function.NewArgErrorf(1, "vars map does not contain key %q, referenced at %s", root, traversal[0].SourceRange())
+		}
+	}
+
+	val, diags := expr.Value(ctx)
+	if diags.HasErrors() {
+		return cty.DynamicVal, diags
+	}
+	return val, nil
+}
+
+// templateFuncs returns a copy of the given function table for use inside
+// a rendered template. The template functions themselves are stubbed out
+// to prevent recursive calls, both with and without the "core::" namespace.
+func templateFuncs(givenFuncs map[string]function.Function) map[string]function.Function {
+	funcs := make(map[string]function.Function, len(givenFuncs))
+	for name, fn := range givenFuncs {
+		switch baseName := strings.TrimPrefix(name, "core::"); baseName {
+		case "templatefile", "templatestring":
+			funcs[name] = function.New(&function.Spec{
+				VarParam: &function.Parameter{
+					Name: "args",
+					Type: cty.DynamicPseudoType,
+				},
+				Type: func(args []cty.Value) (cty.Type, error) {
+					return cty.NilType, fmt.Errorf("cannot recursively call %s from inside another template function", baseName)
+				},
+			})
+		default:
+			funcs[name] = fn
+		}
+	}
+	return funcs
+}
@@ -177,3 +177,49 @@ func TestNonsensitive(t *testing.T) {
 		})
 	}
 }
+
+func TestIsSensitive(t *testing.T) {
+	tests := []struct {
+		Input cty.Value
+		Want  bool
+	}{
+		{
+			cty.NumberIntVal(1).Mark(marks.Sensitive),
+			true,
+		},
+		{
+			cty.NumberIntVal(1),
+			false,
+		},
+		{
+			cty.DynamicVal.Mark(marks.Sensitive),
+			true,
+		},
+		{
+			cty.DynamicVal,
+			false,
+		},
+		{
+			cty.NullVal(cty.String).Mark(marks.Sensitive),
+			true,
+		},
+		{
+			// Only the top-level marks are considered
+			cty.ListVal([]cty.Value{cty.NumberIntVal(1).Mark(marks.Sensitive)}),
+			false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("issensitive(%#v)", test.Input), func(t *testing.T) {
+			got, err := IsSensitive(test.Input)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if !got.RawEquals(cty.BoolVal(test.Want)) {
+				t.Errorf("wrong result\ngot:  %#v\nwant: %#v", got, cty.BoolVal(test.Want))
+			}
+		})
+	}
+}
@@ -7,7 +7,11 @@ import (
 	"regexp"
 	"strings"
 
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/ext/customdecode"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
 	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
 	"github.com/zclconf/go-cty/cty/function"
 )
 
@@ -132,3 +136,83 @@ var StrContainsFunc = function.New(&function.Spec{
 		return cty.False, nil
 	},
 })
+
+// MakeTemplateStringFunc constructs a function that takes a string and
+// an arbitrary object of named values and attempts to render that
+// string as a template using HCL template syntax.
+//
+// Unlike templatefile, the template is given as a string value that was
+// retrieved from elsewhere, such as a local value or a data source. Literal
+// templates are rejected because a template string expression should be
+// used for those instead.
+func MakeTemplateStringFunc(funcsCb func() map[string]function.Function) function.Function {
+	params := []function.Parameter{
+		{
+			Name: "template",
+			Type: customdecode.ExpressionClosureType,
+		},
+		{
+			Name: "vars",
+			Type: cty.DynamicPseudoType,
+		},
+	}
+
+	loadTmpl := func(closure *customdecode.ExpressionClosure) (hcl.Expression, cty.Value, error) {
+		switch closure.Expression.(type) {
+		case *hclsyntax.TemplateExpr, *hclsyntax.TemplateWrapExpr:
+			return nil, cty.NilVal, function.NewArgErrorf(0, "invalid template expression: templatestring is only for rendering templates retrieved dynamically from elsewhere, and so does not support providing a literal template; consider using a template string expression instead")
+		}
+
+		templateVal, diags := closure.Value()
+		if diags.HasErrors() {
+			return nil, cty.NilVal, function.NewArgError(0, diags)
+		}
+		templateVal, err := convert.Convert(templateVal, cty.String)
+		if err != nil {
+			return nil, cty.NilVal, function.NewArgErrorf(0, "invalid template value: %s", err)
+		}
+		if !templateVal.IsKnown() {
+			return nil, templateVal, nil
+		}
+		if templateVal.IsNull() {
+			return nil, cty.NilVal, function.NewArgErrorf(0, "template must not be null")
+		}
+
+		raw, _ := templateVal.Unmark()
+		expr, diags := hclsyntax.ParseTemplate([]byte(raw.AsString()), "<templatestring argument>", hcl.Pos{Line: 1, Column: 1})
+		if diags.HasErrors() {
+			return nil, cty.NilVal, function.NewArgError(0, diags)
+		}
+		return expr, templateVal, nil
+	}
+
+	return function.New(&function.Spec{
+		Params: params,
+		Type: func(args []cty.Value) (cty.Type, error) {
+			expr, templateVal, err := loadTmpl(customdecode.ExpressionClosureFromVal(args[0]))
+			if err != nil {
+				return cty.DynamicPseudoType, err
+			}
+			if !(templateVal.IsKnown() && args[1].IsKnown()) {
+				return cty.DynamicPseudoType, nil
+			}
+
+			// This is safe even if args[1] contains unknowns because the HCL
+			// template renderer itself knows how to short-circuit those.
+			val, err := renderTemplate(expr, args[1], templateFuncs(funcsCb()))
+			return val.Type(), err
+		},
+		Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+			expr, templateVal, err := loadTmpl(customdecode.ExpressionClosureFromVal(args[0]))
+			if err != nil {
+				return cty.DynamicVal, err
+			}
+			_, templateMarks := templateVal.Unmark()
+			if !templateVal.IsKnown() {
+				return cty.UnknownVal(retType).WithMarks(templateMarks), nil
+			}
+			result, err := renderTemplate(expr, args[1], templateFuncs(funcsCb()))
+			return result.WithMarks(templateMarks), err
+		},
+	})
+}
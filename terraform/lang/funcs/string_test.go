@@ -7,7 +7,12 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/ext/customdecode"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/terraform-linters/tflint-plugin-sdk/terraform/lang/marks"
 	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/function"
 )
 
 func TestReplace(t *testing.T) {
@@ -137,3 +142,106 @@ func TestStrContains(t *testing.T) {
 func StrContains(str, substr cty.Value) (cty.Value, error) {
 	return StrContainsFunc.Call([]cty.Value{str, substr})
 }
+
+func TestTemplateString(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Template string
+		Vars     cty.Value
+		Want     cty.Value
+		Err      string
+	}{
+		{
+			"simple",
+			`local.template`,
+			cty.ObjectVal(map[string]cty.Value{
+				"name": cty.StringVal("Jodie"),
+			}),
+			cty.StringVal("Hello, Jodie!"),
+			``,
+		},
+		{
+			"sensitive template",
+			`local.sensitive`,
+			cty.ObjectVal(map[string]cty.Value{
+				"name": cty.StringVal("Jodie"),
+			}),
+			cty.StringVal("Hello, Jodie!").Mark(marks.Sensitive),
+			``,
+		},
+		{
+			"unknown template",
+			`local.unknown`,
+			cty.EmptyObjectVal,
+			cty.DynamicVal,
+			``,
+		},
+		{
+			"missing vars",
+			`local.template`,
+			cty.EmptyObjectVal,
+			cty.NilVal,
+			`vars map does not contain key "name", referenced at <templatestring argument>:1,10-14`,
+		},
+		{
+			"literal template",
+			`"Hello, $${name}!"`,
+			cty.EmptyObjectVal,
+			cty.NilVal,
+			`invalid template expression: templatestring is only for rendering templates retrieved dynamically from elsewhere, and so does not support providing a literal template; consider using a template string expression instead`,
+		},
+		{
+			"recursive",
+			`local.recursive`,
+			cty.EmptyObjectVal,
+			cty.NilVal,
+			`<templatestring argument>:1,3-18: Error in function call; Call to function "templatestring" failed: cannot recursively call templatestring from inside another template function.`,
+		},
+	}
+
+	ctx := &hcl.EvalContext{
+		Variables: map[string]cty.Value{
+			"local": cty.ObjectVal(map[string]cty.Value{
+				"template":  cty.StringVal("Hello, ${name}!"),
+				"sensitive": cty.StringVal("Hello, ${name}!").Mark(marks.Sensitive),
+				"unknown":   cty.UnknownVal(cty.String),
+				"recursive": cty.StringVal("${templatestring(\"\", {})}"),
+			}),
+		},
+	}
+
+	var templateStringFunc function.Function
+	templateStringFunc = MakeTemplateStringFunc(func() map[string]function.Function {
+		return map[string]function.Function{
+			"templatestring": templateStringFunc,
+		}
+	})
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			expr, diags := hclsyntax.ParseExpression([]byte(test.Template), "", hcl.Pos{Line: 1, Column: 1})
+			if diags.HasErrors() {
+				t.Fatal(diags)
+			}
+			closure := customdecode.ExpressionClosureVal(&customdecode.ExpressionClosure{Expression: expr, EvalContext: ctx})
+
+			got, err := templateStringFunc.Call([]cty.Value{closure, test.Vars})
+
+			if test.Err != "" {
+				if err == nil {
+					t.Fatal("succeeded; want error")
+				}
+				if got, want := err.Error(), test.Err; got != want {
+					t.Fatalf("wrong error\ngot:  %s\nwant: %s", got, want)
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if !got.RawEquals(test.Want) {
+				t.Errorf("wrong result\ngot:  %#v\nwant: %#v", got, test.Want)
+			}
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package funcs
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/function"
+)
+
+// The functions in this file are provided by Terraform's built-in
+// "terraform" provider, and are called as "provider::terraform::<name>".
+
+// EncodeTfvarsFunc constructs a function that encodes an object or map
+// as a string in the same syntax as .tfvars files.
+var EncodeTfvarsFunc = function.New(&function.Spec{
+	Params: []function.Parameter{
+		{
+			Name:             "value",
+			Type:             cty.DynamicPseudoType,
+			AllowNull:        true,
+			AllowDynamicType: true,
+			AllowUnknown:     true,
+		},
+	},
+	Type: function.StaticReturnType(cty.String),
+	Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+		obj := args[0]
+		if obj.IsNull() {
+			return cty.NilVal, function.NewArgErrorf(0, "cannot encode a null value in tfvars syntax")
+		}
+		if !obj.IsWhollyKnown() {
+			return cty.UnknownVal(retType), nil
+		}
+
+		ty := obj.Type()
+		if !ty.IsObjectType() && !ty.IsMapType() {
+			return cty.NilVal, function.NewArgErrorf(0, "invalid value to encode: must be an object whose attribute names will become the encoded variable names")
+		}
+
+		names := make([]string, 0, obj.LengthInt())
+		attrs := obj.AsValueMap()
+		for name := range attrs {
+			if !hclsyntax.ValidIdentifier(name) {
+				return cty.NilVal, function.NewArgErrorf(0, "invalid variable name %q: must be a valid identifier, per Terraform's rules for input variable declarations", name)
+			}
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		f := hclwrite.NewEmptyFile()
+		body := f.Body()
+		for _, name := range names {
+			body.SetAttributeValue(name, attrs[name])
+		}
+		return cty.StringVal(string(f.Bytes())), nil
+	},
+})
+
+// DecodeTfvarsFunc constructs a function that parses a string in the
+// .tfvars syntax and returns an object with an attribute per variable.
+var DecodeTfvarsFunc = function.New(&function.Spec{
+	Params: []function.Parameter{
+		{
+			Name: "src",
+			Type: cty.String,
+		},
+	},
+	Type: function.StaticReturnType(cty.DynamicPseudoType),
+	Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+		src := []byte(args[0].AsString())
+		f, diags := hclsyntax.ParseConfig(src, "<decode_tfvars argument>", hcl.Pos{Line: 1, Column: 1})
+		if diags.HasErrors() {
+			return cty.NilVal, fmt.Errorf("invalid tfvars syntax: %s", diags.Error())
+		}
+		attrs, diags := f.Body.JustAttributes()
+		if diags.HasErrors() {
+			return cty.NilVal, fmt.Errorf("invalid tfvars content: %s", diags.Error())
+		}
+
+		ret := make(map[string]cty.Value, len(attrs))
+		for name, attr := range attrs {
+			// Evaluating the expression with no EvalContext achieves the same
+			// interpretation as Terraform CLI makes of .tfvars files, rejecting
+			// any function calls or references to symbols.
+			val, diags := attr.Expr.Value(nil)
+			if diags.HasErrors() {
+				return cty.NilVal, fmt.Errorf("invalid expression for variable %q: %s", name, diags.Error())
+			}
+			ret[name] = val
+		}
+		return cty.ObjectVal(ret), nil
+	},
+})
+
+// EncodeExprFunc constructs a function that encodes an arbitrary value
+// as a string in Terraform expression syntax.
+var EncodeExprFunc = function.New(&function.Spec{
+	Params: []function.Parameter{
+		{
+			Name:             "value",
+			Type:             cty.DynamicPseudoType,
+			AllowNull:        true,
+			AllowDynamicType: true,
+			AllowUnknown:     true,
+		},
+	},
+	Type: function.StaticReturnType(cty.String),
+	Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+		val := args[0]
+		if !val.IsWhollyKnown() {
+			return cty.UnknownVal(retType), nil
+		}
+
+		src := bytes.TrimSpace(hclwrite.TokensForValue(val).Bytes())
+		return cty.StringVal(string(src)), nil
+	},
+})
+
+func EncodeTfvars(v cty.Value) (cty.Value, error) {
+	return EncodeTfvarsFunc.Call([]cty.Value{v})
+}
+
+func DecodeTfvars(src cty.Value) (cty.Value, error) {
+	return DecodeTfvarsFunc.Call([]cty.Value{src})
+}
+
+func EncodeExpr(v cty.Value) (cty.Value, error) {
+	return EncodeExprFunc.Call([]cty.Value{v})
+}
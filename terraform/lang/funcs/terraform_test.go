@@ -0,0 +1,197 @@
+package funcs
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestEncodeTfvars(t *testing.T) {
+	tests := []struct {
+		Input cty.Value
+		Want  cty.Value
+		Err   string
+	}{
+		{
+			cty.ObjectVal(map[string]cty.Value{
+				"string": cty.StringVal("hello"),
+				"number": cty.NumberIntVal(5),
+				"list":   cty.ListVal([]cty.Value{cty.True, cty.False}),
+			}),
+			cty.StringVal("list   = [true, false]\nnumber = 5\nstring = \"hello\"\n"),
+			``,
+		},
+		{
+			cty.MapVal(map[string]cty.Value{
+				"foo": cty.StringVal("bar"),
+			}),
+			cty.StringVal("foo = \"bar\"\n"),
+			``,
+		},
+		{
+			cty.EmptyObjectVal,
+			cty.StringVal(""),
+			``,
+		},
+		{
+			cty.ObjectVal(map[string]cty.Value{
+				"foo": cty.UnknownVal(cty.String),
+			}),
+			cty.UnknownVal(cty.String),
+			``,
+		},
+		{
+			cty.NullVal(cty.EmptyObject),
+			cty.NilVal,
+			`cannot encode a null value in tfvars syntax`,
+		},
+		{
+			cty.StringVal("foo"),
+			cty.NilVal,
+			`invalid value to encode: must be an object whose attribute names will become the encoded variable names`,
+		},
+		{
+			cty.ObjectVal(map[string]cty.Value{
+				"not valid": cty.StringVal("foo"),
+			}),
+			cty.NilVal,
+			`invalid variable name "not valid": must be a valid identifier, per Terraform's rules for input variable declarations`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("encode_tfvars(%#v)", test.Input), func(t *testing.T) {
+			got, err := EncodeTfvars(test.Input)
+
+			if test.Err != "" {
+				if err == nil {
+					t.Fatal("succeeded; want error")
+				}
+				if got, want := err.Error(), test.Err; got != want {
+					t.Fatalf("wrong error\ngot:  %s\nwant: %s", got, want)
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if !got.RawEquals(test.Want) {
+				t.Errorf("wrong result\ngot:  %#v\nwant: %#v", got, test.Want)
+			}
+		})
+	}
+}
+
+func TestDecodeTfvars(t *testing.T) {
+	tests := []struct {
+		Input cty.Value
+		Want  cty.Value
+		Err   string
+	}{
+		{
+			cty.StringVal("string = \"hello\"\nnumber = 5\nlist = [true, false]\n"),
+			cty.ObjectVal(map[string]cty.Value{
+				"string": cty.StringVal("hello"),
+				"number": cty.NumberIntVal(5),
+				"list":   cty.TupleVal([]cty.Value{cty.True, cty.False}),
+			}),
+			``,
+		},
+		{
+			cty.StringVal(""),
+			cty.EmptyObjectVal,
+			``,
+		},
+		{
+			cty.StringVal("foo = var.bar\n"),
+			cty.NilVal,
+			`invalid expression for variable "foo": <decode_tfvars argument>:1,7-10: Variables not allowed; Variables may not be used here.`,
+		},
+		{
+			cty.StringVal("foo {}\n"),
+			cty.NilVal,
+			`invalid tfvars content: <decode_tfvars argument>:1,1-4: Unexpected "foo" block; Blocks are not allowed here.`,
+		},
+		{
+			cty.StringVal("foo = \n"),
+			cty.NilVal,
+			`invalid tfvars syntax: <decode_tfvars argument>:1,7-2,1: Invalid expression; Expected the start of an expression, but found an invalid expression token.`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("decode_tfvars(%#v)", test.Input), func(t *testing.T) {
+			got, err := DecodeTfvars(test.Input)
+
+			if test.Err != "" {
+				if err == nil {
+					t.Fatal("succeeded; want error")
+				}
+				if got, want := err.Error(), test.Err; got != want {
+					t.Fatalf("wrong error\ngot:  %s\nwant: %s", got, want)
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if !got.RawEquals(test.Want) {
+				t.Errorf("wrong result\ngot:  %#v\nwant: %#v", got, test.Want)
+			}
+		})
+	}
+}
+
+func TestEncodeExpr(t *testing.T) {
+	tests := []struct {
+		Input cty.Value
+		Want  cty.Value
+	}{
+		{
+			cty.StringVal("hello"),
+			cty.StringVal(`"hello"`),
+		},
+		{
+			cty.StringVal("hello\nworld\n"),
+			cty.StringVal(`"hello\nworld\n"`),
+		},
+		{
+			cty.StringVal("hel${lo"),
+			cty.StringVal(`"hel$${lo"`),
+		},
+		{
+			cty.NumberIntVal(5),
+			cty.StringVal(`5`),
+		},
+		{
+			cty.True,
+			cty.StringVal(`true`),
+		},
+		{
+			cty.NullVal(cty.String),
+			cty.StringVal(`null`),
+		},
+		{
+			cty.ListVal([]cty.Value{cty.StringVal("foo")}),
+			cty.StringVal(`["foo"]`),
+		},
+		{
+			cty.UnknownVal(cty.String),
+			cty.UnknownVal(cty.String),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("encode_expr(%#v)", test.Input), func(t *testing.T) {
+			got, err := EncodeExpr(test.Input)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if !got.RawEquals(test.Want) {
+				t.Errorf("wrong result\ngot:  %#v\nwant: %#v", got, test.Want)
+			}
+		})
+	}
+}
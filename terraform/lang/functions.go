@@ -4,7 +4,11 @@
 package lang
 
 import (
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/ext/tryfunc"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
 	ctyyaml "github.com/zclconf/go-cty-yaml"
 	"github.com/zclconf/go-cty/cty"
 	"github.com/zclconf/go-cty/cty/function"
@@ -13,6 +17,15 @@ import (
 	"github.com/terraform-linters/tflint/terraform/lang/funcs"
 )
 
+const (
+	// CoreNamespace is the namespace prefix of built-in functions.
+	// e.g. "core::abs" is the same as "abs".
+	CoreNamespace = "core::"
+	// ProviderNamespace is the namespace prefix of provider-defined functions.
+	// e.g. "provider::aws::arn_parse"
+	ProviderNamespace = "provider::"
+)
+
 var impureFunctions = []string{
 	"bcrypt",
 	"timestamp",
@@ -60,6 +73,7 @@ func (s *Scope) Functions() map[string]function.Function {
 			"distinct":         stdlib.DistinctFunc,
 			"element":          stdlib.ElementFunc,
 			"endswith":         funcs.EndsWithFunc,
+			"ephemeralasnull":  funcs.EphemeralAsNullFunc,
 			"chunklist":        stdlib.ChunklistFunc,
 			"file":             funcs.MakeFileFunc(s.BaseDir, false),
 			"fileexists":       funcs.MakeFileExistsFunc(s.BaseDir),
@@ -78,6 +92,7 @@ func (s *Scope) Functions() map[string]function.Function {
 			"formatlist":       stdlib.FormatListFunc,
 			"indent":           stdlib.IndentFunc,
 			"index":            funcs.IndexFunc, // stdlib.IndexFunc is not compatible
+			"issensitive":      funcs.IsSensitiveFunc,
 			"join":             stdlib.JoinFunc,
 			"jsondecode":       stdlib.JSONDecodeFunc,
 			"jsonencode":       stdlib.JSONEncodeFunc,
@@ -155,6 +170,9 @@ func (s *Scope) Functions() map[string]function.Function {
 			// by copying this map and overwriting the "templatefile" entry.
 			return s.funcs
 		})
+		s.funcs["templatestring"] = funcs.MakeTemplateStringFunc(func() map[string]function.Function {
+			return s.funcs
+		})
 
 		if s.PureOnly {
 			// Force our few impure functions to return unknown so that we
@@ -163,8 +181,116 @@ func (s *Scope) Functions() map[string]function.Function {
 				s.funcs[name] = function.Unpredictable(s.funcs[name])
 			}
 		}
+
+		// All of the built-in functions are also available with the "core::"
+		// namespace prefix, which can be used to disambiguate them.
+		coreNames := make([]string, 0, len(s.funcs))
+		for name := range s.funcs {
+			coreNames = append(coreNames, name)
+		}
+		for _, name := range coreNames {
+			s.funcs[CoreNamespace+name] = s.funcs[name]
+		}
+
+		// Functions of the built-in "terraform" provider are always available.
+		s.funcs[ProviderNamespace+"terraform::encode_tfvars"] = funcs.EncodeTfvarsFunc
+		s.funcs[ProviderNamespace+"terraform::decode_tfvars"] = funcs.DecodeTfvarsFunc
+		s.funcs[ProviderNamespace+"terraform::encode_expr"] = funcs.EncodeExprFunc
+
+		// Provider-defined functions are only known to providers, and TFLint does
+		// not run providers, so the functions called in the configuration return
+		// unknown values instead of "unknown function" errors.
+		for _, name := range s.FunctionCalls {
+			if _, exists := s.funcs[name]; !exists && strings.HasPrefix(name, ProviderNamespace) {
+				s.funcs[name] = unknownFunc
+			}
+		}
 	}
 	s.funcsLock.Unlock()
 
 	return s.funcs
 }
+
+// unknownFunc accepts any arguments and always returns an unknown value.
+var unknownFunc = function.New(&function.Spec{
+	VarParam: &function.Parameter{
+		Name:             "args",
+		Type:             cty.DynamicPseudoType,
+		AllowUnknown:     true,
+		AllowNull:        true,
+		AllowMarked:      true,
+		AllowDynamicType: true,
+	},
+	Type: function.StaticReturnType(cty.DynamicPseudoType),
+	Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+		return cty.DynamicVal, nil
+	},
+})
+
+// FunctionCallsInBody returns the names of all functions called in the given body.
+// Native syntax bodies are walked, and the string literals in other bodies (e.g. JSON)
+// are scanned as templates. The result is typically passed as Scope.FunctionCalls.
+func FunctionCallsInBody(body hcl.Body) []string {
+	if node, ok := body.(*hclsyntax.Body); ok {
+		return functionCallsInNode(node)
+	}
+
+	// JSON bodies return all properties as attributes, including nested blocks.
+	// Errors are ignored because only expressions are needed here.
+	attrs, _ := body.JustAttributes()
+	var calls []string
+	for _, attr := range attrs {
+		if node, ok := hcl.UnwrapExpression(attr.Expr).(hclsyntax.Node); ok {
+			calls = append(calls, functionCallsInNode(node)...)
+		} else {
+			calls = append(calls, functionCallsInLiteral(attr.Expr)...)
+		}
+	}
+	return calls
+}
+
+// functionCallsInLiteral returns function calls in the template strings of the
+// given expression. JSON expressions return their strings as-is without an
+// evaluation context, so they are parsed in the same way as the JSON syntax.
+func functionCallsInLiteral(expr hcl.Expression) []string {
+	val, diags := expr.Value(nil)
+	if diags.HasErrors() {
+		return nil
+	}
+
+	var calls []string
+	scan := func(v cty.Value) {
+		v, _ = v.Unmark()
+		if v.IsNull() || !v.IsKnown() || !v.Type().Equals(cty.String) {
+			return
+		}
+		tmpl, diags := hclsyntax.ParseTemplate([]byte(v.AsString()), expr.Range().Filename, expr.Range().Start)
+		if diags.HasErrors() {
+			return
+		}
+		calls = append(calls, functionCallsInNode(tmpl)...)
+	}
+	// The callback never returns errors
+	_ = cty.Walk(val, func(path cty.Path, v cty.Value) (bool, error) {
+		// Object keys are also templates in JSON
+		if len(path) > 0 {
+			if step, ok := path[len(path)-1].(cty.GetAttrStep); ok {
+				scan(cty.StringVal(step.Name))
+			}
+		}
+		scan(v)
+		return true, nil
+	})
+	return calls
+}
+
+func functionCallsInNode(node hclsyntax.Node) []string {
+	var calls []string
+	hclsyntax.VisitAll(node, func(n hclsyntax.Node) hcl.Diagnostics {
+		if call, ok := n.(*hclsyntax.FunctionCallExpr); ok {
+			calls = append(calls, call.Name)
+		}
+		return nil
+	})
+	return calls
+}
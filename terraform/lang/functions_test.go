@@ -7,10 +7,14 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/hclsyntax"
+	hcljson "github.com/hashicorp/hcl/v2/json"
 	homedir "github.com/mitchellh/go-homedir"
 	"github.com/terraform-linters/tflint-plugin-sdk/terraform/lang/marks"
 	"github.com/zclconf/go-cty/cty"
@@ -327,6 +331,18 @@ func TestFunctions(t *testing.T) {
 			},
 		},
 
+		"ephemeralasnull": {
+			{
+				// Ephemeral values cannot be generated in this test setup,
+				// so this only verifies that non-ephemeral values are
+				// passed through unchanged.
+				`ephemeralasnull({a = "b"})`,
+				cty.ObjectVal(map[string]cty.Value{
+					"a": cty.StringVal("b"),
+				}),
+			},
+		},
+
 		"file": {
 			{
 				`file("hello.txt")`,
@@ -480,6 +496,17 @@ func TestFunctions(t *testing.T) {
 			},
 		},
 
+		"issensitive": {
+			{
+				`issensitive(1)`,
+				cty.False,
+			},
+			{
+				`issensitive(sensitive(1))`,
+				cty.True,
+			},
+		},
+
 		"join": {
 			{
 				`join(" ", ["Hello", "World"])`,
@@ -900,6 +927,13 @@ func TestFunctions(t *testing.T) {
 			},
 		},
 
+		"templatestring": {
+			{
+				`templatestring(tostring("Hello, $${name}!"), {name = "Jodie"})`,
+				cty.StringVal("Hello, Jodie!"),
+			},
+		},
+
 		"timeadd": {
 			{
 				`timeadd("2017-11-22T00:00:00Z", "1s")`,
@@ -1118,6 +1152,32 @@ func TestFunctions(t *testing.T) {
 				}),
 			},
 		},
+
+		"provider::terraform::decode_tfvars": {
+			{
+				`provider::terraform::decode_tfvars("foo = \"bar\"\nbaz = 1\n")`,
+				cty.ObjectVal(map[string]cty.Value{
+					"foo": cty.StringVal("bar"),
+					"baz": cty.NumberIntVal(1),
+				}),
+			},
+		},
+
+		"provider::terraform::encode_expr": {
+			{
+				`provider::terraform::encode_expr({foo = ["bar"]})`,
+				cty.StringVal(`{
+  foo = ["bar"]
+}`),
+			},
+		},
+
+		"provider::terraform::encode_tfvars": {
+			{
+				`provider::terraform::encode_tfvars({foo = "bar", baz = 1})`,
+				cty.StringVal("baz = 1\nfoo = \"bar\"\n"),
+			},
+		},
 	}
 
 	t.Run("all functions are tested", func(t *testing.T) {
@@ -1136,8 +1196,12 @@ func TestFunctions(t *testing.T) {
 		// suitable type.
 		for _, impureFunc := range impureFunctions {
 			delete(allFunctions, impureFunc)
+			delete(allFunctions, CoreNamespace+impureFunc)
 		}
 		for f := range scope.Functions() {
+			// Functions in the "core::" namespace are aliases of the
+			// built-in functions, so their tests are shared.
+			f = strings.TrimPrefix(f, CoreNamespace)
 			if _, ok := tests[f]; !ok {
 				t.Errorf("Missing test for function %s\n", f)
 			}
@@ -1179,6 +1243,198 @@ func TestFunctions(t *testing.T) {
 	}
 }
 
+func TestFunctions_namespaces(t *testing.T) {
+	tests := []struct {
+		src  string
+		want cty.Value
+	}{
+		{
+			`core::abs(-1)`,
+			cty.NumberIntVal(1),
+		},
+		{
+			`core::upper(core::lower("Hello"))`,
+			cty.StringVal("HELLO"),
+		},
+		{
+			// Provider-defined functions cannot be called, so the result is unknown.
+			`provider::aws::arn_parse("arn:aws:iam::123456789012:user/foo")`,
+			cty.DynamicVal,
+		},
+		{
+			`upper(provider::aws::arn_parse("arn:aws:iam::123456789012:user/foo").account_id)`,
+			cty.UnknownVal(cty.String).RefineNotNull(),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.src, func(t *testing.T) {
+			scope := &Scope{Data: &dataForTests{}, FunctionCalls: []string{"provider::aws::arn_parse"}}
+
+			expr, parseDiags := hclsyntax.ParseExpression([]byte(test.src), "test.hcl", hcl.Pos{Line: 1, Column: 1})
+			if parseDiags.HasErrors() {
+				t.Fatal(parseDiags)
+			}
+
+			got, diags := scope.EvalExpr(expr, cty.DynamicPseudoType)
+			if diags.HasErrors() {
+				t.Fatal(diags)
+			}
+
+			if !test.want.RawEquals(got) {
+				t.Errorf("wrong result\nexpr: %s\ngot:  %#v\nwant: %#v", test.src, got, test.want)
+			}
+		})
+	}
+}
+
+func TestFunctions_namespacesJSON(t *testing.T) {
+	tests := []struct {
+		src  string
+		want cty.Value
+	}{
+		{
+			`"${provider::aws::arn_parse(\"arn:aws:iam::123456789012:user/foo\")}"`,
+			cty.DynamicVal,
+		},
+		{
+			`"${upper(provider::aws::arn_parse(\"arn:aws:iam::123456789012:user/foo\").account_id)}"`,
+			cty.UnknownVal(cty.String).RefineNotNull(),
+		},
+		{
+			`{"${provider::aws::arn_parse(\"arn:aws:iam::123456789012:user/foo\").account_id}": "foo"}`,
+			cty.DynamicVal,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.src, func(t *testing.T) {
+			scope := &Scope{Data: &dataForTests{}, FunctionCalls: []string{"provider::aws::arn_parse"}}
+
+			expr, parseDiags := hcljson.ParseExpression([]byte(test.src), "test.tf.json")
+			if parseDiags.HasErrors() {
+				t.Fatal(parseDiags)
+			}
+
+			got, diags := scope.EvalExpr(expr, cty.DynamicPseudoType)
+			if diags.HasErrors() {
+				t.Fatal(diags)
+			}
+
+			if !test.want.RawEquals(got) {
+				t.Errorf("wrong result\nexpr: %s\ngot:  %#v\nwant: %#v", test.src, got, test.want)
+			}
+		})
+	}
+}
+
+// opaqueExpr hides the native syntax of the wrapped expression,
+// so function calls in it cannot be found from the expression.
+type opaqueExpr struct {
+	hcl.Expression
+}
+
+func TestFunctions_providerFunctionCalls(t *testing.T) {
+	src := `upper(provider::aws::arn_parse("arn:aws:iam::123456789012:user/foo").account_id)`
+
+	tests := []struct {
+		name  string
+		calls []string
+		want  cty.Value
+		err   string
+	}{
+		{
+			name:  "called",
+			calls: []string{"upper", "provider::aws::arn_parse"},
+			want:  cty.UnknownVal(cty.String).RefineNotNull(),
+		},
+		{
+			name:  "not called",
+			calls: []string{"upper"},
+			want:  cty.DynamicVal,
+			err:   `test.hcl:1,7-31: Call to unknown function; There are no functions in namespace "provider::aws::".`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			scope := &Scope{Data: &dataForTests{}, FunctionCalls: test.calls}
+
+			expr, parseDiags := hclsyntax.ParseExpression([]byte(src), "test.hcl", hcl.Pos{Line: 1, Column: 1})
+			if parseDiags.HasErrors() {
+				t.Fatal(parseDiags)
+			}
+
+			// Function calls are not collected from the evaluated expression
+			got, diags := scope.EvalExpr(opaqueExpr{expr}, cty.DynamicPseudoType)
+			if diags.HasErrors() {
+				if diags.Error() != test.err {
+					t.Fatalf("want=%s, got=%s", test.err, diags.Error())
+				}
+			} else if test.err != "" {
+				t.Fatalf("want=%s, got no error", test.err)
+			}
+
+			if !test.want.RawEquals(got) {
+				t.Errorf("wrong result\ngot:  %#v\nwant: %#v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestFunctionCallsInBody(t *testing.T) {
+	native, diags := hclsyntax.ParseConfig([]byte(`
+foo = provider::aws::arn_parse("arn")
+`), "main.tf", hcl.InitialPos)
+	if diags.HasErrors() {
+		t.Fatal(diags)
+	}
+	json, diags := hcljson.Parse([]byte(`{
+  "resource": {
+    "null_resource": {
+      "main": {
+        "triggers": { "bar": "${provider::google::region_from_id(\"id\")}" }
+      }
+    }
+  }
+}`), "main.tf.json")
+	if diags.HasErrors() {
+		t.Fatal(diags)
+	}
+
+	tests := []struct {
+		name string
+		body hcl.Body
+		want []string
+	}{
+		{
+			name: "native",
+			body: native.Body,
+			want: []string{"provider::aws::arn_parse"},
+		},
+		{
+			name: "json",
+			body: json.Body,
+			want: []string{"provider::google::region_from_id"},
+		},
+		{
+			name: "merged",
+			body: hcl.MergeBodies([]hcl.Body{native.Body, json.Body}),
+			want: []string{"provider::aws::arn_parse", "provider::google::region_from_id"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := FunctionCallsInBody(test.body)
+			sort.Strings(got)
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
 const (
 	CipherBase64 = "eczGaDhXDbOFRZGhjx2etVzWbRqWDlmq0bvNt284JHVbwCgObiuyX9uV0LSAMY707IEgMkExJqXmsB4OWKxvB7epRB9G/3+F+pcrQpODlDuL9oDUAsa65zEpYF0Wbn7Oh7nrMQncyUPpyr9WUlALl0gRWytOA23S+y5joa4M34KFpawFgoqTu/2EEH4Xl1zo+0fy73fEto+nfkUY+meuyGZ1nUx/+DljP7ZqxHBFSlLODmtuTMdswUbHbXbWneW51D7Jm7xB8nSdiA2JQNK5+Sg5x8aNfgvFTt/m2w2+qpsyFa5Wjeu6fZmXSl840CA07aXbk9vN4I81WmJyblD/ZA=="
 	PrivateKey   = `
@@ -0,0 +1,37 @@
+// Package marks contains cty value marks that TFLint uses in addition to
+// the ones provided by the plugin SDK.
+//
+// The SDK's marks package only knows about sensitive values, so marks that
+// were introduced in later versions of Terraform are defined here.
+package marks
+
+import (
+	"github.com/zclconf/go-cty/cty"
+)
+
+// valueMarks allow creating strictly typed values for use as cty.Value marks.
+// Each distinct mark value must be a constant in this package whose value
+// is a valueMark whose underlying string matches the name of the variable.
+type valueMark string
+
+func (m valueMark) GoString() string {
+	return "marks." + string(m)
+}
+
+// Contains returns true if the cty.Value or any any value within it contains
+// the given mark.
+func Contains(val cty.Value, mark valueMark) bool {
+	ret := false
+	cty.Walk(val, func(_ cty.Path, v cty.Value) (bool, error) {
+		if v.HasMark(mark) {
+			ret = true
+			return false, nil
+		}
+		return true, nil
+	})
+	return ret
+}
+
+// Ephemeral indicates that this value is ephemeral in the context of
+// Terraform, i.e. it is never persisted to a plan or state.
+const Ephemeral = valueMark("Ephemeral")
@@ -29,6 +29,15 @@ type Scope struct {
 	// then differ during apply.
 	PureOnly bool
 
+	// ExpandProviders can be set to true to expand provider blocks with
+	// for_each in ExpandBlock. This is only supported in OpenTofu.
+	ExpandProviders bool
+
+	// FunctionCalls is the names of functions called in the configuration,
+	// typically collected by FunctionCallsInBody. Provider-defined functions
+	// are only available if they are in this list.
+	FunctionCalls []string
+
 	funcs     map[string]function.Function
 	funcsLock sync.Mutex
 }
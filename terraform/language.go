@@ -0,0 +1,54 @@
+package terraform
+
+import "fmt"
+
+// Language is a configuration language dialect to be interpreted.
+// Terraform and OpenTofu share most of the language, but there are
+// differences in file extensions and some language features.
+type Language int32
+
+const (
+	// LanguageAuto detects the language from the files in each module directory.
+	LanguageAuto Language = iota
+
+	// LanguageTerraform interprets the configuration as Terraform.
+	LanguageTerraform
+
+	// LanguageOpenTofu interprets the configuration as OpenTofu.
+	LanguageOpenTofu
+)
+
+func AsLanguage(s string) (Language, error) {
+	switch s {
+	case "auto":
+		return LanguageAuto, nil
+	case "terraform":
+		return LanguageTerraform, nil
+	case "opentofu":
+		return LanguageOpenTofu, nil
+	default:
+		return LanguageAuto, fmt.Errorf("%s is invalid language. Allowed values are: auto, terraform, opentofu", s)
+	}
+}
+
+func (l Language) String() string {
+	switch l {
+	case LanguageAuto:
+		return "auto"
+	case LanguageTerraform:
+		return "terraform"
+	case LanguageOpenTofu:
+		return "opentofu"
+	default:
+		panic("never happened")
+	}
+}
+
+// initCommand returns the CLI command for installing modules.
+// This is used to suggest a command in error messages.
+func (l Language) initCommand() string {
+	if l == LanguageOpenTofu {
+		return "tofu init"
+	}
+	return "terraform init"
+}
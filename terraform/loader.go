@@ -19,8 +19,17 @@ import (
 // loading full configurations using modules and gathering input values from
 // values files.
 type Loader struct {
-	parser  *Parser
-	modules moduleMgr
+	parser *Parser
+	// varFileParser reads values files passed explicitly. This is usually the same as parser.
+	varFileParser *Parser
+	modules       moduleMgr
+	mirror        *moduleMirror
+
+	staleManifest StaleModuleManifest
+
+	// varfiles and variables are values passed explicitly. See SetInputVariables.
+	varfiles  []string
+	variables []string
 
 	baseDir string
 }
@@ -47,8 +56,10 @@ func NewLoader(fs afero.Afero, originalWd string) (*Loader, error) {
 		return nil, fmt.Errorf("failed to determine base dir: %s", err)
 	}
 
+	parser := NewParser(fs)
 	ret := &Loader{
-		parser: NewParser(fs),
+		parser:        parser,
+		varFileParser: parser,
 		modules: moduleMgr{
 			fs:       fs,
 			manifest: moduleManifest{},
@@ -64,14 +75,70 @@ func NewLoader(fs afero.Afero, originalWd string) (*Loader, error) {
 	return ret, nil
 }
 
+// SetLanguage sets the language used to interpret configurations.
+// By default, the language is detected automatically.
+func (l *Loader) SetLanguage(lang Language) {
+	l.parser.SetLanguage(lang)
+}
+
+// SetModuleMirror sets the directory of a local module mirror.
+// Remote modules not found in the module manifest are resolved from the mirror.
+// See moduleMirror for the layout of the mirror.
+func (l *Loader) SetModuleMirror(dir string) {
+	if dir == "" {
+		l.mirror = nil
+		return
+	}
+	l.mirror = &moduleMirror{fs: l.modules.fs, dir: dir}
+}
+
+// SetVarFileFs sets the filesystem to read values files passed explicitly to LoadValuesFiles.
+// By default, they are read from the same filesystem as the configuration. This is useful
+// when the configuration is read from an archive, but values files are on the local disk.
+func (l *Loader) SetVarFileFs(fs afero.Fs) {
+	l.varFileParser = NewParser(fs)
+}
+
+// SetInputVariables sets values files and variables passed explicitly (e.g. --var-file and --var).
+// In OpenTofu, module sources and versions can refer to input variables, so LoadConfig reads them
+// with autoloaded values files and TF_VAR_* environment variables as the Evaluator does.
+func (l *Loader) SetInputVariables(varfiles []string, variables []string) {
+	l.varfiles = varfiles
+	l.variables = variables
+}
+
+// Language returns the language used to interpret the given directory.
+func (l *Loader) Language(dir string) Language {
+	return l.parser.Language(dir)
+}
+
+// SetStaleModuleManifest sets how to handle module manifests that do not match the configuration.
+// By default, mismatches are reported as warnings.
+func (l *Loader) SetStaleModuleManifest(mode StaleModuleManifest) {
+	l.staleManifest = mode
+}
+
 // LoadConfig reads the Terraform module in the given directory and uses it as the
 // root module to build the static module tree that represents a configuration.
+//
+// If the module manifest does not match the configuration, warnings may be
+// returned with the config. See SetStaleModuleManifest.
 func (l *Loader) LoadConfig(dir string, callModuleType CallModuleType) (*Config, hcl.Diagnostics) {
 	mod, diags := l.parser.LoadConfigDir(l.baseDir, dir)
 	if diags.HasErrors() {
 		return nil, diags
 	}
 
+	// Test files are only loaded for the root module.
+	// Invalid test files should not prevent the inspection of the module,
+	// so errors in test files are reported as warnings.
+	testFiles, testDiags := l.parser.LoadTestFiles(l.baseDir, dir)
+	for path, file := range testFiles {
+		mod.TestSources[path] = file.Bytes
+		mod.TestFiles[path] = file
+	}
+	testDiags = testDiags.Extend(mod.buildTests())
+
 	var walker ModuleWalkerFunc
 	switch callModuleType {
 	case CallAllModule:
@@ -86,14 +153,67 @@ func (l *Loader) LoadConfig(dir string, callModuleType CallModuleType) (*Config,
 		panic(fmt.Sprintf("unexpected module call type: %d", callModuleType))
 	}
 
-	cfg, diags := BuildConfig(mod, walker)
+	var values []InputValues
+	if mod.language == LanguageOpenTofu {
+		values, diags = l.inputValues(dir, mod)
+		if diags.HasErrors() {
+			return nil, diags
+		}
+	}
+
+	cfg, diags := BuildConfig(mod, walker, values...)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	diags = diags.Extend(l.modules.manifest.checkOrphanedRecords(cfg, l.staleManifest))
 	if diags.HasErrors() {
 		return nil, diags
 	}
-	return cfg, nil
+	diags = diags.Extend(asWarnings(testDiags))
+	// Warnings are returned with the config
+	return cfg, diags
+}
+
+// inputValues returns the input values of the given root module in order of priority.
+// These are the same values as those passed to the Evaluator, except for defaults.
+func (l *Loader) inputValues(dir string, mod *Module) ([]InputValues, hcl.Diagnostics) {
+	envVars, diags := EnvironmentVariableValues(mod.Variables)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+	values, diags := l.LoadValuesFiles(dir, l.varfiles...)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+	cliVars, diags := ParseVariableValues(l.variables, mod.Variables)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	return append(append([]InputValues{envVars}, values...), cliVars), nil
 }
 
 func (l *Loader) moduleWalkerFunc(walkLocal, walkRemote bool) ModuleWalkerFunc {
+	// Modules in the same directory can be called many times (e.g. a local module
+	// called from multiple module blocks), so parsed modules are shared between calls.
+	// Modules are never modified after loading, except for the root module.
+	type loadedModule struct {
+		mod   *Module
+		diags hcl.Diagnostics
+	}
+	loaded := map[string]loadedModule{}
+	loadConfigDir := func(dir string) (*Module, hcl.Diagnostics) {
+		key := filepath.Clean(dir)
+		if ret, exists := loaded[key]; exists {
+			log.Printf("[DEBUG] Reuse the loaded module: dir=%s", dir)
+			return ret.mod, ret.diags
+		}
+		mod, diags := l.parser.LoadConfigDir(l.baseDir, dir)
+		loaded[key] = loadedModule{mod: mod, diags: diags}
+		return mod, diags
+	}
+
 	return func(req *ModuleRequest) (*Module, *version.Version, hcl.Diagnostics) {
 		switch source := req.SourceAddr.(type) {
 		case nil:
@@ -116,7 +236,7 @@ func (l *Loader) moduleWalkerFunc(walkLocal, walkRemote bool) ModuleWalkerFunc {
 					},
 				}
 			}
-			mod, diags := l.parser.LoadConfigDir(l.baseDir, dir)
+			mod, diags := loadConfigDir(dir)
 			return mod, nil, diags
 
 		case addrs.ModuleSourceRemote:
@@ -129,19 +249,40 @@ func (l *Loader) moduleWalkerFunc(walkLocal, walkRemote bool) ModuleWalkerFunc {
 			// so that we can prompt the user to run "terraform init" if not.
 			key := l.modules.manifest.moduleKey(req.Path)
 			record, exists := l.modules.manifest[key]
+			if !exists && l.mirror != nil {
+				log.Printf(`[DEBUG] Failed to find "%s" in the manifest. Trying to resolve it from the module mirror`, key)
+				dir, ver, err := l.mirror.resolve(source.String(), req.VersionConstraint, req.Parent.Root.Module.language)
+				if err != nil {
+					return nil, nil, hcl.Diagnostics{
+						{
+							Severity: hcl.DiagError,
+							Summary:  fmt.Sprintf(`"%s" module is not found in the module mirror`, req.Name),
+							Detail:   fmt.Sprintf(`Failed to resolve "%s"; %s`, source, err),
+							Subject:  &req.CallRange,
+						},
+					}
+				}
+				log.Printf("[DEBUG] Trying to load the remote module from the mirror: key=%s, version=%s, dir=%s", key, ver, dir)
+				mod, diags := loadConfigDir(dir)
+				return mod, ver, diags
+			}
 			if !exists {
 				log.Printf(`[DEBUG] Failed to find "%s"`, key)
 				return nil, nil, hcl.Diagnostics{
 					{
 						Severity: hcl.DiagError,
-						Summary:  fmt.Sprintf(`"%s" module is not found. Did you run "terraform init"?`, req.Name),
+						Summary:  fmt.Sprintf(`"%s" module is not found. Did you run "%s"?`, req.Name, req.Parent.Root.Module.language.initCommand()),
 						Subject:  &req.CallRange,
 					},
 				}
 			}
+			diags := l.modules.manifest.checkRecord(req, record, l.staleManifest)
+			if diags.HasErrors() {
+				return nil, nil, diags
+			}
 			log.Printf("[DEBUG] Trying to load the remote module: key=%s, version=%s, dir=%s", key, record.VersionStr, record.Dir)
-			mod, diags := l.parser.LoadConfigDir(l.baseDir, record.Dir)
-			return mod, record.Version, diags
+			mod, loadDiags := loadConfigDir(record.Dir)
+			return mod, record.Version, diags.Extend(loadDiags)
 
 		default:
 			panic(fmt.Sprintf("unexpected module source type: %T", req.SourceAddr))
@@ -166,19 +307,19 @@ func (l *Loader) LoadValuesFiles(dir string, files ...string) ([]InputValues, hc
 		return nil, diags
 	}
 	defaultVarsFile := filepath.Join(dir, defaultVarsFilename)
-	if _, err := os.Stat(defaultVarsFile); err == nil {
+	if _, err := l.parser.fs.Stat(defaultVarsFile); err == nil {
 		autoLoadFiles = append([]string{defaultVarsFile}, autoLoadFiles...)
 	}
 
 	for _, file := range autoLoadFiles {
-		vals, loadDiags := l.loadValuesFile(file)
+		vals, loadDiags := l.loadValuesFile(l.parser, file)
 		diags = diags.Extend(loadDiags)
 		if !loadDiags.HasErrors() {
 			values = append(values, vals)
 		}
 	}
 	for _, file := range files {
-		vals, loadDiags := l.loadValuesFile(file)
+		vals, loadDiags := l.loadValuesFile(l.varFileParser, file)
 		diags = diags.Extend(loadDiags)
 		if !loadDiags.HasErrors() {
 			values = append(values, vals)
@@ -188,8 +329,8 @@ func (l *Loader) LoadValuesFiles(dir string, files ...string) ([]InputValues, hc
 	return values, diags
 }
 
-func (l *Loader) loadValuesFile(file string) (InputValues, hcl.Diagnostics) {
-	vals, diags := l.parser.LoadValuesFile(l.baseDir, file)
+func (l *Loader) loadValuesFile(parser *Parser, file string) (InputValues, hcl.Diagnostics) {
+	vals, diags := parser.LoadValuesFile(l.baseDir, file)
 	if diags.HasErrors() {
 		return nil, diags
 	}
@@ -212,7 +353,13 @@ func (l *Loader) IsConfigDir(path string) bool {
 }
 
 func (l *Loader) Sources() map[string][]byte {
-	return l.parser.Sources()
+	ret := l.parser.Sources()
+	if l.varFileParser != l.parser {
+		for path, source := range l.varFileParser.Sources() {
+			ret[path] = source
+		}
+	}
+	return ret
 }
 
 func (l *Loader) Files() map[string]*hcl.File {
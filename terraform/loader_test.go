@@ -9,6 +9,7 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/hashicorp/hcl/v2"
 	"github.com/spf13/afero"
 	"github.com/zclconf/go-cty/cty"
 )
@@ -166,6 +167,70 @@ func TestLoadConfig_callLocalModules(t *testing.T) {
 	})
 }
 
+func TestLoadConfig_sharedModules(t *testing.T) {
+	withinFixtureDir(t, "shared_modules", func(dir string) {
+		loader, err := NewLoader(afero.Afero{Fs: afero.NewOsFs()}, dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		config, diags := loader.LoadConfig(".", CallLocalModule)
+		if diags.HasErrors() {
+			t.Fatal(diags)
+		}
+
+		if len(config.Children) != 2 {
+			t.Fatalf("Root module must have 2 children, but got %d", len(config.Children))
+		}
+		// The same module directory is loaded only once
+		if config.Children["foo"].Module != config.Children["bar"].Module {
+			t.Fatal("module.foo and module.bar must share the loaded module")
+		}
+		if config.Children["foo"].Path.String() != "module.foo" {
+			t.Fatalf("module.foo path: want=module.foo, got=%s", config.Children["foo"].Path)
+		}
+	})
+}
+
+func TestLoadConfig_earlyEvaluation(t *testing.T) {
+	tests := []struct {
+		name      string
+		variables []string
+		want      string
+	}{
+		{
+			name: "values files",
+			want: "modules/staging",
+		},
+		{
+			name:      "variables",
+			variables: []string{"env=prod"},
+			want:      "modules/prod",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			withinFixtureDir(t, "early_evaluation", func(dir string) {
+				loader, err := NewLoader(afero.Afero{Fs: afero.NewOsFs()}, dir)
+				if err != nil {
+					t.Fatal(err)
+				}
+				loader.SetInputVariables(nil, test.variables)
+				config, diags := loader.LoadConfig(".", CallLocalModule)
+				if diags.HasErrors() {
+					t.Fatal(diags)
+				}
+
+				testChildModule(t, config, "foo", test.want)
+				testChildModule(t, config, "bar", test.want)
+				// Arguments of module calls are passed to child modules
+				testChildModule(t, config.Children["foo"], "child", "modules/foo")
+				testChildModule(t, config.Children["bar"], "child", "modules/bar")
+			})
+		})
+	}
+}
+
 func TestLoadConfig_withoutModuleManifest(t *testing.T) {
 	withinFixtureDir(t, "without_module_manifest", func(dir string) {
 		loader, err := NewLoader(afero.Afero{Fs: afero.NewOsFs()}, dir)
@@ -208,6 +273,130 @@ func TestLoadConfig_withoutModuleManifest_callLocalModules(t *testing.T) {
 	})
 }
 
+func TestLoadConfig_moduleMirror(t *testing.T) {
+	withinFixtureDir(t, "module_mirror", func(dir string) {
+		loader, err := NewLoader(afero.Afero{Fs: afero.NewOsFs()}, dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		loader.SetModuleMirror("mirror")
+		config, diags := loader.LoadConfig(".", CallAllModule)
+		if diags.HasErrors() {
+			t.Fatal(diags)
+		}
+
+		// module.consul
+		testChildModule(t, config, "consul", "mirror/registry.terraform.io/hashicorp/consul/aws/0.9.1")
+		// module.consul.module.consul_servers
+		testChildModule(
+			t,
+			config.Children["consul"],
+			"consul_servers",
+			"mirror/registry.terraform.io/hashicorp/consul/aws/0.9.1/modules/consul-cluster",
+		)
+		// module.example
+		testChildModule(t, config, "example", "mirror/github.com/terraform-linters/example/v1.0.0/modules/foo")
+
+		resolvedSources := map[string]string{
+			"root":                                "",
+			"module.consul":                       "registry.terraform.io/hashicorp/consul/aws",
+			"module.consul.module.consul_servers": "mirror/registry.terraform.io/hashicorp/consul/aws/0.9.1/modules/consul-cluster",
+			"module.example":                      "github.com/terraform-linters/example/v1.0.0//modules/foo",
+		}
+		configs := map[string]*Config{
+			"root":                                config,
+			"module.consul":                       config.Children["consul"],
+			"module.consul.module.consul_servers": config.Children["consul"].Children["consul_servers"],
+			"module.example":                      config.Children["example"],
+		}
+		for name, want := range resolvedSources {
+			if got := configs[name].ResolvedSource(); got != want {
+				t.Errorf("%s: resolved source: want=%s, got=%s", name, want, got)
+			}
+		}
+	})
+}
+
+func TestLoadConfig_moduleMirror_notFound(t *testing.T) {
+	withinFixtureDir(t, "module_mirror", func(dir string) {
+		loader, err := NewLoader(afero.Afero{Fs: afero.NewOsFs()}, dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		loader.SetModuleMirror("not_found")
+		_, diags := loader.LoadConfig(".", CallAllModule)
+		if !diags.HasErrors() {
+			t.Fatal("Expected error is not occurred")
+		}
+
+		expected := `module.tf:1,1-16: "consul" module is not found in the module mirror; Failed to resolve "hashicorp/consul/aws"; the directory "not_found/registry.terraform.io/hashicorp/consul/aws" does not exist in the module mirror`
+		if diags[0].Error() != filepath.FromSlash(expected) {
+			t.Fatalf(`Expected error is "%s", but got "%s"`, expected, diags[0])
+		}
+	})
+}
+
+func TestLoadConfig_staleModuleManifest(t *testing.T) {
+	versionChanged := `module.tf:1,1-16: Module version requirements have changed; The version requirements of module "consul" were changed to "~> 0.10" since the module was installed, and the installed version (0.9.0) is no longer acceptable. Run "terraform init" to install a matching version.`
+	sourceChanged := `module.tf:6,1-17: Module source has changed; The source address of module "example" was changed from "git::https://github.com/terraform-linters/example.git" to "github.com/terraform-linters/example-v2" since the module was installed. Run "terraform init" to install the new module.`
+	notDeclared := fmt.Sprintf(`%s:0,0-0: Module is not declared; The module manifest has the module "removed", but there is no corresponding module call in the configuration. Run "terraform init" to update the manifest.`, filepath.Join(".terraform", "modules", "modules.json"))
+
+	tests := []struct {
+		name     string
+		mode     StaleModuleManifest
+		errors   bool
+		expected []string
+	}{
+		{
+			name:     "warning",
+			mode:     StaleModuleManifestWarning,
+			expected: []string{versionChanged, sourceChanged, notDeclared},
+		},
+		{
+			name:     "error",
+			mode:     StaleModuleManifestError,
+			errors:   true,
+			expected: []string{versionChanged, sourceChanged},
+		},
+		{
+			name:     "ignore",
+			mode:     StaleModuleManifestIgnore,
+			expected: []string{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			withinFixtureDir(t, "stale_module_manifest", func(dir string) {
+				loader, err := NewLoader(afero.Afero{Fs: afero.NewOsFs()}, dir)
+				if err != nil {
+					t.Fatal(err)
+				}
+				loader.SetStaleModuleManifest(test.mode)
+				config, diags := loader.LoadConfig(".", CallAllModule)
+
+				if diags.HasErrors() != test.errors {
+					t.Fatalf("expected errors=%t, but got %s", test.errors, diags)
+				}
+				got := make([]string, len(diags))
+				for i, diag := range diags {
+					got[i] = diag.Error()
+				}
+				if diff := cmp.Diff(test.expected, got); diff != "" {
+					t.Fatal(diff)
+				}
+
+				if !test.errors {
+					// Installed modules are loaded even if they are stale
+					testChildModule(t, config, "consul", ".terraform/modules/consul")
+					testChildModule(t, config, "example", ".terraform/modules/example")
+					testChildModule(t, config, "vpc", ".terraform/modules/vpc")
+				}
+			})
+		})
+	}
+}
+
 func TestLoadConfig_moduleNotFound(t *testing.T) {
 	withinFixtureDir(t, "module_not_found", func(dir string) {
 		loader, err := NewLoader(afero.Afero{Fs: afero.NewOsFs()}, dir)
@@ -284,6 +473,36 @@ func TestLoadConfig_invalidConfiguration(t *testing.T) {
 	})
 }
 
+func TestLoadConfig_invalidTestFiles(t *testing.T) {
+	withinFixtureDir(t, "invalid_test_files", func(dir string) {
+		loader, err := NewLoader(afero.Afero{Fs: afero.NewOsFs()}, dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		config, diags := loader.LoadConfig(".", CallNoModule)
+		if diags.HasErrors() {
+			t.Fatal(diags)
+		}
+
+		// The main module is built even if test files are invalid
+		if _, exists := config.Module.Resources["aws_s3_bucket"]["bucket"]; !exists {
+			t.Fatalf("aws_s3_bucket.bucket is not found: %#v", config.Module.Resources)
+		}
+		if len(config.Module.Tests) != 1 {
+			t.Fatalf("Root module must have 1 test file, but got %d", len(config.Module.Tests))
+		}
+
+		if len(diags) != 2 {
+			t.Fatalf("want 2 warnings, got %d: %s", len(diags), diags)
+		}
+		for _, diag := range diags {
+			if diag.Severity != hcl.DiagWarning {
+				t.Errorf("diagnostic must be a warning: %s", diag)
+			}
+		}
+	})
+}
+
 func TestLoadConfig_circularReferencingModules(t *testing.T) {
 	withinFixtureDir(t, "circular_referencing_modules", func(dir string) {
 		loader, err := NewLoader(afero.Afero{Fs: afero.NewOsFs()}, dir)
@@ -616,3 +835,49 @@ func testChildModule(t *testing.T, config *Config, key string, wantPath string)
 		t.Fatalf("`%s` module path: want=%s, got=%s", key, wantPath, modulePath)
 	}
 }
+
+// BenchmarkLoadConfig loads a large root module that calls the same local module many times.
+func BenchmarkLoadConfig(b *testing.B) {
+	fs := afero.Afero{Fs: afero.NewMemMapFs()}
+	for i := 0; i < 50; i++ {
+		src := ""
+		for j := 0; j < 20; j++ {
+			src += fmt.Sprintf("resource \"null_resource\" \"r_%d_%d\" {\n  triggers = {\n    foo = \"${var.input}-%d\"\n  }\n}\n\n", i, j, j)
+		}
+		src += fmt.Sprintf("module \"child_%d\" {\n  source = \"./modules/child\"\n  input  = var.input\n}\n", i)
+		if err := fs.WriteFile(fmt.Sprintf("main_%d.tf", i), []byte(src), os.ModePerm); err != nil {
+			b.Fatal(err)
+		}
+	}
+	if err := fs.WriteFile("variables.tf", []byte("variable \"input\" {}\n"), os.ModePerm); err != nil {
+		b.Fatal(err)
+	}
+	for i := 0; i < 20; i++ {
+		src := "variable \"input_" + fmt.Sprint(i) + "\" {\n  default = \"\"\n}\n"
+		for j := 0; j < 20; j++ {
+			src += fmt.Sprintf("resource \"null_resource\" \"r_%d_%d\" {\n  triggers = {\n    foo = \"${var.input}-%d\"\n  }\n}\n\n", i, j, j)
+		}
+		if err := fs.WriteFile(fmt.Sprintf("modules/child/main_%d.tf", i), []byte(src), os.ModePerm); err != nil {
+			b.Fatal(err)
+		}
+	}
+	if err := fs.WriteFile("modules/child/variables.tf", []byte("variable \"input\" {}\n"), os.ModePerm); err != nil {
+		b.Fatal(err)
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		loader, err := NewLoader(fs, wd)
+		if err != nil {
+			b.Fatal(err)
+		}
+		if _, diags := loader.LoadConfig(".", CallLocalModule); diags.HasErrors() {
+			b.Fatal(diags)
+		}
+	}
+}
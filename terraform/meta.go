@@ -5,8 +5,12 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"sort"
 )
 
+// workspaceDir is the directory where the local backend stores states of non-default workspaces.
+const workspaceDir = "terraform.tfstate.d"
+
 func dataDir() string {
 	dir := os.Getenv("TF_DATA_DIR")
 	if dir != "" {
@@ -35,3 +39,29 @@ func Workspace() string {
 
 	return current
 }
+
+// Workspaces returns the names of workspaces in the current directory.
+// Like the local backend, it returns "default" and the names of directories in terraform.tfstate.d.
+// Note that workspaces of remote backends cannot be discovered.
+func Workspaces() ([]string, error) {
+	workspaces := []string{"default"}
+
+	entries, err := os.ReadDir(workspaceDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return workspaces, nil
+		}
+		return workspaces, err
+	}
+
+	names := []string{}
+	for _, entry := range entries {
+		if !entry.IsDir() || entry.Name() == "default" {
+			continue
+		}
+		names = append(names, entry.Name())
+	}
+	sort.Strings(names)
+
+	return append(workspaces, names...), nil
+}
@@ -4,6 +4,8 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+
+	"github.com/google/go-cmp/cmp"
 )
 
 func TestWorkspace(t *testing.T) {
@@ -64,3 +66,49 @@ func TestWorkspace(t *testing.T) {
 		})
 	}
 }
+
+func TestWorkspaces(t *testing.T) {
+	currentDir, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		dir  string
+		want []string
+	}{
+		{
+			name: "no workspaces",
+			want: []string{"default"},
+		},
+		{
+			name: "terraform.tfstate.d",
+			dir:  filepath.Join(currentDir, "test-fixtures", "workspaces"),
+			want: []string{"default", "dev", "prod"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if test.dir != "" {
+				if err := os.Chdir(test.dir); err != nil {
+					t.Fatal(err)
+				}
+				defer func() {
+					if err := os.Chdir(currentDir); err != nil {
+						t.Fatal(err)
+					}
+				}()
+			}
+
+			got, err := Workspaces()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
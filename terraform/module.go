@@ -2,41 +2,63 @@ package terraform
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/hclsyntax"
 	hcljson "github.com/hashicorp/hcl/v2/json"
 	"github.com/terraform-linters/tflint-plugin-sdk/hclext"
+	"github.com/terraform-linters/tflint/terraform/lang"
+	"github.com/zclconf/go-cty/cty"
 )
 
 type Module struct {
-	Resources   map[string]map[string]*Resource
-	Variables   map[string]*Variable
-	Locals      map[string]*Local
-	ModuleCalls map[string]*ModuleCall
+	Resources          map[string]map[string]*Resource
+	EphemeralResources map[string]map[string]*Resource
+	Variables          map[string]*Variable
+	Locals             map[string]*Local
+	ModuleCalls        map[string]*ModuleCall
 
 	SourceDir string
 
 	Sources map[string][]byte
 	Files   map[string]*hcl.File
 
+	// Test files and mock data files are kept separately from configuration files.
+	// Only test files of the root module are loaded.
+	Tests       map[string]*TestFile
+	TestSources map[string][]byte
+	TestFiles   map[string]*hcl.File
+
 	primaries map[string]*hcl.File
 	overrides map[string]*hcl.File
+
+	// moduleBlocks and inputs are kept to decode module calls again with
+	// input values passed by the caller. See decodeModuleCalls.
+	moduleBlocks hclext.Blocks
+	inputs       InputValues
+
+	language Language
 }
 
 func NewEmptyModule() *Module {
 	return &Module{
-		Resources:   map[string]map[string]*Resource{},
-		Variables:   map[string]*Variable{},
-		Locals:      map[string]*Local{},
-		ModuleCalls: map[string]*ModuleCall{},
+		Resources:          map[string]map[string]*Resource{},
+		EphemeralResources: map[string]map[string]*Resource{},
+		Variables:          map[string]*Variable{},
+		Locals:             map[string]*Local{},
+		ModuleCalls:        map[string]*ModuleCall{},
 
 		SourceDir: "",
 
 		Sources: map[string][]byte{},
 		Files:   map[string]*hcl.File{},
 
+		Tests:       map[string]*TestFile{},
+		TestSources: map[string][]byte{},
+		TestFiles:   map[string]*hcl.File{},
+
 		primaries: map[string]*hcl.File{},
 		overrides: map[string]*hcl.File{},
 	}
@@ -48,6 +70,7 @@ func (m *Module) build() hcl.Diagnostics {
 		return diags
 	}
 
+	m.moduleBlocks = hclext.Blocks{}
 	for _, block := range body.Blocks {
 		switch block.Type {
 		case "resource":
@@ -56,14 +79,18 @@ func (m *Module) build() hcl.Diagnostics {
 				m.Resources[r.Type] = map[string]*Resource{}
 			}
 			m.Resources[r.Type][r.Name] = r
+		case "ephemeral":
+			r := decodeResourceBlock(block)
+			if _, exists := m.EphemeralResources[r.Type]; !exists {
+				m.EphemeralResources[r.Type] = map[string]*Resource{}
+			}
+			m.EphemeralResources[r.Type][r.Name] = r
 		case "variable":
 			v, valDiags := decodeVairableBlock(block)
 			diags = diags.Extend(valDiags)
 			m.Variables[v.Name] = v
 		case "module":
-			call, moduleDiags := decodeModuleBlock(block)
-			diags = diags.Extend(moduleDiags)
-			m.ModuleCalls[call.Name] = call
+			m.moduleBlocks = append(m.moduleBlocks, block)
 		case "locals":
 			locals := decodeLocalsBlock(block)
 			for _, local := range locals {
@@ -72,9 +99,161 @@ func (m *Module) build() hcl.Diagnostics {
 		}
 	}
 
+	moduleCalls, moduleDiags := m.decodeModuleCalls()
+	diags = diags.Extend(moduleDiags)
+	m.ModuleCalls = moduleCalls
+
 	return diags
 }
 
+// decodeModuleCalls decodes module blocks in the module.
+//
+// OpenTofu allows module sources to refer to variables and locals that can be
+// evaluated early, so this must be called after they are decoded.
+func (m *Module) decodeModuleCalls() (map[string]*ModuleCall, hcl.Diagnostics) {
+	var ctx *hcl.EvalContext
+	if m.language == LanguageOpenTofu {
+		ctx = m.staticEvalContext()
+	}
+
+	var diags hcl.Diagnostics
+	ret := map[string]*ModuleCall{}
+	for _, block := range m.moduleBlocks {
+		call, moduleDiags := decodeModuleBlock(block, ctx)
+		diags = diags.Extend(moduleDiags)
+		ret[call.Name] = call
+	}
+	return ret, diags
+}
+
+// withInputs returns a copy of the module whose module calls are decoded with
+// the passed input values. Modules can be shared between module calls, so the
+// receiver is never modified.
+func (m *Module) withInputs(inputs InputValues) (*Module, hcl.Diagnostics) {
+	ret := *m
+	ret.inputs = inputs
+
+	var diags hcl.Diagnostics
+	ret.ModuleCalls, diags = ret.decodeModuleCalls()
+	return &ret, diags
+}
+
+// moduleCallArguments returns the arguments of the passed module call as input values
+// of the called module. Arguments that cannot be evaluated early are treated as unknown.
+func (m *Module) moduleCallArguments(name string, vars map[string]*Variable) InputValues {
+	schema := &hclext.BodySchema{
+		Blocks: []hclext.BlockSchema{
+			{
+				Type:       "module",
+				LabelNames: []string{"name"},
+				Body:       &hclext.BodySchema{},
+			},
+		},
+	}
+	for varName := range vars {
+		schema.Blocks[0].Body.Attributes = append(schema.Blocks[0].Body.Attributes, hclext.AttributeSchema{Name: varName})
+	}
+
+	ret := InputValues{}
+	content, diags := m.PartialContent(schema, nil)
+	if diags.HasErrors() {
+		return ret
+	}
+	ctx := m.staticEvalContext()
+	for _, block := range content.Blocks {
+		if block.Labels[0] != name {
+			continue
+		}
+		for varName, attr := range block.Body.Attributes {
+			val, diags := attr.Expr.Value(ctx)
+			if diags.HasErrors() {
+				val = cty.DynamicVal
+			}
+			ret[varName] = &InputValue{Value: val}
+		}
+	}
+	return ret
+}
+
+// staticEvalContext returns an evaluation context for early evaluation in OpenTofu.
+// Input values of the module and local values derived from them are available.
+// Variables without values take their defaults, and are treated as unknown if
+// they have no defaults.
+func (m *Module) staticEvalContext() *hcl.EvalContext {
+	vars := map[string]cty.Value{}
+	for name, v := range DefaultVariableValues(m.Variables).Override(m.inputs) {
+		vars[name] = v.Value
+	}
+
+	locals := map[string]cty.Value{}
+	ctx := &hcl.EvalContext{
+		Variables: map[string]cty.Value{
+			"var":   cty.ObjectVal(vars),
+			"local": cty.ObjectVal(locals),
+		},
+		Functions: (&lang.Scope{BaseDir: m.SourceDir, PureOnly: true}).Functions(),
+	}
+
+	// Locals can refer to each other, so evaluate them repeatedly until no more
+	// values can be resolved.
+	for progress := true; progress; {
+		progress = false
+		for name, local := range m.Locals {
+			if _, exists := locals[name]; exists {
+				continue
+			}
+			val, diags := local.Expr.Value(ctx)
+			if diags.HasErrors() || !val.IsWhollyKnown() {
+				continue
+			}
+			locals[name] = val
+			progress = true
+		}
+		ctx.Variables["local"] = cty.ObjectVal(locals)
+	}
+
+	return ctx
+}
+
+// buildTests decodes test files and validates them against the module.
+func (m *Module) buildTests() hcl.Diagnostics {
+	var diags hcl.Diagnostics
+
+	paths := make([]string, 0, len(m.TestFiles))
+	for path := range m.TestFiles {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	m.Tests = map[string]*TestFile{}
+	for _, path := range paths {
+		if isMockDataFile(path) {
+			continue
+		}
+
+		test, testDiags := decodeTestFile(m.TestFiles[path])
+		diags = diags.Extend(testDiags)
+		if testDiags.HasErrors() {
+			continue
+		}
+		diags = diags.Extend(test.validate(m))
+		m.Tests[path] = test
+	}
+
+	return diags
+}
+
+// asWarnings returns a copy of the passed diagnostics with errors downgraded to warnings.
+func asWarnings(diags hcl.Diagnostics) hcl.Diagnostics {
+	ret := make(hcl.Diagnostics, len(diags))
+	for i, diag := range diags {
+		d := *diag
+		d.Severity = hcl.DiagWarning
+		ret[i] = &d
+	}
+	return ret
+}
+
 // Rebuild rebuilds the module from the passed sources.
 // The main purpose of this is to apply autofixes in the module.
 func (m *Module) Rebuild(sources map[string][]byte) hcl.Diagnostics {
@@ -82,6 +261,7 @@ func (m *Module) Rebuild(sources map[string][]byte) hcl.Diagnostics {
 		return nil
 	}
 	var diags hcl.Diagnostics
+	testChanged := false
 
 	for path, source := range sources {
 		var file *hcl.File
@@ -96,6 +276,13 @@ func (m *Module) Rebuild(sources map[string][]byte) hcl.Diagnostics {
 			continue
 		}
 
+		if _, exists := m.TestFiles[path]; exists {
+			m.TestSources[path] = source
+			m.TestFiles[path] = file
+			testChanged = true
+			continue
+		}
+
 		m.Sources[path] = source
 		m.Files[path] = file
 		if _, exists := m.primaries[path]; exists {
@@ -108,6 +295,9 @@ func (m *Module) Rebuild(sources map[string][]byte) hcl.Diagnostics {
 
 	d := m.build()
 	diags = diags.Extend(d)
+	if testChanged {
+		diags = diags.Extend(asWarnings(m.buildTests()))
+	}
 	return diags
 }
 
@@ -122,6 +312,8 @@ func (m *Module) Rebuild(sources map[string][]byte) hcl.Diagnostics {
 //  3. Expands resource/module depends on the meta-arguments
 //     https://developer.hashicorp.com/terraform/language/meta-arguments/count
 //     https://developer.hashicorp.com/terraform/language/meta-arguments/for_each
+//     Providers with for_each are also expanded for OpenTofu
+//     https://opentofu.org/docs/language/providers/configuration/#for_each-multiple-instances-of-a-provider-configuration
 //
 // But 2 and 3 won't run if you didn't pass the evaluation context.
 func (m *Module) PartialContent(schema *hclext.BodySchema, ctx *Evaluator) (*hclext.BodyContent, hcl.Diagnostics) {
@@ -179,6 +371,10 @@ var moduleSchema = &hclext.BodySchema{
 			Type:       "resource",
 			LabelNames: []string{"type", "name"},
 		},
+		{
+			Type:       "ephemeral",
+			LabelNames: []string{"type", "name"},
+		},
 		{
 			Type:       "variable",
 			LabelNames: []string{"name"},
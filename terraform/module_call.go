@@ -5,7 +5,9 @@ package terraform
 
 import (
 	"fmt"
+	"log"
 
+	"github.com/hashicorp/go-version"
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/gohcl"
 	"github.com/terraform-linters/tflint-plugin-sdk/hclext"
@@ -17,10 +19,17 @@ type ModuleCall struct {
 	SourceAddr    addrs.ModuleSource
 	SourceAddrRaw string
 
+	// Version is the version constraints of the module.
+	// This is only meaningful for registry modules.
+	Version version.Constraints
+
 	DeclRange hcl.Range
 }
 
-func decodeModuleBlock(block *hclext.Block) (*ModuleCall, hcl.Diagnostics) {
+// decodeModuleBlock decodes a module block. If an evaluation context is passed,
+// the source and version attributes are evaluated with it. If they are unknown,
+// the source address is not set and the module is not loaded.
+func decodeModuleBlock(block *hclext.Block, ctx *hcl.EvalContext) (*ModuleCall, hcl.Diagnostics) {
 	var diags hcl.Diagnostics
 
 	mc := &ModuleCall{
@@ -29,7 +38,14 @@ func decodeModuleBlock(block *hclext.Block) (*ModuleCall, hcl.Diagnostics) {
 	}
 
 	if attr, exists := block.Body.Attributes["source"]; exists {
-		valDiags := gohcl.DecodeExpression(attr.Expr, nil, &mc.SourceAddrRaw)
+		if ctx != nil {
+			val, valDiags := attr.Expr.Value(ctx)
+			if !valDiags.HasErrors() && !val.IsWhollyKnown() {
+				log.Printf("[DEBUG] The source of module %s is unknown. Skip loading the module", mc.Name)
+				return mc, diags
+			}
+		}
+		valDiags := gohcl.DecodeExpression(attr.Expr, ctx, &mc.SourceAddrRaw)
 		diags = diags.Extend(valDiags)
 
 		if !diags.HasErrors() {
@@ -46,6 +62,33 @@ func decodeModuleBlock(block *hclext.Block) (*ModuleCall, hcl.Diagnostics) {
 		}
 	}
 
+	if attr, exists := block.Body.Attributes["version"]; exists {
+		if ctx != nil {
+			val, valDiags := attr.Expr.Value(ctx)
+			if !valDiags.HasErrors() && !val.IsWhollyKnown() {
+				log.Printf("[DEBUG] The version of module %s is unknown. Skip loading the module", mc.Name)
+				mc.SourceAddr = nil
+				return mc, diags
+			}
+		}
+		var raw string
+		valDiags := gohcl.DecodeExpression(attr.Expr, ctx, &raw)
+		diags = diags.Extend(valDiags)
+
+		if !valDiags.HasErrors() {
+			var err error
+			mc.Version, err = version.NewConstraint(raw)
+			if err != nil {
+				diags = diags.Append(&hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Invalid version constraint",
+					Detail:   fmt.Sprintf("Failed to parse version constraint: %s", err),
+					Subject:  attr.Expr.Range().Ptr(),
+				})
+			}
+		}
+	}
+
 	return mc, diags
 }
 
@@ -54,6 +97,9 @@ var moduleBlockSchema = &hclext.BodySchema{
 		{
 			Name: "source",
 		},
+		{
+			Name: "version",
+		},
 	},
 }
 
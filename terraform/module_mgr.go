@@ -7,9 +7,11 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/hcl/v2"
 	"github.com/spf13/afero"
 	"github.com/terraform-linters/tflint/terraform/addrs"
 )
@@ -74,8 +76,171 @@ func (l *moduleMgr) readModuleManifest() error {
 	}
 
 	for _, record := range read.Records {
+		if record.VersionStr != "" {
+			record.Version, err = version.NewVersion(record.VersionStr)
+			if err != nil {
+				return fmt.Errorf("invalid version %q for %s: %s", record.VersionStr, record.Key, err)
+			}
+		}
 		l.manifest[record.Key] = record
 	}
 
 	return nil
 }
+
+// StaleModuleManifest is a type of how to handle module manifests that
+// do not match the configuration. This usually happens when the module
+// source or version is changed without running "terraform init".
+type StaleModuleManifest int32
+
+const (
+	// StaleModuleManifestWarning reports mismatches as warnings and loads the installed modules.
+	StaleModuleManifestWarning StaleModuleManifest = iota
+
+	// StaleModuleManifestError reports mismatches as errors.
+	StaleModuleManifestError
+
+	// StaleModuleManifestIgnore does not check the module manifest.
+	StaleModuleManifestIgnore
+)
+
+func AsStaleModuleManifest(s string) (StaleModuleManifest, error) {
+	switch s {
+	case "warning":
+		return StaleModuleManifestWarning, nil
+	case "error":
+		return StaleModuleManifestError, nil
+	case "ignore":
+		return StaleModuleManifestIgnore, nil
+	default:
+		return StaleModuleManifestWarning, fmt.Errorf("%s is invalid stale module manifest handling. Allowed values are: warning, error, ignore", s)
+	}
+}
+
+func (s StaleModuleManifest) String() string {
+	switch s {
+	case StaleModuleManifestWarning:
+		return "warning"
+	case StaleModuleManifestError:
+		return "error"
+	case StaleModuleManifestIgnore:
+		return "ignore"
+	default:
+		panic("never happened")
+	}
+}
+
+func (s StaleModuleManifest) severity() hcl.DiagnosticSeverity {
+	if s == StaleModuleManifestError {
+		return hcl.DiagError
+	}
+	return hcl.DiagWarning
+}
+
+// checkRecord checks that the manifest record of the remote module is consistent
+// with the module call. It returns a diagnostic if the source or version was changed
+// since the module was installed.
+func (m moduleManifest) checkRecord(req *ModuleRequest, record *moduleRecord, mode StaleModuleManifest) hcl.Diagnostics {
+	if mode == StaleModuleManifestIgnore {
+		return nil
+	}
+	lang := req.Parent.Root.Module.language
+
+	if canonicalModuleSource(record.Source, lang) != canonicalModuleSource(req.SourceAddr.String(), lang) {
+		return hcl.Diagnostics{
+			{
+				Severity: mode.severity(),
+				Summary:  "Module source has changed",
+				Detail:   fmt.Sprintf(`The source address of module "%s" was changed from "%s" to "%s" since the module was installed. Run "%s" to install the new module.`, req.Name, record.Source, req.SourceAddr, lang.initCommand()),
+				Subject:  &req.CallRange,
+			},
+		}
+	}
+
+	if len(req.VersionConstraint) > 0 && record.Version != nil && !req.VersionConstraint.Check(record.Version) {
+		return hcl.Diagnostics{
+			{
+				Severity: mode.severity(),
+				Summary:  "Module version requirements have changed",
+				Detail:   fmt.Sprintf(`The version requirements of module "%s" were changed to "%s" since the module was installed, and the installed version (%s) is no longer acceptable. Run "%s" to install a matching version.`, req.Name, req.VersionConstraint, record.Version, lang.initCommand()),
+				Subject:  &req.CallRange,
+			},
+		}
+	}
+
+	return nil
+}
+
+// checkOrphanedRecords returns diagnostics for manifest records that no longer
+// have corresponding module calls in the configuration.
+//
+// A record is only reported if the parent module was loaded, since modules
+// under skipped module calls (e.g. remote modules with --call-module-type=local)
+// cannot be checked.
+func (m moduleManifest) checkOrphanedRecords(cfg *Config, mode StaleModuleManifest) hcl.Diagnostics {
+	if mode == StaleModuleManifestIgnore {
+		return nil
+	}
+	var diags hcl.Diagnostics
+
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if key == "" {
+			// The root module
+			continue
+		}
+		path := strings.Split(key, ".")
+
+		parent := cfg
+		for _, name := range path[:len(path)-1] {
+			parent = parent.Children[name]
+			if parent == nil {
+				break
+			}
+		}
+		if parent == nil {
+			continue
+		}
+		if _, exists := parent.Module.ModuleCalls[path[len(path)-1]]; exists {
+			continue
+		}
+
+		diags = diags.Append(&hcl.Diagnostic{
+			Severity: mode.severity(),
+			Summary:  "Module is not declared",
+			Detail:   fmt.Sprintf(`The module manifest has the module "%s", but there is no corresponding module call in the configuration. Run "%s" to update the manifest.`, key, cfg.Module.language.initCommand()),
+			Subject:  &hcl.Range{Filename: moduleManifestPath()},
+		})
+	}
+
+	return diags
+}
+
+// canonicalModuleSource returns the normalized module source address for comparison.
+// The manifest may record an address normalized by Terraform (e.g. "registry.terraform.io/hashicorp/consul/aws"
+// or "git::https://github.com/hashicorp/example.git"), so the address in the configuration cannot be compared as-is.
+func canonicalModuleSource(source string, lang Language) string {
+	pkg, subdir := splitModuleSubdir(source)
+	if subdir != "" {
+		subdir = "//" + subdir
+	}
+
+	if match := registrySourcePattern.FindStringSubmatch(pkg); match != nil {
+		host := match[1]
+		if host == "" {
+			host = defaultRegistryHost(lang)
+		}
+		return strings.ToLower(host) + "/" + match[2] + "/" + match[3] + "/" + match[4] + subdir
+	}
+
+	pkgPath, err := mirrorPackagePath(pkg)
+	if err != nil {
+		return source
+	}
+	return pkgPath + subdir
+}
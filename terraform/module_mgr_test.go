@@ -35,3 +35,53 @@ func Test_moduleManifestPath(t *testing.T) {
 		})
 	}
 }
+
+func Test_canonicalModuleSource(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		lang   Language
+		want   string
+	}{
+		{
+			name:   "registry",
+			source: "hashicorp/consul/aws",
+			want:   "registry.terraform.io/hashicorp/consul/aws",
+		},
+		{
+			name:   "registry with host",
+			source: "Registry.Terraform.io/hashicorp/consul/aws",
+			want:   "registry.terraform.io/hashicorp/consul/aws",
+		},
+		{
+			name:   "registry in OpenTofu",
+			source: "hashicorp/consul/aws",
+			lang:   LanguageOpenTofu,
+			want:   "registry.opentofu.org/hashicorp/consul/aws",
+		},
+		{
+			name:   "registry with subdir",
+			source: "hashicorp/consul/aws//modules/consul-cluster",
+			want:   "registry.terraform.io/hashicorp/consul/aws//modules/consul-cluster",
+		},
+		{
+			name:   "GitHub shorthand",
+			source: "github.com/hashicorp/example",
+			want:   "github.com/hashicorp/example",
+		},
+		{
+			name:   "normalized Git",
+			source: "git::https://github.com/hashicorp/example.git?ref=v1.0.0",
+			want:   "github.com/hashicorp/example/v1.0.0",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := canonicalModuleSource(test.source, test.lang)
+			if test.want != got {
+				t.Errorf("want: %s, got: %s", test.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,186 @@
+package terraform
+
+import (
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/go-version"
+	"github.com/spf13/afero"
+)
+
+const (
+	defaultTerraformRegistryHost = "registry.terraform.io"
+	defaultOpenTofuRegistryHost  = "registry.opentofu.org"
+)
+
+// registrySourcePattern matches module registry addresses like "[<HOST>/]<NAMESPACE>/<NAME>/<PROVIDER>".
+// Like Terraform, namespaces cannot contain dots, so shorthands like "github.com/org/repo" are not matched.
+var registrySourcePattern = regexp.MustCompile(`^(?:([^/]+\.[^/]+)/)?([0-9A-Za-z](?:[0-9A-Za-z-_]{0,62}[0-9A-Za-z])?)/([0-9A-Za-z](?:[0-9A-Za-z-_]{0,62}[0-9A-Za-z])?)/([0-9a-z]{1,64})$`)
+
+func defaultRegistryHost(lang Language) string {
+	if lang == LanguageOpenTofu {
+		return defaultOpenTofuRegistryHost
+	}
+	return defaultTerraformRegistryHost
+}
+
+// moduleMirror resolves remote module sources to directories in a local mirror.
+// This allows loading remote modules without the module manifest created by "terraform init".
+//
+// The mirror directory has the following layout:
+//
+//   - Registry modules: <HOST>/<NAMESPACE>/<NAME>/<PROVIDER>/<VERSION>
+//   - Other remote modules: <HOST>/<PATH>[/<REF>]
+//
+// For other remote modules, forced getters (e.g. "git::"), URL schemes, and the ".git" suffix
+// are removed from the address, and the "ref" query parameter is used as the last path segment.
+// A subdirectory specified with "//" is resolved within the package directory.
+type moduleMirror struct {
+	fs  afero.Afero
+	dir string
+}
+
+// resolve returns the directory of the passed module source in the mirror.
+// If the source is a registry address, it also returns the latest version
+// that satisfies the passed version constraints.
+func (m *moduleMirror) resolve(source string, constraints version.Constraints, lang Language) (string, *version.Version, error) {
+	pkg, subdir := splitModuleSubdir(source)
+
+	var dir string
+	var ver *version.Version
+	if match := registrySourcePattern.FindStringSubmatch(pkg); match != nil {
+		host := match[1]
+		if host == "" {
+			host = defaultRegistryHost(lang)
+		}
+		pkgDir := filepath.Join(m.dir, strings.ToLower(host), match[2], match[3], match[4])
+
+		var err error
+		ver, err = m.latestVersion(pkgDir, constraints)
+		if err != nil {
+			return "", nil, err
+		}
+		dir = filepath.Join(pkgDir, ver.Original())
+	} else {
+		pkgPath, err := mirrorPackagePath(pkg)
+		if err != nil {
+			return "", nil, err
+		}
+		dir = filepath.Join(m.dir, filepath.FromSlash(pkgPath))
+	}
+
+	if subdir != "" {
+		dir = filepath.Join(dir, filepath.FromSlash(subdir))
+	}
+	if _, err := m.fs.Stat(dir); err != nil {
+		if os.IsNotExist(err) {
+			return "", nil, fmt.Errorf(`the directory "%s" does not exist in the module mirror`, dir)
+		}
+		return "", nil, err
+	}
+
+	log.Printf("[DEBUG] Resolved the module source in the mirror: source=%s, dir=%s", source, dir)
+	return filepath.ToSlash(dir), ver, nil
+}
+
+// latestVersion returns the latest version in the passed directory that satisfies the constraints.
+// Each subdirectory name is treated as a version. Directories that are not valid versions are ignored.
+func (m *moduleMirror) latestVersion(dir string, constraints version.Constraints) (*version.Version, error) {
+	entries, err := m.fs.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf(`the directory "%s" does not exist in the module mirror`, dir)
+		}
+		return nil, err
+	}
+
+	versions := version.Collection{}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		v, err := version.NewVersion(entry.Name())
+		if err != nil {
+			log.Printf("[DEBUG] Ignore %s in the module mirror: %s", filepath.Join(dir, entry.Name()), err)
+			continue
+		}
+		// Like Terraform, pre-releases are never selected without constraints.
+		// If constraints are given, pre-releases are only matched by constraints with pre-releases.
+		if len(constraints) == 0 && v.Prerelease() != "" {
+			continue
+		}
+		if constraints.Check(v) {
+			versions = append(versions, v)
+		}
+	}
+	if len(versions) == 0 {
+		return nil, fmt.Errorf(`no versions matching "%s" were found in "%s"`, constraints, dir)
+	}
+	sort.Sort(versions)
+
+	return versions[len(versions)-1], nil
+}
+
+// splitModuleSubdir splits the passed source into the package address and subdirectory.
+// This is similar to getter.SourceDirSubdir, but the query string is kept in the package address.
+func splitModuleSubdir(source string) (string, string) {
+	// Skip the scheme (e.g. "https://") so that it is not treated as a subdir separator
+	start := 0
+	if idx := strings.Index(source, "://"); idx >= 0 {
+		start = idx + 3
+	}
+
+	idx := strings.Index(source[start:], "//")
+	if idx < 0 {
+		return source, ""
+	}
+	idx += start
+
+	pkg := source[:idx]
+	subdir := source[idx+2:]
+
+	// The query string is a part of the package address
+	if qidx := strings.Index(subdir, "?"); qidx >= 0 {
+		pkg += subdir[qidx:]
+		subdir = subdir[:qidx]
+	}
+
+	return pkg, path.Clean(subdir)
+}
+
+// mirrorPackagePath converts the passed remote package address into the path in the mirror.
+func mirrorPackagePath(pkg string) (string, error) {
+	// Remove forced getters like "git::"
+	if idx := strings.Index(pkg, "::"); idx >= 0 {
+		pkg = pkg[idx+2:]
+	}
+	// scp-like Git addresses (e.g. git@github.com:org/repo.git)
+	if !strings.Contains(pkg, "://") && strings.Contains(pkg, "@") && strings.Contains(pkg, ":") {
+		pkg = "ssh://" + strings.Replace(pkg, ":", "/", 1)
+	}
+	if !strings.Contains(pkg, "://") {
+		pkg = "https://" + pkg
+	}
+
+	u, err := url.Parse(pkg)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse the module source: %w", err)
+	}
+	if u.Hostname() == "" {
+		return "", fmt.Errorf(`failed to parse the module source "%s": no host`, pkg)
+	}
+
+	ret := path.Join(strings.ToLower(u.Hostname()), strings.TrimSuffix(strings.Trim(u.Path, "/"), ".git"))
+	if ref := u.Query().Get("ref"); ref != "" {
+		ret = path.Join(ret, ref)
+	}
+
+	return ret, nil
+}
@@ -0,0 +1,151 @@
+package terraform
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/go-version"
+	"github.com/spf13/afero"
+)
+
+func TestModuleMirror_resolve(t *testing.T) {
+	dirs := []string{
+		"mirror/registry.terraform.io/terraform-aws-modules/vpc/aws/5.0.0",
+		"mirror/registry.terraform.io/terraform-aws-modules/vpc/aws/5.1.2",
+		"mirror/registry.terraform.io/terraform-aws-modules/vpc/aws/6.0.0-beta1",
+		"mirror/registry.terraform.io/terraform-aws-modules/vpc/aws/5.1.2/modules/vpc-endpoints",
+		"mirror/registry.opentofu.org/terraform-aws-modules/vpc/aws/4.0.0",
+		"mirror/app.terraform.io/example-corp/k8s-cluster/azurerm/1.0.0",
+		"mirror/github.com/hashicorp/example",
+		"mirror/github.com/hashicorp/example/v1.2.0/modules/foo",
+		"mirror/example.com/storage/vpc",
+	}
+
+	tests := []struct {
+		name        string
+		source      string
+		constraints string
+		lang        Language
+		dir         string
+		version     string
+		err         string
+	}{
+		{
+			name:    "registry",
+			source:  "terraform-aws-modules/vpc/aws",
+			dir:     "mirror/registry.terraform.io/terraform-aws-modules/vpc/aws/5.1.2",
+			version: "5.1.2",
+		},
+		{
+			name:        "registry with constraints",
+			source:      "terraform-aws-modules/vpc/aws",
+			constraints: "~> 5.0.0",
+			dir:         "mirror/registry.terraform.io/terraform-aws-modules/vpc/aws/5.0.0",
+			version:     "5.0.0",
+		},
+		{
+			name:        "registry with pre-release constraints",
+			source:      "terraform-aws-modules/vpc/aws",
+			constraints: "6.0.0-beta1",
+			dir:         "mirror/registry.terraform.io/terraform-aws-modules/vpc/aws/6.0.0-beta1",
+			version:     "6.0.0-beta1",
+		},
+		{
+			name:    "registry with subdir",
+			source:  "terraform-aws-modules/vpc/aws//modules/vpc-endpoints",
+			dir:     "mirror/registry.terraform.io/terraform-aws-modules/vpc/aws/5.1.2/modules/vpc-endpoints",
+			version: "5.1.2",
+		},
+		{
+			name:    "registry with host",
+			source:  "app.terraform.io/example-corp/k8s-cluster/azurerm",
+			dir:     "mirror/app.terraform.io/example-corp/k8s-cluster/azurerm/1.0.0",
+			version: "1.0.0",
+		},
+		{
+			name:    "registry in OpenTofu",
+			source:  "terraform-aws-modules/vpc/aws",
+			lang:    LanguageOpenTofu,
+			dir:     "mirror/registry.opentofu.org/terraform-aws-modules/vpc/aws/4.0.0",
+			version: "4.0.0",
+		},
+		{
+			name:        "registry without matching versions",
+			source:      "terraform-aws-modules/vpc/aws",
+			constraints: ">= 7.0",
+			err:         `no versions matching ">= 7.0" were found in "mirror/registry.terraform.io/terraform-aws-modules/vpc/aws"`,
+		},
+		{
+			name:   "GitHub shorthand",
+			source: "github.com/hashicorp/example",
+			dir:    "mirror/github.com/hashicorp/example",
+		},
+		{
+			name:   "Git with ref and subdir",
+			source: "git::https://github.com/hashicorp/example.git//modules/foo?ref=v1.2.0",
+			dir:    "mirror/github.com/hashicorp/example/v1.2.0/modules/foo",
+		},
+		{
+			name:   "Git over SSH",
+			source: "git@github.com:hashicorp/example.git",
+			dir:    "mirror/github.com/hashicorp/example",
+		},
+		{
+			name:   "S3",
+			source: "s3::https://example.com/storage/vpc",
+			dir:    "mirror/example.com/storage/vpc",
+		},
+		{
+			name:   "not found",
+			source: "github.com/hashicorp/not_found",
+			err:    `the directory "mirror/github.com/hashicorp/not_found" does not exist in the module mirror`,
+		},
+	}
+
+	fs := afero.Afero{Fs: afero.NewMemMapFs()}
+	for _, dir := range dirs {
+		if err := fs.MkdirAll(dir, os.ModePerm); err != nil {
+			t.Fatal(err)
+		}
+	}
+	mirror := &moduleMirror{fs: fs, dir: "mirror"}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var constraints version.Constraints
+			if test.constraints != "" {
+				var err error
+				constraints, err = version.NewConstraint(test.constraints)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			dir, ver, err := mirror.resolve(test.source, constraints, test.lang)
+			if err != nil {
+				if test.err == "" {
+					t.Fatalf("unexpected error: %s", err)
+				}
+				if err.Error() != filepath.FromSlash(test.err) {
+					t.Fatalf("expected error is `%s`, but got `%s`", test.err, err)
+				}
+				return
+			}
+			if test.err != "" {
+				t.Fatalf("expected error is `%s`, but got no error", test.err)
+			}
+
+			if dir != test.dir {
+				t.Errorf("dir: want=%s, got=%s", test.dir, dir)
+			}
+			var gotVersion string
+			if ver != nil {
+				gotVersion = ver.String()
+			}
+			if gotVersion != test.version {
+				t.Errorf("version: want=%s, got=%s", test.version, gotVersion)
+			}
+		})
+	}
+}
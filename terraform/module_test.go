@@ -499,6 +499,89 @@ resource "aws_instance" "bar" {
 				},
 			},
 		},
+		{
+			name: "provider for_each in Terraform",
+			files: map[string]string{
+				"main.tf": `
+variable "regions" {
+  default = ["us-east-1", "us-west-2"]
+}
+
+provider "aws" {
+  alias    = "by_region"
+  for_each = toset(var.regions)
+  region   = each.value
+}`,
+			},
+			schema: &hclext.BodySchema{
+				Blocks: []hclext.BlockSchema{
+					{
+						Type:       "provider",
+						LabelNames: []string{"name"},
+						Body:       &hclext.BodySchema{Attributes: []hclext.AttributeSchema{{Name: "region"}}},
+					},
+				},
+			},
+			want: &hclext.BodyContent{
+				Blocks: hclext.Blocks{
+					{
+						Type:   "provider",
+						Labels: []string{"aws"},
+						Body: &hclext.BodyContent{
+							Attributes: hclext.Attributes{"region": &hclext.Attribute{Name: "region", Range: hcl.Range{Filename: "main.tf"}}},
+							Blocks:     hclext.Blocks{},
+						},
+						DefRange: hcl.Range{Filename: "main.tf"},
+					},
+				},
+			},
+		},
+		{
+			name: "provider for_each in OpenTofu",
+			files: map[string]string{
+				"main.tofu": `
+variable "regions" {
+  default = ["us-east-1", "us-west-2"]
+}
+
+provider "aws" {
+  alias    = "by_region"
+  for_each = toset(var.regions)
+  region   = each.value
+}`,
+			},
+			schema: &hclext.BodySchema{
+				Blocks: []hclext.BlockSchema{
+					{
+						Type:       "provider",
+						LabelNames: []string{"name"},
+						Body:       &hclext.BodySchema{Attributes: []hclext.AttributeSchema{{Name: "region"}}},
+					},
+				},
+			},
+			want: &hclext.BodyContent{
+				Blocks: hclext.Blocks{
+					{
+						Type:   "provider",
+						Labels: []string{"aws"},
+						Body: &hclext.BodyContent{
+							Attributes: hclext.Attributes{"region": &hclext.Attribute{Name: "region", Range: hcl.Range{Filename: "main.tofu"}}},
+							Blocks:     hclext.Blocks{},
+						},
+						DefRange: hcl.Range{Filename: "main.tofu"},
+					},
+					{
+						Type:   "provider",
+						Labels: []string{"aws"},
+						Body: &hclext.BodyContent{
+							Attributes: hclext.Attributes{"region": &hclext.Attribute{Name: "region", Range: hcl.Range{Filename: "main.tofu"}}},
+							Blocks:     hclext.Blocks{},
+						},
+						DefRange: hcl.Range{Filename: "main.tofu"},
+					},
+				},
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -551,6 +634,116 @@ resource "aws_instance" "bar" {
 	}
 }
 
+func TestBuild_moduleSource(t *testing.T) {
+	tests := []struct {
+		name     string
+		files    map[string]string
+		language Language
+		want     string
+		err      string
+	}{
+		{
+			name: "static source",
+			files: map[string]string{
+				"main.tf": `
+module "foo" {
+  source = "./modules/foo"
+}`,
+			},
+			want: "./modules/foo",
+		},
+		{
+			name: "variable in Terraform",
+			files: map[string]string{
+				"main.tf": `
+variable "env" {
+  default = "prod"
+}
+
+module "foo" {
+  source = "./modules/${var.env}"
+}`,
+			},
+			language: LanguageTerraform,
+			want:     "",
+			err:      "main.tf:7,25-28: Variables not allowed; Variables may not be used here., and 1 other diagnostic(s)",
+		},
+		{
+			name: "variable in OpenTofu",
+			files: map[string]string{
+				"main.tf": `
+variable "env" {
+  default = "prod"
+}
+
+module "foo" {
+  source = "./modules/${var.env}"
+}`,
+			},
+			language: LanguageOpenTofu,
+			want:     "./modules/prod",
+		},
+		{
+			name: "locals in OpenTofu",
+			files: map[string]string{
+				"main.tofu": `
+variable "env" {
+  default = "prod"
+}
+
+locals {
+  dir    = "${local.prefix}/${var.env}"
+  prefix = "./modules"
+}
+
+module "foo" {
+  source = local.dir
+}`,
+			},
+			want: "./modules/prod",
+		},
+		{
+			name: "unknown variable in OpenTofu",
+			files: map[string]string{
+				"main.tofu": `
+variable "env" {}
+
+module "foo" {
+  source = "./modules/${var.env}"
+}`,
+			},
+			want: "",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fs := afero.Afero{Fs: afero.NewMemMapFs()}
+			for name, content := range test.files {
+				if err := fs.WriteFile(name, []byte(content), os.ModePerm); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			parser := NewParser(fs)
+			parser.SetLanguage(test.language)
+			mod, diags := parser.LoadConfigDir(".", ".")
+			if diags.HasErrors() {
+				if diags.Error() != test.err {
+					t.Fatalf("want=%s, got=%s", test.err, diags.Error())
+				}
+			} else if test.err != "" {
+				t.Fatalf("want=%s, got no error", test.err)
+			}
+
+			got := mod.ModuleCalls["foo"].SourceAddrRaw
+			if got != test.want {
+				t.Errorf("want=%s, got=%s", test.want, got)
+			}
+		})
+	}
+}
+
 func Test_overrideBlocks(t *testing.T) {
 	tests := []struct {
 		Name      string
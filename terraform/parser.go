@@ -4,11 +4,14 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"runtime"
 	"sort"
 	"strings"
+	"sync"
 
 	"github.com/hashicorp/hcl/v2"
-	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/json"
 	"github.com/spf13/afero"
 	"github.com/zclconf/go-cty/cty"
 )
@@ -17,10 +20,15 @@ import (
 // configuration files and other related files from disk.
 //
 // It retains a cache of all files that are loaded so that they can be used
-// to create source code snippets in diagnostics, etc.
+// to create source code snippets in diagnostics, etc. Files in a directory
+// are parsed concurrently, so the cache is safe for concurrent use.
 type Parser struct {
 	fs afero.Afero
-	p  *hclparse.Parser
+
+	filesMu sync.Mutex
+	files   map[string]*hcl.File
+
+	language Language
 }
 
 // NewParser creates and returns a new Parser that reads files from the given
@@ -32,9 +40,45 @@ func NewParser(fs afero.Fs) *Parser {
 	}
 
 	return &Parser{
-		fs: afero.Afero{Fs: fs},
-		p:  hclparse.NewParser(),
+		fs:    afero.Afero{Fs: fs},
+		files: map[string]*hcl.File{},
+	}
+}
+
+// SetLanguage sets the language used to interpret configuration files.
+// By default, the language is detected from the files in each directory.
+func (p *Parser) SetLanguage(lang Language) {
+	p.language = lang
+}
+
+// Language returns the language used to interpret the given directory.
+// If the language is not set explicitly, it is detected as OpenTofu if the
+// directory contains .tofu/.tofu.json files or the dependency lock file
+// refers to the OpenTofu registry. Otherwise, it is Terraform.
+func (p *Parser) Language(dir string) Language {
+	if p.language != LanguageAuto {
+		return p.language
+	}
+
+	infos, err := p.fs.ReadDir(dir)
+	if err != nil {
+		return LanguageTerraform
+	}
+	for _, info := range infos {
+		if info.IsDir() || isIgnoredFile(info.Name()) {
+			continue
+		}
+		if strings.HasSuffix(info.Name(), ".tofu") || strings.HasSuffix(info.Name(), ".tofu.json") {
+			return LanguageOpenTofu
+		}
+	}
+
+	lockFile, err := p.fs.ReadFile(filepath.Join(dir, lockFilename))
+	if err == nil && strings.Contains(string(lockFile), openTofuRegistryHost) {
+		return LanguageOpenTofu
 	}
+
+	return LanguageTerraform
 }
 
 // LoadConfigDir reads the .tf and .tf.json files in the given directory and
@@ -53,7 +97,8 @@ func NewParser(fs afero.Fs) *Parser {
 // will simply return an empty module in that case.
 //
 // .tf files are parsed using the HCL native syntax while .tf.json files are
-// parsed using the HCL JSON syntax.
+// parsed using the HCL JSON syntax. In OpenTofu, .tofu and .tofu.json files are
+// also read, and they take precedence over .tf/.tf.json files with the same name.
 //
 // If a baseDir is passed, the loaded files are assumed to be loaded from that
 // directory. However, SourceDir does not contain baseDir because it affects
@@ -65,28 +110,24 @@ func (p *Parser) LoadConfigDir(baseDir, dir string) (*Module, hcl.Diagnostics) {
 	}
 
 	mod := NewEmptyModule()
+	mod.language = p.Language(dir)
 
-	for _, path := range primaries {
-		f, loadDiags := p.loadHCLFile(baseDir, path)
-		diags = diags.Extend(loadDiags)
-		if loadDiags.HasErrors() {
-			continue
-		}
-		realPath := filepath.Join(baseDir, path)
+	paths := append(primaries, overrides...)
+	files, loadDiags := p.loadHCLFiles(baseDir, paths)
+	diags = diags.Extend(loadDiags)
 
-		mod.primaries[realPath] = f
-		mod.Sources[realPath] = f.Bytes
-		mod.Files[realPath] = f
-	}
-	for _, path := range overrides {
-		f, loadDiags := p.loadHCLFile(baseDir, path)
-		diags = diags.Extend(loadDiags)
-		if loadDiags.HasErrors() {
+	for i, path := range paths {
+		f := files[i]
+		if f == nil {
 			continue
 		}
 		realPath := filepath.Join(baseDir, path)
 
-		mod.overrides[realPath] = f
+		if i < len(primaries) {
+			mod.primaries[realPath] = f
+		} else {
+			mod.overrides[realPath] = f
+		}
 		mod.Sources[realPath] = f.Bytes
 		mod.Files[realPath] = f
 	}
@@ -118,23 +159,54 @@ func (p *Parser) LoadConfigDirFiles(baseDir, dir string) (map[string]*hcl.File,
 		return map[string]*hcl.File{}, diags
 	}
 
-	files := map[string]*hcl.File{}
+	paths := append(primaries, overrides...)
+	loaded, loadDiags := p.loadHCLFiles(baseDir, paths)
+	diags = diags.Extend(loadDiags)
 
-	for _, path := range primaries {
-		f, loadDiags := p.loadHCLFile(baseDir, path)
-		diags = diags.Extend(loadDiags)
-		if loadDiags.HasErrors() {
+	files := map[string]*hcl.File{}
+	for i, path := range paths {
+		if loaded[i] == nil {
 			continue
 		}
-		files[filepath.Join(baseDir, path)] = f
+		files[filepath.Join(baseDir, path)] = loaded[i]
 	}
-	for _, path := range overrides {
-		f, loadDiags := p.loadHCLFile(baseDir, path)
-		diags = diags.Extend(loadDiags)
-		if loadDiags.HasErrors() {
+
+	return files, diags
+}
+
+// LoadTestFiles reads the test files (.tftest.hcl and .tftest.json) and the mock
+// data files (.tfmock.hcl and .tfmock.json) in the given directory and its "tests"
+// directory, then returns these files as a map of file path. In OpenTofu,
+// .tofutest.hcl and .tofutest.json files are also read.
+//
+// Unlike configuration files, it is not an error if the directory does not exist.
+//
+// If a baseDir is passed, the loaded files are assumed to be loaded from that
+// directory.
+func (p *Parser) LoadTestFiles(baseDir, dir string) (map[string]*hcl.File, hcl.Diagnostics) {
+	lang := p.Language(dir)
+	files := map[string]*hcl.File{}
+	var diags hcl.Diagnostics
+
+	for _, testDir := range []string{dir, filepath.Join(dir, defaultTestDirectory)} {
+		infos, err := p.fs.ReadDir(testDir)
+		if err != nil {
 			continue
 		}
-		files[filepath.Join(baseDir, path)] = f
+
+		for _, info := range infos {
+			if info.IsDir() || isIgnoredFile(info.Name()) || !isTestFile(info.Name(), lang) {
+				continue
+			}
+
+			path := filepath.Join(testDir, info.Name())
+			f, loadDiags := p.loadHCLFile(baseDir, path)
+			diags = diags.Extend(loadDiags)
+			if loadDiags.HasErrors() {
+				continue
+			}
+			files[filepath.Join(baseDir, path)] = f
+		}
 	}
 
 	return files, diags
@@ -207,31 +279,93 @@ func (p *Parser) loadHCLFile(baseDir, path string) (*hcl.File, hcl.Diagnostics)
 		}
 	}
 
+	// Like hclparse.Parser, a file that has already been parsed is returned
+	// from the cache without diagnostics.
+	p.filesMu.Lock()
+	existing := p.files[realPath]
+	p.filesMu.Unlock()
+	if existing != nil {
+		return existing, nil
+	}
+
+	var f *hcl.File
+	var diags hcl.Diagnostics
 	switch {
 	case strings.HasSuffix(path, ".json"):
-		return p.p.ParseJSON(src, realPath)
+		f, diags = json.Parse(src, realPath)
 	default:
-		return p.p.ParseHCL(src, realPath)
+		f, diags = hclsyntax.ParseConfig(src, realPath, hcl.InitialPos)
+	}
+
+	p.filesMu.Lock()
+	defer p.filesMu.Unlock()
+	if existing := p.files[realPath]; existing != nil {
+		return existing, nil
+	}
+	p.files[realPath] = f
+	return f, diags
+}
+
+// loadHCLFiles loads the given files concurrently. The returned files are
+// in the same order as the given paths, and files with errors are nil.
+// Diagnostics are also returned in the order of the paths.
+func (p *Parser) loadHCLFiles(baseDir string, paths []string) ([]*hcl.File, hcl.Diagnostics) {
+	files := make([]*hcl.File, len(paths))
+	fileDiags := make([]hcl.Diagnostics, len(paths))
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, runtime.GOMAXPROCS(0))
+	for i, path := range paths {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, path string) {
+			defer func() { <-sem; wg.Done() }()
+			files[i], fileDiags[i] = p.loadHCLFile(baseDir, path)
+		}(i, path)
 	}
+	wg.Wait()
+
+	var diags hcl.Diagnostics
+	for i := range paths {
+		diags = diags.Extend(fileDiags[i])
+		if fileDiags[i].HasErrors() {
+			files[i] = nil
+		}
+	}
+	return files, diags
 }
 
 // Sources returns a map of the cached source buffers for all files that
 // have been loaded through this parser, with source filenames (as requested
 // when each file was opened) as the keys.
 func (p *Parser) Sources() map[string][]byte {
-	return p.p.Sources()
+	p.filesMu.Lock()
+	defer p.filesMu.Unlock()
+
+	ret := make(map[string][]byte, len(p.files))
+	for fn, f := range p.files {
+		ret[fn] = f.Bytes
+	}
+	return ret
 }
 
 // Files returns a map of the cached HCL file objects for all files that
 // have been loaded through this parser, with source filenames (as requested
 // when each file was opened) as the keys.
 func (p *Parser) Files() map[string]*hcl.File {
-	return p.p.Files()
+	p.filesMu.Lock()
+	defer p.filesMu.Unlock()
+
+	ret := make(map[string]*hcl.File, len(p.files))
+	for fn, f := range p.files {
+		ret[fn] = f
+	}
+	return ret
 }
 
 // IsConfigDir determines whether the given path refers to a directory that
 // exists and contains at least one Terraform config file (with a .tf or
-// .tf.json extension.)
+// .tf.json extension, or .tofu/.tofu.json in OpenTofu.)
 func (p *Parser) IsConfigDir(baseDir, path string) bool {
 	primaryPaths, overridePaths, _ := p.configDirFiles(baseDir, path)
 	return (len(primaryPaths) + len(overridePaths)) > 0
@@ -254,6 +388,12 @@ func (p *Parser) configDirFiles(baseDir, dir string) (primary, override []string
 		})
 		return
 	}
+	lang := p.Language(dir)
+
+	names := map[string]bool{}
+	for _, info := range infos {
+		names[info.Name()] = true
+	}
 
 	for _, info := range infos {
 		if info.IsDir() {
@@ -262,12 +402,17 @@ func (p *Parser) configDirFiles(baseDir, dir string) (primary, override []string
 		}
 
 		name := info.Name()
-		ext := configFileExt(name)
+		ext := configFileExt(name, lang)
 		if ext == "" || isIgnoredFile(name) {
 			continue
 		}
 
 		baseName := name[:len(name)-len(ext)] // strip extension
+		// In OpenTofu, .tofu files shadow .tf files with the same name
+		if tofuExt := tofuFileExt(ext); lang == LanguageOpenTofu && tofuExt != "" && names[baseName+tofuExt] {
+			continue
+		}
+
 		isOverride := baseName == "override" || strings.HasSuffix(baseName, "_override")
 
 		fullPath := filepath.Join(dir, name)
@@ -281,6 +426,12 @@ func (p *Parser) configDirFiles(baseDir, dir string) (primary, override []string
 	return
 }
 
+const (
+	lockFilename         = ".terraform.lock.hcl"
+	openTofuRegistryHost = "registry.opentofu.org"
+	defaultTestDirectory = "tests"
+)
+
 func (p *Parser) autoLoadValuesDirFiles(baseDir, dir string) (files []string, diags hcl.Diagnostics) {
 	infos, err := p.fs.ReadDir(dir)
 	if err != nil {
@@ -315,16 +466,58 @@ func (p *Parser) autoLoadValuesDirFiles(baseDir, dir string) (files []string, di
 
 // configFileExt returns the Terraform configuration extension of the given
 // path, or a blank string if it is not a recognized extension.
-func configFileExt(path string) string {
+// The .tofu and .tofu.json extensions are only recognized in OpenTofu.
+func configFileExt(path string, lang Language) string {
 	if strings.HasSuffix(path, ".tf") {
 		return ".tf"
 	} else if strings.HasSuffix(path, ".tf.json") {
 		return ".tf.json"
+	} else if lang == LanguageOpenTofu && strings.HasSuffix(path, ".tofu") {
+		return ".tofu"
+	} else if lang == LanguageOpenTofu && strings.HasSuffix(path, ".tofu.json") {
+		return ".tofu.json"
 	} else {
 		return ""
 	}
 }
 
+// isTestFile determines if the file is a test file or a mock data file.
+// The .tofutest.hcl and .tofutest.json extensions are only recognized in OpenTofu.
+func isTestFile(path string, lang Language) bool {
+	if strings.HasSuffix(path, ".tftest.hcl") || strings.HasSuffix(path, ".tftest.json") {
+		return true
+	}
+	if lang == LanguageOpenTofu && (strings.HasSuffix(path, ".tofutest.hcl") || strings.HasSuffix(path, ".tofutest.json")) {
+		return true
+	}
+	return isMockDataFile(path)
+}
+
+// isMockDataFile determines if the file ends with .tfmock.hcl or .tfmock.json
+func isMockDataFile(path string) bool {
+	return strings.HasSuffix(path, ".tfmock.hcl") || strings.HasSuffix(path, ".tfmock.json")
+}
+
+// tofuFileExt returns the OpenTofu-specific extension corresponding to
+// the given Terraform extension, or a blank string if there is no such one.
+func tofuFileExt(ext string) string {
+	switch ext {
+	case ".tf":
+		return ".tofu"
+	case ".tf.json":
+		return ".tofu.json"
+	default:
+		return ""
+	}
+}
+
+// IsNativeSyntaxFile returns true if the given path is a configuration file
+// written in the HCL native syntax, i.e. a .tf or .tofu file, or a test file
+// written in the HCL native syntax.
+func IsNativeSyntaxFile(path string) bool {
+	return strings.HasSuffix(path, ".tf") || strings.HasSuffix(path, ".tofu") || strings.HasSuffix(path, ".hcl")
+}
+
 // isAutoVarFile determines if the file ends with .auto.tfvars or .auto.tfvars.json
 func isAutoVarFile(path string) bool {
 	return strings.HasSuffix(path, ".auto.tfvars") ||
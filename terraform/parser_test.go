@@ -15,11 +15,12 @@ import (
 
 func TestLoadConfigDir(t *testing.T) {
 	tests := []struct {
-		name    string
-		files   map[string]string
-		baseDir string
-		dir     string
-		want    *Module
+		name     string
+		files    map[string]string
+		language Language
+		baseDir  string
+		dir      string
+		want     *Module
 	}{
 		{
 			name: "HCL native files",
@@ -171,6 +172,66 @@ func TestLoadConfigDir(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "OpenTofu files",
+			files: map[string]string{
+				"main.tf":           "",
+				"main.tofu":         "",
+				"network.tf.json":   "{}",
+				"network.tofu.json": "{}",
+				"variables.tf":      "",
+				"override.tf":       "",
+				"override.tofu":     "",
+			},
+			baseDir: ".",
+			dir:     ".",
+			want: &Module{
+				SourceDir: ".",
+				primaries: map[string]*hcl.File{
+					"main.tofu":         {Body: hcltest.MockBody(&hcl.BodyContent{MissingItemRange: hcl.Range{Filename: "main.tofu"}})},
+					"network.tofu.json": {Body: hcltest.MockBody(&hcl.BodyContent{MissingItemRange: hcl.Range{Filename: "network.tofu.json"}})},
+					"variables.tf":      {Body: hcltest.MockBody(&hcl.BodyContent{MissingItemRange: hcl.Range{Filename: "variables.tf"}})},
+				},
+				overrides: map[string]*hcl.File{
+					"override.tofu": {Body: hcltest.MockBody(&hcl.BodyContent{MissingItemRange: hcl.Range{Filename: "override.tofu"}})},
+				},
+				Sources: map[string][]byte{
+					"main.tofu":         {},
+					"network.tofu.json": []byte("{}"),
+					"variables.tf":      {},
+					"override.tofu":     {},
+				},
+				Files: map[string]*hcl.File{
+					"main.tofu":         {Body: hcl.EmptyBody()},
+					"network.tofu.json": {Body: hcl.EmptyBody()},
+					"variables.tf":      {Body: hcl.EmptyBody()},
+					"override.tofu":     {Body: hcl.EmptyBody()},
+				},
+			},
+		},
+		{
+			name: "OpenTofu files in Terraform",
+			files: map[string]string{
+				"main.tf":   "",
+				"main.tofu": "",
+			},
+			language: LanguageTerraform,
+			baseDir:  ".",
+			dir:      ".",
+			want: &Module{
+				SourceDir: ".",
+				primaries: map[string]*hcl.File{
+					"main.tf": {Body: hcltest.MockBody(&hcl.BodyContent{MissingItemRange: hcl.Range{Filename: "main.tf"}})},
+				},
+				overrides: map[string]*hcl.File{},
+				Sources: map[string][]byte{
+					"main.tf": {},
+				},
+				Files: map[string]*hcl.File{
+					"main.tf": {Body: hcl.EmptyBody()},
+				},
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -182,6 +243,7 @@ func TestLoadConfigDir(t *testing.T) {
 				}
 			}
 			parser := NewParser(fs)
+			parser.SetLanguage(test.language)
 
 			mod, diags := parser.LoadConfigDir(test.baseDir, test.dir)
 			if diags.HasErrors() {
@@ -349,6 +411,113 @@ func TestLoadConfigDirFiles(t *testing.T) {
 	}
 }
 
+func TestLoadTestFiles(t *testing.T) {
+	tests := []struct {
+		name     string
+		files    map[string]string
+		language Language
+		baseDir  string
+		dir      string
+		want     []string
+	}{
+		{
+			name: "test files",
+			files: map[string]string{
+				"main.tf":                                    "",
+				"main.tftest.hcl":                            "",
+				"main.tftest.json":                           "{}",
+				filepath.Join("tests", "foo.tftest.hcl"):     "",
+				filepath.Join("tests", "aws.tfmock.hcl"):     "",
+				filepath.Join("tests", "google.tfmock.json"): "{}",
+				filepath.Join("tests", "README.md"):          "",
+			},
+			baseDir: ".",
+			dir:     ".",
+			want: []string{
+				"main.tftest.hcl",
+				"main.tftest.json",
+				filepath.Join("tests", "foo.tftest.hcl"),
+				filepath.Join("tests", "aws.tfmock.hcl"),
+				filepath.Join("tests", "google.tfmock.json"),
+			},
+		},
+		{
+			name: "without tests directory",
+			files: map[string]string{
+				"main.tf": "",
+			},
+			baseDir: ".",
+			dir:     ".",
+			want:    []string{},
+		},
+		{
+			name: "with basedir + dir",
+			files: map[string]string{
+				filepath.Join("bar", "main.tf"):                  "",
+				filepath.Join("bar", "tests", "main.tftest.hcl"): "",
+			},
+			baseDir: "foo",
+			dir:     "bar",
+			want: []string{
+				filepath.Join("foo", "bar", "tests", "main.tftest.hcl"),
+			},
+		},
+		{
+			name: "OpenTofu test files",
+			files: map[string]string{
+				"main.tf":           "",
+				"main.tofutest.hcl": "",
+				"main.tftest.hcl":   "",
+			},
+			language: LanguageOpenTofu,
+			baseDir:  ".",
+			dir:      ".",
+			want: []string{
+				"main.tofutest.hcl",
+				"main.tftest.hcl",
+			},
+		},
+		{
+			name: "OpenTofu test files in Terraform",
+			files: map[string]string{
+				"main.tf":           "",
+				"main.tofutest.hcl": "",
+			},
+			language: LanguageTerraform,
+			baseDir:  ".",
+			dir:      ".",
+			want:     []string{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fs := afero.Afero{Fs: afero.NewMemMapFs()}
+			for name, content := range test.files {
+				if err := fs.WriteFile(name, []byte(content), os.ModePerm); err != nil {
+					t.Fatal(err)
+				}
+			}
+			parser := NewParser(fs)
+			parser.SetLanguage(test.language)
+
+			files, diags := parser.LoadTestFiles(test.baseDir, test.dir)
+			if diags.HasErrors() {
+				t.Fatal(diags)
+			}
+
+			got := []string{}
+			for name := range files {
+				got = append(got, name)
+			}
+			opt := cmpopts.SortSlices(func(x, y string) bool { return x > y })
+			if diff := cmp.Diff(got, test.want, opt); diff != "" {
+				t.Errorf(diff)
+			}
+		})
+	}
+}
+
 func TestLoadValuesFile(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -416,11 +585,12 @@ func TestLoadValuesFile(t *testing.T) {
 
 func TestIsConfigDir(t *testing.T) {
 	tests := []struct {
-		name    string
-		files   map[string]string
-		baseDir string
-		dir     string
-		want    bool
+		name     string
+		files    map[string]string
+		language Language
+		baseDir  string
+		dir      string
+		want     bool
 	}{
 		{
 			name: "HCL native files (primary)",
@@ -467,6 +637,25 @@ func TestIsConfigDir(t *testing.T) {
 			dir:     ".",
 			want:    false,
 		},
+		{
+			name: "OpenTofu files",
+			files: map[string]string{
+				"main.tofu": "",
+			},
+			baseDir: ".",
+			dir:     ".",
+			want:    true,
+		},
+		{
+			name: "OpenTofu files in Terraform",
+			files: map[string]string{
+				"main.tofu": "",
+			},
+			language: LanguageTerraform,
+			baseDir:  ".",
+			dir:      ".",
+			want:     false,
+		},
 	}
 
 	for _, test := range tests {
@@ -478,6 +667,7 @@ func TestIsConfigDir(t *testing.T) {
 				}
 			}
 			parser := NewParser(fs)
+			parser.SetLanguage(test.language)
 
 			got := parser.IsConfigDir(test.baseDir, test.dir)
 
@@ -488,6 +678,87 @@ func TestIsConfigDir(t *testing.T) {
 	}
 }
 
+func TestLanguage(t *testing.T) {
+	tests := []struct {
+		name     string
+		files    map[string]string
+		language Language
+		want     Language
+	}{
+		{
+			name: "Terraform files",
+			files: map[string]string{
+				"main.tf": "",
+			},
+			want: LanguageTerraform,
+		},
+		{
+			name: "OpenTofu files",
+			files: map[string]string{
+				"main.tf":   "",
+				"main.tofu": "",
+			},
+			want: LanguageOpenTofu,
+		},
+		{
+			name: "OpenTofu JSON files",
+			files: map[string]string{
+				"main.tofu.json": "{}",
+			},
+			want: LanguageOpenTofu,
+		},
+		{
+			name: "OpenTofu lock file",
+			files: map[string]string{
+				"main.tf": "",
+				".terraform.lock.hcl": `
+provider "registry.opentofu.org/hashicorp/aws" {
+  version = "5.0.0"
+}`,
+			},
+			want: LanguageOpenTofu,
+		},
+		{
+			name: "Terraform lock file",
+			files: map[string]string{
+				"main.tf": "",
+				".terraform.lock.hcl": `
+provider "registry.terraform.io/hashicorp/aws" {
+  version = "5.0.0"
+}`,
+			},
+			want: LanguageTerraform,
+		},
+		{
+			name: "explicit language",
+			files: map[string]string{
+				"main.tofu": "",
+			},
+			language: LanguageTerraform,
+			want:     LanguageTerraform,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fs := afero.Afero{Fs: afero.NewMemMapFs()}
+			for name, content := range test.files {
+				if err := fs.WriteFile(name, []byte(content), os.ModePerm); err != nil {
+					t.Fatal(err)
+				}
+			}
+			parser := NewParser(fs)
+			parser.SetLanguage(test.language)
+
+			got := parser.Language(".")
+
+			if got != test.want {
+				t.Errorf("want=%s, got=%s", test.want, got)
+			}
+		})
+	}
+}
+
 func TestExists(t *testing.T) {
 	tests := []struct {
 		name  string
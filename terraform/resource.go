@@ -3,9 +3,11 @@ package terraform
 import (
 	"github.com/hashicorp/hcl/v2"
 	"github.com/terraform-linters/tflint-plugin-sdk/hclext"
+	"github.com/terraform-linters/tflint/terraform/addrs"
 )
 
 type Resource struct {
+	Mode addrs.ResourceMode
 	Name string
 	Type string
 
@@ -14,7 +16,13 @@ type Resource struct {
 }
 
 func decodeResourceBlock(block *hclext.Block) *Resource {
+	mode := addrs.ManagedResourceMode
+	if block.Type == "ephemeral" {
+		mode = addrs.EphemeralResourceMode
+	}
+
 	return &Resource{
+		Mode:      mode,
 		Type:      block.Labels[0],
 		Name:      block.Labels[1],
 		DeclRange: block.DefRange,
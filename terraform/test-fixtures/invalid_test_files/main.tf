@@ -0,0 +1,5 @@
+variable "bucket_prefix" {}
+
+resource "aws_s3_bucket" "bucket" {
+  bucket = "${var.bucket_prefix}-bucket"
+}
@@ -0,0 +1,5 @@
+run "valid_bucket_name" {
+  variables {
+    bucket_prefx = "test"
+  }
+}
@@ -0,0 +1,2 @@
+run "broken" {
+  command = plan
@@ -0,0 +1,3 @@
+module "consul_servers" {
+  source = "./modules/consul-cluster"
+}
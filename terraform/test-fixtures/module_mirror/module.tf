@@ -0,0 +1,8 @@
+module "consul" {
+  source  = "hashicorp/consul/aws"
+  version = "~> 0.9.0"
+}
+
+module "example" {
+  source = "git::https://github.com/terraform-linters/example.git//modules/foo?ref=v1.0.0"
+}
@@ -0,0 +1,7 @@
+module "foo" {
+  source = "./modules/child"
+}
+
+module "bar" {
+  source = "./modules/child/"
+}
@@ -0,0 +1,12 @@
+module "consul" {
+  source  = "hashicorp/consul/aws"
+  version = "~> 0.10"
+}
+
+module "example" {
+  source = "github.com/terraform-linters/example-v2"
+}
+
+module "vpc" {
+  source = "terraform-aws-modules/vpc/aws"
+}
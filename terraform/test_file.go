@@ -0,0 +1,189 @@
+package terraform
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/hcl/v2"
+)
+
+// TestFile represents a Terraform test file (.tftest.hcl or .tftest.json).
+// https://developer.hashicorp.com/terraform/language/tests
+type TestFile struct {
+	// Variables are the file-level variables applied to all run blocks.
+	Variables hcl.Attributes
+
+	Runs []*TestRun
+}
+
+// TestRun represents a run block in a test file.
+type TestRun struct {
+	Name string
+
+	// Variables are the run-level variables which override file-level variables.
+	Variables hcl.Attributes
+
+	// ModuleSourceSet is true if the run block has a module block. In this case,
+	// the run block tests an alternate module instead of the module under test.
+	ModuleSourceSet bool
+
+	Asserts []*TestAssert
+
+	DeclRange hcl.Range
+}
+
+// TestAssert represents an assert block in a run block.
+type TestAssert struct {
+	Condition    hcl.Expression
+	ErrorMessage hcl.Expression
+
+	DeclRange hcl.Range
+}
+
+func decodeTestFile(file *hcl.File) (*TestFile, hcl.Diagnostics) {
+	tf := &TestFile{Variables: hcl.Attributes{}}
+
+	content, diags := file.Body.Content(testFileSchema)
+
+	for _, block := range content.Blocks {
+		switch block.Type {
+		case "variables":
+			attrs, attrDiags := block.Body.JustAttributes()
+			diags = diags.Extend(attrDiags)
+			for name, attr := range attrs {
+				tf.Variables[name] = attr
+			}
+
+		case "run":
+			run, runDiags := decodeTestRunBlock(block)
+			diags = diags.Extend(runDiags)
+			tf.Runs = append(tf.Runs, run)
+		}
+	}
+
+	return tf, diags
+}
+
+func decodeTestRunBlock(block *hcl.Block) (*TestRun, hcl.Diagnostics) {
+	run := &TestRun{
+		Name:      block.Labels[0],
+		Variables: hcl.Attributes{},
+		DeclRange: block.DefRange,
+	}
+
+	content, diags := block.Body.Content(testRunBlockSchema)
+
+	for _, block := range content.Blocks {
+		switch block.Type {
+		case "variables":
+			attrs, attrDiags := block.Body.JustAttributes()
+			diags = diags.Extend(attrDiags)
+			for name, attr := range attrs {
+				run.Variables[name] = attr
+			}
+
+		case "module":
+			run.ModuleSourceSet = true
+
+		case "assert":
+			assertContent, assertDiags := block.Body.Content(testAssertBlockSchema)
+			diags = diags.Extend(assertDiags)
+			assert := &TestAssert{DeclRange: block.DefRange}
+			if attr, exists := assertContent.Attributes["condition"]; exists {
+				assert.Condition = attr.Expr
+			}
+			if attr, exists := assertContent.Attributes["error_message"]; exists {
+				assert.ErrorMessage = attr.Expr
+			}
+			run.Asserts = append(run.Asserts, assert)
+		}
+	}
+
+	return run, diags
+}
+
+// validate checks that the test file is consistent with the module under test.
+// Currently, it checks that variables passed by run blocks are declared in the module.
+//
+// File-level variables are not checked because they are shared by run blocks
+// that test alternate modules.
+func (f *TestFile) validate(mod *Module) hcl.Diagnostics {
+	var diags hcl.Diagnostics
+
+	for _, run := range f.Runs {
+		if run.ModuleSourceSet {
+			continue
+		}
+
+		names := make([]string, 0, len(run.Variables))
+		for name := range run.Variables {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			if _, declared := mod.Variables[name]; declared {
+				continue
+			}
+
+			suggestions := make([]string, 0, len(mod.Variables))
+			for k := range mod.Variables {
+				suggestions = append(suggestions, k)
+			}
+			suggestion := nameSuggestion(name, suggestions)
+			if suggestion != "" {
+				suggestion = fmt.Sprintf(" Did you mean %q?", suggestion)
+			}
+
+			attr := run.Variables[name]
+			diags = diags.Append(&hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Value for undeclared variable",
+				Detail:   fmt.Sprintf(`The run block %q assigns a value to the variable %q, but the module under test does not declare a variable of that name.%s`, run.Name, name, suggestion),
+				Subject:  attr.NameRange.Ptr(),
+			})
+		}
+	}
+
+	return diags
+}
+
+var testFileSchema = &hcl.BodySchema{
+	Blocks: []hcl.BlockHeaderSchema{
+		{Type: "run", LabelNames: []string{"name"}},
+		{Type: "variables"},
+		{Type: "provider", LabelNames: []string{"name"}},
+		{Type: "mock_provider", LabelNames: []string{"name"}},
+		{Type: "override_resource"},
+		{Type: "override_data"},
+		{Type: "override_module"},
+		{Type: "test"},
+	},
+}
+
+var testRunBlockSchema = &hcl.BodySchema{
+	Attributes: []hcl.AttributeSchema{
+		{Name: "command"},
+		{Name: "providers"},
+		{Name: "expect_failures"},
+		{Name: "state_key"},
+		{Name: "parallel"},
+		{Name: "skip_cleanup"},
+	},
+	Blocks: []hcl.BlockHeaderSchema{
+		{Type: "plan_options"},
+		{Type: "variables"},
+		{Type: "module"},
+		{Type: "assert"},
+		{Type: "override_resource"},
+		{Type: "override_data"},
+		{Type: "override_module"},
+	},
+}
+
+var testAssertBlockSchema = &hcl.BodySchema{
+	Attributes: []hcl.AttributeSchema{
+		{Name: "condition", Required: true},
+		{Name: "error_message", Required: true},
+	},
+}
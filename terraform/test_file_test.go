@@ -0,0 +1,139 @@
+package terraform
+
+import (
+	"os"
+	"testing"
+
+	"github.com/spf13/afero"
+)
+
+func TestBuildTests(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		runs  int
+		err   string
+	}{
+		{
+			name: "valid test file",
+			files: map[string]string{
+				"main.tf": `
+variable "bucket_prefix" {}
+
+resource "aws_s3_bucket" "bucket" {
+  bucket = "${var.bucket_prefix}-bucket"
+}`,
+				"main.tftest.hcl": `
+variables {
+  region = "us-east-1"
+}
+
+run "valid_bucket_name" {
+  command = plan
+
+  variables {
+    bucket_prefix = "test"
+  }
+
+  assert {
+    condition     = aws_s3_bucket.bucket.bucket == "test-bucket"
+    error_message = "S3 bucket name did not match expected"
+  }
+}
+
+run "setup" {
+  module {
+    source = "./testing/setup"
+  }
+
+  variables {
+    unknown = "ok"
+  }
+}`,
+			},
+			runs: 2,
+		},
+		{
+			name: "undeclared variable",
+			files: map[string]string{
+				"main.tf": `
+variable "bucket_prefix" {}`,
+				"main.tftest.hcl": `
+run "valid_bucket_name" {
+  variables {
+    bucket_prefx = "test"
+  }
+}`,
+			},
+			runs: 1,
+			err:  `main.tftest.hcl:4,5-17: Value for undeclared variable; The run block "valid_bucket_name" assigns a value to the variable "bucket_prefx", but the module under test does not declare a variable of that name. Did you mean "bucket_prefix"?`,
+		},
+		{
+			name: "unsupported argument in run block",
+			files: map[string]string{
+				"main.tf": "",
+				"main.tftest.hcl": `
+run "valid_bucket_name" {
+  comand = plan
+}`,
+			},
+			runs: 0,
+			err:  `main.tftest.hcl:3,3-9: Unsupported argument; An argument named "comand" is not expected here. Did you mean "command"?`,
+		},
+		{
+			name: "mock data file",
+			files: map[string]string{
+				"main.tf": "",
+				"aws.tfmock.hcl": `
+mock_resource "aws_s3_bucket" {
+  defaults = {
+    arn = "arn:aws:s3:::name"
+  }
+}`,
+			},
+			runs: 0,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fs := afero.Afero{Fs: afero.NewMemMapFs()}
+			for name, content := range test.files {
+				if err := fs.WriteFile(name, []byte(content), os.ModePerm); err != nil {
+					t.Fatal(err)
+				}
+			}
+			parser := NewParser(fs)
+
+			mod, diags := parser.LoadConfigDir(".", ".")
+			if diags.HasErrors() {
+				t.Fatal(diags)
+			}
+			files, diags := parser.LoadTestFiles(".", ".")
+			if diags.HasErrors() {
+				t.Fatal(diags)
+			}
+			for path, file := range files {
+				mod.TestSources[path] = file.Bytes
+				mod.TestFiles[path] = file
+			}
+
+			diags = mod.buildTests()
+			if diags.HasErrors() {
+				if diags.Error() != test.err {
+					t.Errorf("want=%s, got=%s", test.err, diags.Error())
+				}
+			} else if test.err != "" {
+				t.Errorf("want=%s, got no error", test.err)
+			}
+
+			runs := 0
+			for _, test := range mod.Tests {
+				runs += len(test.Runs)
+			}
+			if runs != test.runs {
+				t.Errorf("want=%d runs, got=%d runs", test.runs, runs)
+			}
+		})
+	}
+}
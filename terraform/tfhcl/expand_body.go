@@ -15,6 +15,7 @@ type expandBody struct {
 	ctx              *hcl.EvalContext
 	dynamicIteration *dynamicIteration // non-nil if we're nested inside a "dynamic" block
 	metaArgIteration *metaArgIteration // non-nil if we're nested inside a block with meta-arguments
+	expandProviders  bool              // true if provider blocks with for_each are expanded (OpenTofu)
 
 	// These are used with PartialContent to produce a "remaining items"
 	// body to return. They are nil on all bodies fresh out of the transformer.
@@ -68,6 +69,7 @@ func (b *expandBody) PartialContent(schema *hcl.BodySchema) (*hcl.BodyContent, h
 		ctx:              b.ctx,
 		dynamicIteration: b.dynamicIteration,
 		metaArgIteration: b.metaArgIteration,
+		expandProviders:  b.expandProviders,
 		hiddenAttrs:      make(map[string]struct{}),
 		hiddenBlocks:     make(map[string]hcl.BlockHeaderSchema),
 	}
@@ -184,6 +186,16 @@ func (b *expandBody) expandBlocks(schema *hcl.BodySchema, rawBlocks hcl.Blocks,
 			blocks = append(blocks, expandedBlocks...)
 			diags = append(diags, expandDiags...)
 
+		// Providers with for_each are supported in OpenTofu
+		case "provider":
+			if b.expandProviders {
+				expandedBlocks, expandDiags := b.expandMetaArgBlock(schema, rawBlock)
+				blocks = append(blocks, expandedBlocks...)
+				diags = append(diags, expandDiags...)
+			} else if _, hidden := b.hiddenBlocks[rawBlock.Type]; !hidden {
+				blocks = append(blocks, b.expandStaticBlock(rawBlock))
+			}
+
 		default:
 			if _, hidden := b.hiddenBlocks[rawBlock.Type]; !hidden {
 				blocks = append(blocks, b.expandStaticBlock(rawBlock))
@@ -326,6 +338,7 @@ func (b *expandBody) expandChild(child hcl.Body, i *dynamicIteration, mi *metaAr
 	ret := Expand(child, chiCtx)
 	ret.(*expandBody).dynamicIteration = i
 	ret.(*expandBody).metaArgIteration = mi
+	ret.(*expandBody).expandProviders = b.expandProviders
 	return ret
 }
 
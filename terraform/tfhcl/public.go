@@ -23,3 +23,15 @@ func Expand(body hcl.Body, ctx *hcl.EvalContext) hcl.Body {
 		ctx:      ctx,
 	}
 }
+
+// ExpandWithProviders is like Expand, but also expands provider blocks with
+// for_each. This is only supported in OpenTofu.
+//
+// https://opentofu.org/docs/language/providers/configuration/#for_each-multiple-instances-of-a-provider-configuration
+func ExpandWithProviders(body hcl.Body, ctx *hcl.EvalContext) hcl.Body {
+	return &expandBody{
+		original:        body,
+		ctx:             ctx,
+		expandProviders: true,
+	}
+}
@@ -24,6 +24,7 @@ type Variable struct {
 
 	ParsingMode VariableParsingMode
 	Sensitive   bool
+	Ephemeral   bool
 	Nullable    bool
 }
 
@@ -51,6 +52,11 @@ func decodeVairableBlock(block *hclext.Block) (*Variable, hcl.Diagnostics) {
 		diags = diags.Extend(valDiags)
 	}
 
+	if attr, exists := block.Body.Attributes["ephemeral"]; exists {
+		valDiags := gohcl.DecodeExpression(attr.Expr, nil, &v.Ephemeral)
+		diags = diags.Extend(valDiags)
+	}
+
 	if attr, exists := block.Body.Attributes["nullable"]; exists {
 		valDiags := gohcl.DecodeExpression(attr.Expr, nil, &v.Nullable)
 		diags = append(diags, valDiags...)
@@ -229,6 +235,9 @@ var variableBlockSchema = &hclext.BodySchema{
 		{
 			Name: "sensitive",
 		},
+		{
+			Name: "ephemeral",
+		},
 		{
 			Name: "nullable",
 		},
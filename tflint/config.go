@@ -3,9 +3,14 @@ package tflint
 import (
 	"fmt"
 	"log"
+	"net/url"
 	"os"
+	"path"
+	"path/filepath"
 	"strings"
+	"time"
 
+	"github.com/hashicorp/go-version"
 	hcl "github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/gohcl"
 	"github.com/hashicorp/hcl/v2/hclparse"
@@ -15,6 +20,7 @@ import (
 	"github.com/terraform-linters/tflint-plugin-sdk/hclext"
 	sdk "github.com/terraform-linters/tflint-plugin-sdk/tflint"
 	"github.com/terraform-linters/tflint/terraform"
+	"github.com/terraform-linters/tflint/terraform/addrs"
 )
 
 var defaultConfigFile = ".tflint.hcl"
@@ -33,6 +39,14 @@ var configSchema = &hcl.BodySchema{
 			Type:       "plugin",
 			LabelNames: []string{"name"},
 		},
+		{
+			Type:       "scenario",
+			LabelNames: []string{"name"},
+		},
+		{
+			Type:       "module",
+			LabelNames: []string{"name"},
+		},
 	},
 }
 
@@ -46,7 +60,13 @@ var innerConfigSchema = &hcl.BodySchema{
 		{Name: "variables"},
 		{Name: "disabled_by_default"},
 		{Name: "plugin_dir"},
+		{Name: "plugin_mirror"},
+		{Name: "plugin_env_isolation"},
 		{Name: "format"},
+		{Name: "language"},
+		{Name: "workspace"},
+		{Name: "module_mirror"},
+		{Name: "stale_module_manifest"},
 	},
 }
 
@@ -70,20 +90,52 @@ type Config struct {
 	DisabledByDefault    bool
 	DisabledByDefaultSet bool
 
-	PluginDir    string
-	PluginDirSet bool
+	// PluginDirs is a search path of plugin directories.
+	// Plugins are installed in the first directory.
+	PluginDirs    []string
+	PluginDirsSet bool
+
+	// PluginMirror is a directory to install plugins from
+	// instead of fetching them from the source.
+	PluginMirror    string
+	PluginMirrorSet bool
+
+	// PluginEnvIsolation launches plugins with only an allow-listed environment
+	// instead of inheriting the whole environment of TFLint.
+	PluginEnvIsolation    bool
+	PluginEnvIsolationSet bool
 
 	Format    string
 	FormatSet bool
 
+	Language    terraform.Language
+	LanguageSet bool
+
+	// Workspace overrides the current workspace if set.
+	Workspace    string
+	WorkspaceSet bool
+
+	// ModuleMirror is a directory to resolve remote modules
+	// that are not installed by "terraform init".
+	ModuleMirror    string
+	ModuleMirrorSet bool
+
+	StaleModuleManifest    terraform.StaleModuleManifest
+	StaleModuleManifestSet bool
+
 	Varfiles      []string
 	Variables     []string
 	Only          []string
 	IgnoreModules map[string]bool
 	Rules         map[string]*RuleConfig
 	Plugins       map[string]*PluginConfig
+	Scenarios     []*ScenarioConfig
+	// Modules are configs for module calls, keyed by module call addresses.
+	Modules map[string]*ModuleConfig
 
 	sources map[string][]byte
+	// ignoreModulePatterns are parsed patterns of IgnoreModules, keyed by raw patterns.
+	ignoreModulePatterns map[string]*modulePattern
 }
 
 // RuleConfig is a TFLint's rule config
@@ -95,18 +147,86 @@ type RuleConfig struct {
 
 // PluginConfig is a TFLint's plugin config
 type PluginConfig struct {
-	Name       string `hcl:"name,label"`
-	Enabled    bool   `hcl:"enabled"`
-	Version    string `hcl:"version,optional"`
-	Source     string `hcl:"source,optional"`
-	SigningKey string `hcl:"signing_key,optional"`
+	Name         string `hcl:"name,label"`
+	Enabled      bool   `hcl:"enabled"`
+	Version      string `hcl:"version,optional"`
+	Source       string `hcl:"source,optional"`
+	SigningKey   string `hcl:"signing_key,optional"`
+	ChecksumsURL string `hcl:"checksums_url,optional"`
+	Timeout      string `hcl:"timeout,optional"`
+
+	// Env is environment variables set to the plugin process.
+	Env map[string]string `hcl:"env,optional"`
+	// EnvPassthrough is names of environment variables passed to the plugin process.
+	// If set, the plugin does not inherit other environment variables.
+	EnvPassthrough []string `hcl:"env_passthrough,optional"`
+	// Args is extra command line arguments passed to the plugin process.
+	Args []string `hcl:"args,optional"`
 
 	Body hcl.Body `hcl:",remain"`
 
 	// Parsed source attributes
+	SourceType  PluginSourceType
 	SourceHost  string
 	SourceOwner string
 	SourceRepo  string
+
+	// Parsed timeout attribute. Zero means no timeout.
+	CheckTimeout time.Duration
+}
+
+// VersionConstraints returns the version constraints if the version is not an exact
+// version like "0.1.0" but constraints like "~> 0.1". Otherwise, it returns nil.
+func (c *PluginConfig) VersionConstraints() version.Constraints {
+	if c.Version == "" {
+		return nil
+	}
+	if _, err := version.NewVersion(c.Version); err == nil {
+		return nil
+	}
+	constraints, err := version.NewConstraint(c.Version)
+	if err != nil {
+		return nil
+	}
+	return constraints
+}
+
+// PluginSourceType is the kind of location that plugins are installed from.
+type PluginSourceType int
+
+const (
+	// PluginSourceGitHub is a GitHub repository like "github.com/owner/repo".
+	PluginSourceGitHub PluginSourceType = iota
+	// PluginSourceURL is an HTTPS URL template of the release asset.
+	PluginSourceURL
+	// PluginSourceFile is a file URL template of the release asset.
+	PluginSourceFile
+	// PluginSourceDir is a local directory that contains release assets.
+	PluginSourceDir
+)
+
+// ScenarioConfig is a TFLint's scenario config.
+// A scenario is a set of variables and a workspace to inspect the module.
+type ScenarioConfig struct {
+	Name      string   `hcl:"name,label"`
+	Varfiles  []string `hcl:"varfile,optional"`
+	Variables []string `hcl:"variables,optional"`
+	Workspace string   `hcl:"workspace,optional"`
+}
+
+// ModuleConfig is a TFLint's module config.
+// It controls rules for issues that come through the module call.
+// The name is the module call address (e.g. "module.network.module.vpc").
+// The "module." prefix can be omitted for a module call in the root module (e.g. "vpc").
+type ModuleConfig struct {
+	Name  string              `hcl:"name,label"`
+	Rules []*ModuleRuleConfig `hcl:"rule,block"`
+}
+
+// ModuleRuleConfig is a rule config in a module config.
+type ModuleRuleConfig struct {
+	Name    string `hcl:"name,label"`
+	Enabled bool   `hcl:"enabled"`
 }
 
 // EmptyConfig returns default config
@@ -266,6 +386,14 @@ func loadConfig(file afero.File) (*Config, error) {
 					if err := gohcl.DecodeExpression(attr.Expr, nil, &config.IgnoreModules); err != nil {
 						return config, err
 					}
+					config.ignoreModulePatterns = map[string]*modulePattern{}
+					for raw := range config.IgnoreModules {
+						pattern, err := parseModulePattern(raw)
+						if err != nil {
+							return config, err
+						}
+						config.ignoreModulePatterns[raw] = pattern
+					}
 
 				case "varfile":
 					if err := gohcl.DecodeExpression(attr.Expr, nil, &config.Varfiles); err != nil {
@@ -284,8 +412,24 @@ func loadConfig(file afero.File) (*Config, error) {
 					}
 
 				case "plugin_dir":
-					config.PluginDirSet = true
-					if err := gohcl.DecodeExpression(attr.Expr, nil, &config.PluginDir); err != nil {
+					config.PluginDirsSet = true
+					// Both a single directory and a list of directories are accepted
+					var dir string
+					if diags := gohcl.DecodeExpression(attr.Expr, nil, &dir); !diags.HasErrors() {
+						config.PluginDirs = []string{dir}
+					} else if err := gohcl.DecodeExpression(attr.Expr, nil, &config.PluginDirs); err != nil {
+						return config, err
+					}
+
+				case "plugin_mirror":
+					config.PluginMirrorSet = true
+					if err := gohcl.DecodeExpression(attr.Expr, nil, &config.PluginMirror); err != nil {
+						return config, err
+					}
+
+				case "plugin_env_isolation":
+					config.PluginEnvIsolationSet = true
+					if err := gohcl.DecodeExpression(attr.Expr, nil, &config.PluginEnvIsolation); err != nil {
 						return config, err
 					}
 
@@ -305,6 +449,40 @@ func loadConfig(file afero.File) (*Config, error) {
 						return config, fmt.Errorf("%s is invalid format. Allowed formats are: %s", config.Format, strings.Join(validFormats, ", "))
 					}
 
+				case "language":
+					var language string
+					config.LanguageSet = true
+					if err := gohcl.DecodeExpression(attr.Expr, nil, &language); err != nil {
+						return config, err
+					}
+					config.Language, err = terraform.AsLanguage(language)
+					if err != nil {
+						return config, err
+					}
+
+				case "workspace":
+					config.WorkspaceSet = true
+					if err := gohcl.DecodeExpression(attr.Expr, nil, &config.Workspace); err != nil {
+						return config, err
+					}
+
+				case "module_mirror":
+					config.ModuleMirrorSet = true
+					if err := gohcl.DecodeExpression(attr.Expr, nil, &config.ModuleMirror); err != nil {
+						return config, err
+					}
+
+				case "stale_module_manifest":
+					var staleModuleManifest string
+					config.StaleModuleManifestSet = true
+					if err := gohcl.DecodeExpression(attr.Expr, nil, &staleModuleManifest); err != nil {
+						return config, err
+					}
+					config.StaleModuleManifest, err = terraform.AsStaleModuleManifest(staleModuleManifest)
+					if err != nil {
+						return config, err
+					}
+
 				default:
 					panic("never happened")
 				}
@@ -327,6 +505,34 @@ func loadConfig(file afero.File) (*Config, error) {
 			}
 			config.Plugins[block.Labels[0]] = pluginConfig
 
+		case "scenario":
+			scenarioConfig := &ScenarioConfig{Name: block.Labels[0]}
+			if err := gohcl.DecodeBody(block.Body, nil, scenarioConfig); err != nil {
+				return config, err
+			}
+			for _, scenario := range config.Scenarios {
+				if scenario.Name == scenarioConfig.Name {
+					return config, fmt.Errorf(`scenario "%s" is declared more than once`, scenarioConfig.Name)
+				}
+			}
+			config.Scenarios = append(config.Scenarios, scenarioConfig)
+
+		case "module":
+			moduleConfig := &ModuleConfig{Name: block.Labels[0]}
+			if err := gohcl.DecodeBody(block.Body, nil, moduleConfig); err != nil {
+				return config, err
+			}
+			if !validModuleCallAddr(moduleConfig.Name) {
+				return config, fmt.Errorf(`module "%s" is not a valid module call address`, moduleConfig.Name)
+			}
+			if _, exists := config.Modules[moduleConfig.Name]; exists {
+				return config, fmt.Errorf(`module "%s" is declared more than once`, moduleConfig.Name)
+			}
+			if config.Modules == nil {
+				config.Modules = map[string]*ModuleConfig{}
+			}
+			config.Modules[moduleConfig.Name] = moduleConfig
+
 		default:
 			panic("never happened")
 		}
@@ -339,10 +545,22 @@ func loadConfig(file afero.File) (*Config, error) {
 	log.Printf("[DEBUG]   ForceSet: %t", config.ForceSet)
 	log.Printf("[DEBUG]   DisabledByDefault: %t", config.DisabledByDefault)
 	log.Printf("[DEBUG]   DisabledByDefaultSet: %t", config.DisabledByDefaultSet)
-	log.Printf("[DEBUG]   PluginDir: %s", config.PluginDir)
-	log.Printf("[DEBUG]   PluginDirSet: %t", config.PluginDirSet)
+	log.Printf("[DEBUG]   PluginDirs: %s", strings.Join(config.PluginDirs, ", "))
+	log.Printf("[DEBUG]   PluginDirsSet: %t", config.PluginDirsSet)
+	log.Printf("[DEBUG]   PluginMirror: %s", config.PluginMirror)
+	log.Printf("[DEBUG]   PluginMirrorSet: %t", config.PluginMirrorSet)
+	log.Printf("[DEBUG]   PluginEnvIsolation: %t", config.PluginEnvIsolation)
+	log.Printf("[DEBUG]   PluginEnvIsolationSet: %t", config.PluginEnvIsolationSet)
 	log.Printf("[DEBUG]   Format: %s", config.Format)
 	log.Printf("[DEBUG]   FormatSet: %t", config.FormatSet)
+	log.Printf("[DEBUG]   Language: %s", config.Language)
+	log.Printf("[DEBUG]   LanguageSet: %t", config.LanguageSet)
+	log.Printf("[DEBUG]   Workspace: %s", config.Workspace)
+	log.Printf("[DEBUG]   WorkspaceSet: %t", config.WorkspaceSet)
+	log.Printf("[DEBUG]   ModuleMirror: %s", config.ModuleMirror)
+	log.Printf("[DEBUG]   ModuleMirrorSet: %t", config.ModuleMirrorSet)
+	log.Printf("[DEBUG]   StaleModuleManifest: %s", config.StaleModuleManifest)
+	log.Printf("[DEBUG]   StaleModuleManifestSet: %t", config.StaleModuleManifestSet)
 	log.Printf("[DEBUG]   Varfiles: %s", strings.Join(config.Varfiles, ", "))
 	log.Printf("[DEBUG]   Variables: %s", strings.Join(config.Variables, ", "))
 	log.Printf("[DEBUG]   Only: %s", strings.Join(config.Only, ", "))
@@ -358,6 +576,16 @@ func loadConfig(file afero.File) (*Config, error) {
 	for name, plugin := range config.Plugins {
 		log.Printf("[DEBUG]     %s: enabled=%t, version=%s, source=%s", name, plugin.Enabled, plugin.Version, plugin.Source)
 	}
+	log.Printf("[DEBUG]   Scenarios:")
+	for _, scenario := range config.Scenarios {
+		log.Printf("[DEBUG]     %s: varfile=%s, variables=%s, workspace=%s", scenario.Name, strings.Join(scenario.Varfiles, ", "), strings.Join(scenario.Variables, ", "), scenario.Workspace)
+	}
+	log.Printf("[DEBUG]   Modules:")
+	for name, module := range config.Modules {
+		for _, rule := range module.Rules {
+			log.Printf("[DEBUG]     %s: %s: %t", name, rule.Name, rule.Enabled)
+		}
+	}
 
 	return config, nil
 }
@@ -428,14 +656,38 @@ func (c *Config) Merge(other *Config) {
 		c.DisabledByDefaultSet = true
 		c.DisabledByDefault = other.DisabledByDefault
 	}
-	if other.PluginDirSet {
-		c.PluginDirSet = true
-		c.PluginDir = other.PluginDir
+	if other.PluginDirsSet {
+		c.PluginDirsSet = true
+		c.PluginDirs = other.PluginDirs
+	}
+	if other.PluginMirrorSet {
+		c.PluginMirrorSet = true
+		c.PluginMirror = other.PluginMirror
+	}
+	if other.PluginEnvIsolationSet {
+		c.PluginEnvIsolationSet = true
+		c.PluginEnvIsolation = other.PluginEnvIsolation
 	}
 	if other.FormatSet {
 		c.FormatSet = true
 		c.Format = other.Format
 	}
+	if other.LanguageSet {
+		c.LanguageSet = true
+		c.Language = other.Language
+	}
+	if other.WorkspaceSet {
+		c.WorkspaceSet = true
+		c.Workspace = other.Workspace
+	}
+	if other.ModuleMirrorSet {
+		c.ModuleMirrorSet = true
+		c.ModuleMirror = other.ModuleMirror
+	}
+	if other.StaleModuleManifestSet {
+		c.StaleModuleManifestSet = true
+		c.StaleModuleManifest = other.StaleModuleManifest
+	}
 
 	c.Varfiles = append(c.Varfiles, other.Varfiles...)
 	c.Variables = append(c.Variables, other.Variables...)
@@ -443,6 +695,13 @@ func (c *Config) Merge(other *Config) {
 
 	for name, ignore := range other.IgnoreModules {
 		c.IgnoreModules[name] = ignore
+		// Invalid patterns are not stored here and are reported by IsModuleIgnored
+		if pattern, err := other.ignoreModulePattern(name); err == nil {
+			if c.ignoreModulePatterns == nil {
+				c.ignoreModulePatterns = map[string]*modulePattern{}
+			}
+			c.ignoreModulePatterns[name] = pattern
+		}
 	}
 
 	for name, rule := range other.Rules {
@@ -464,6 +723,112 @@ func (c *Config) Merge(other *Config) {
 			c.Plugins[name] = plugin
 		}
 	}
+
+	for _, scenario := range other.Scenarios {
+		replaced := false
+		for i, s := range c.Scenarios {
+			if s.Name == scenario.Name {
+				c.Scenarios[i] = scenario
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			c.Scenarios = append(c.Scenarios, scenario)
+		}
+	}
+
+	for name, module := range other.Modules {
+		if c.Modules == nil {
+			c.Modules = map[string]*ModuleConfig{}
+		}
+		c.Modules[name] = module
+	}
+}
+
+// IsModuleIgnored returns true if any of the passed targets (e.g. the module source or
+// the module call address) matches the patterns in "ignore_module".
+func (c *Config) IsModuleIgnored(targets ...string) (bool, error) {
+	for raw, ignore := range c.IgnoreModules {
+		if !ignore {
+			continue
+		}
+		pattern, err := c.ignoreModulePattern(raw)
+		if err != nil {
+			return false, err
+		}
+		if pattern.match(targets...) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// ignoreModulePattern returns the pattern parsed when the config is loaded.
+// Patterns set directly to IgnoreModules are parsed here.
+func (c *Config) ignoreModulePattern(raw string) (*modulePattern, error) {
+	if pattern, exists := c.ignoreModulePatterns[raw]; exists {
+		return pattern, nil
+	}
+	return parseModulePattern(raw)
+}
+
+// IsRuleDisabledInModules returns true if the passed rule is disabled
+// in the module call of the passed module path or any of its ancestors.
+func (c *Config) IsRuleDisabledInModules(ruleName string, path addrs.Module) bool {
+	if path.IsRoot() {
+		return false
+	}
+	addr := path.String()
+
+	for name, module := range c.Modules {
+		callAddr := name
+		if !strings.HasPrefix(callAddr, "module.") {
+			callAddr = "module." + callAddr
+		}
+		if addr != callAddr && !strings.HasPrefix(addr, callAddr+".") {
+			continue
+		}
+		for _, rule := range module.Rules {
+			if rule.Name == ruleName && !rule.Enabled {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// validModuleCallAddr returns true if the passed name is a module call address
+// like "module.network.module.vpc", or a module call name like "vpc".
+func validModuleCallAddr(name string) bool {
+	if hclsyntax.ValidIdentifier(name) {
+		return true
+	}
+
+	parts := strings.Split(name, ".")
+	if len(parts)%2 != 0 {
+		return false
+	}
+	for i := 0; i < len(parts); i += 2 {
+		if parts[i] != "module" || !hclsyntax.ValidIdentifier(parts[i+1]) {
+			return false
+		}
+	}
+	return true
+}
+
+// ForScenario returns a copy of the config to which the passed scenario is applied.
+// Variables in the scenario take precedence over variables in the config.
+func (c *Config) ForScenario(scenario *ScenarioConfig) *Config {
+	ret := *c
+
+	ret.Varfiles = append(append([]string{}, c.Varfiles...), scenario.Varfiles...)
+	ret.Variables = append(append([]string{}, c.Variables...), scenario.Variables...)
+	if scenario.Workspace != "" {
+		ret.Workspace = scenario.Workspace
+	}
+
+	return &ret
 }
 
 // ToPluginConfig converts self into the plugin configuration format
@@ -527,6 +892,13 @@ func (c *Config) ValidateRules(rulesets ...RuleSet) error {
 			return fmt.Errorf("Rule not found: %s", rule.Name)
 		}
 	}
+	for _, module := range c.Modules {
+		for _, rule := range module.Rules {
+			if _, exists := rulesMap[rule.Name]; !exists {
+				return fmt.Errorf(`Rule not found: %s (in module "%s")`, rule.Name, module.Name)
+			}
+		}
+	}
 
 	return nil
 }
@@ -540,16 +912,81 @@ func (c *PluginConfig) validate() error {
 		if c.Version == "" {
 			return fmt.Errorf(`plugin "%s": "version" attribute cannot be omitted when specifying "source"`, c.Name)
 		}
+		if _, err := version.NewVersion(c.Version); err != nil {
+			if _, err := version.NewConstraint(c.Version); err != nil {
+				return fmt.Errorf(`plugin "%s": "version" must be a version like "0.1.0" or version constraints like "~> 0.1"`, c.Name)
+			}
+		}
+
+		switch {
+		case strings.HasPrefix(c.Source, "https://"):
+			u, err := url.Parse(c.Source)
+			if err != nil || u.Host == "" {
+				return fmt.Errorf(`plugin "%s": "source" is an invalid URL`, c.Name)
+			}
+			c.SourceType = PluginSourceURL
+			c.SourceHost = u.Host
+
+		case strings.HasPrefix(c.Source, "file://"):
+			u, err := url.Parse(c.Source)
+			if err != nil || u.Path == "" || (u.Host != "" && u.Host != "localhost") {
+				return fmt.Errorf(`plugin "%s": "source" is an invalid file URL. Must be in the format "file:///path/to/archive.zip"`, c.Name)
+			}
+			c.SourceType = PluginSourceFile
+
+		case strings.Contains(c.Source, "://"):
+			return fmt.Errorf(`plugin "%s": "source" is invalid. Only "https://" and "file://" URLs are supported`, c.Name)
+
+		case strings.HasPrefix(c.Source, "./") || strings.HasPrefix(c.Source, "../") || filepath.IsAbs(c.Source) || strings.HasPrefix(c.Source, "/"):
+			c.SourceType = PluginSourceDir
 
-		parts := strings.Split(c.Source, "/")
-		// Expected `github.com/owner/repo` format
-		if len(parts) != 3 {
-			return fmt.Errorf(`plugin "%s": "source" is invalid. Must be a GitHub reference in the format "${host}/${owner}/${repo}"`, c.Name)
+		default:
+			parts := strings.Split(c.Source, "/")
+			// Expected `github.com/owner/repo` format
+			if len(parts) != 3 {
+				return fmt.Errorf(`plugin "%s": "source" is invalid. Must be a GitHub reference in the format "${host}/${owner}/${repo}"`, c.Name)
+			}
+
+			c.SourceType = PluginSourceGitHub
+			c.SourceHost = parts[0]
+			c.SourceOwner = parts[1]
+			c.SourceRepo = parts[2]
 		}
+	}
+
+	if c.VersionConstraints() != nil && c.SourceType != PluginSourceGitHub {
+		return fmt.Errorf(`plugin "%s": version constraints can only be used with GitHub sources`, c.Name)
+	}
+
+	if c.Timeout != "" {
+		timeout, err := time.ParseDuration(c.Timeout)
+		if err != nil || timeout <= 0 {
+			return fmt.Errorf(`plugin "%s": "timeout" must be a positive duration like "30s" or "5m"`, c.Name)
+		}
+		c.CheckTimeout = timeout
+	}
 
-		c.SourceHost = parts[0]
-		c.SourceOwner = parts[1]
-		c.SourceRepo = parts[2]
+	for _, name := range c.EnvPassthrough {
+		if _, err := path.Match(name, ""); err != nil || name == "" || strings.Contains(name, "=") {
+			return fmt.Errorf(`plugin "%s": "env_passthrough" must be a list of environment variable names like "AWS_REGION" or "AWS_*"`, c.Name)
+		}
+	}
+	for name := range c.Env {
+		if name == "" || strings.Contains(name, "=") {
+			return fmt.Errorf(`plugin "%s": "env" has an invalid environment variable name "%s"`, c.Name, name)
+		}
+	}
+
+	if c.ChecksumsURL != "" {
+		if c.SourceType != PluginSourceURL && c.SourceType != PluginSourceFile {
+			return fmt.Errorf(`plugin "%s": "checksums_url" can only be used with "https://" or "file://" sources`, c.Name)
+		}
+		if !strings.HasPrefix(c.ChecksumsURL, "https://") && !strings.HasPrefix(c.ChecksumsURL, "file://") {
+			return fmt.Errorf(`plugin "%s": "checksums_url" must be an "https://" or "file://" URL`, c.Name)
+		}
+		if _, err := url.Parse(c.ChecksumsURL); err != nil {
+			return fmt.Errorf(`plugin "%s": "checksums_url" is an invalid URL`, c.Name)
+		}
 	}
 
 	return nil
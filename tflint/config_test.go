@@ -4,6 +4,7 @@ import (
 	"errors"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
@@ -13,6 +14,7 @@ import (
 	"github.com/terraform-linters/tflint-plugin-sdk/hclext"
 	sdk "github.com/terraform-linters/tflint-plugin-sdk/tflint"
 	"github.com/terraform-linters/tflint/terraform"
+	"github.com/terraform-linters/tflint/terraform/addrs"
 )
 
 func TestLoadConfig(t *testing.T) {
@@ -35,6 +37,12 @@ func TestLoadConfig(t *testing.T) {
 config {
 	format = "compact"
 	plugin_dir = "~/.tflint.d/plugins"
+	plugin_mirror = "vendor/plugins"
+	plugin_env_isolation = true
+	language = "opentofu"
+	workspace = "production"
+	module_mirror = "vendor/modules"
+	stale_module_manifest = "error"
 
 	call_module_type = "all"
 	force = true
@@ -81,13 +89,25 @@ plugin "baz" {
 				IgnoreModules: map[string]bool{
 					"github.com/terraform-linters/example-module": true,
 				},
-				Varfiles:          []string{"example1.tfvars", "example2.tfvars"},
-				Variables:         []string{"foo=bar", "bar=['foo']"},
-				DisabledByDefault: false,
-				PluginDir:         "~/.tflint.d/plugins",
-				PluginDirSet:      true,
-				Format:            "compact",
-				FormatSet:         true,
+				Varfiles:               []string{"example1.tfvars", "example2.tfvars"},
+				Variables:              []string{"foo=bar", "bar=['foo']"},
+				DisabledByDefault:      false,
+				PluginDirs:             []string{"~/.tflint.d/plugins"},
+				PluginDirsSet:          true,
+				PluginMirror:           "vendor/plugins",
+				PluginMirrorSet:        true,
+				PluginEnvIsolation:     true,
+				PluginEnvIsolationSet:  true,
+				Format:                 "compact",
+				FormatSet:              true,
+				Language:               terraform.LanguageOpenTofu,
+				LanguageSet:            true,
+				Workspace:              "production",
+				WorkspaceSet:           true,
+				ModuleMirror:           "vendor/modules",
+				ModuleMirrorSet:        true,
+				StaleModuleManifest:    terraform.StaleModuleManifestError,
+				StaleModuleManifestSet: true,
 				Rules: map[string]*RuleConfig{
 					"aws_instance_invalid_type": {
 						Name:    "aws_instance_invalid_type",
@@ -278,6 +298,19 @@ config {
 				return err == nil || err.Error() != "invalid is invalid format. Allowed formats are: default, json, checkstyle, junit, compact, sarif"
 			},
 		},
+		{
+			name: "invalid language",
+			file: "invalid_language.hcl",
+			files: map[string]string{
+				"invalid_language.hcl": `
+config {
+	language = "invalid"
+}`,
+			},
+			errCheck: func(err error) bool {
+				return err == nil || err.Error() != "invalid is invalid language. Allowed values are: auto, terraform, opentofu"
+			},
+		},
 		{
 			name: "invalid call_module_type",
 			file: "invalid_call_module_type.hcl",
@@ -375,6 +408,311 @@ plugin "foo" {
 			},
 			errCheck: neverHappend,
 		},
+		{
+			name: "plugin with HTTPS source",
+			file: "plugin_with_https_source.hcl",
+			files: map[string]string{
+				"plugin_with_https_source.hcl": `
+plugin "foo" {
+	enabled = true
+
+	version       = "0.1.0"
+	source        = "https://artifactory.example.com/tflint/{name}_{version}_{os}_{arch}.zip"
+	checksums_url = "https://artifactory.example.com/tflint/{name}_{version}_checksums.txt"
+}`,
+			},
+			want: &Config{
+				CallModuleType:    terraform.CallLocalModule,
+				Force:             false,
+				IgnoreModules:     map[string]bool{},
+				Varfiles:          []string{},
+				Variables:         []string{},
+				DisabledByDefault: false,
+				Rules:             map[string]*RuleConfig{},
+				Plugins: map[string]*PluginConfig{
+					"foo": {
+						Name:         "foo",
+						Enabled:      true,
+						Version:      "0.1.0",
+						Source:       "https://artifactory.example.com/tflint/{name}_{version}_{os}_{arch}.zip",
+						ChecksumsURL: "https://artifactory.example.com/tflint/{name}_{version}_checksums.txt",
+						SourceType:   PluginSourceURL,
+						SourceHost:   "artifactory.example.com",
+					},
+					"terraform": {
+						Name:    "terraform",
+						Enabled: true,
+					},
+				},
+			},
+			errCheck: neverHappend,
+		},
+		{
+			name: "plugin with local directory source",
+			file: "plugin_with_local_directory_source.hcl",
+			files: map[string]string{
+				"plugin_with_local_directory_source.hcl": `
+plugin "foo" {
+	enabled = true
+
+	version = "0.1.0"
+	source  = "./plugins/foo"
+}`,
+			},
+			want: &Config{
+				CallModuleType:    terraform.CallLocalModule,
+				Force:             false,
+				IgnoreModules:     map[string]bool{},
+				Varfiles:          []string{},
+				Variables:         []string{},
+				DisabledByDefault: false,
+				Rules:             map[string]*RuleConfig{},
+				Plugins: map[string]*PluginConfig{
+					"foo": {
+						Name:       "foo",
+						Enabled:    true,
+						Version:    "0.1.0",
+						Source:     "./plugins/foo",
+						SourceType: PluginSourceDir,
+					},
+					"terraform": {
+						Name:    "terraform",
+						Enabled: true,
+					},
+				},
+			},
+			errCheck: neverHappend,
+		},
+		{
+			name: "plugin with version constraints",
+			file: "plugin_with_version_constraints.hcl",
+			files: map[string]string{
+				"plugin_with_version_constraints.hcl": `
+plugin "foo" {
+	enabled = true
+
+	version = ">= 0.29, < 1.0"
+	source  = "github.com/foo/bar"
+}`,
+			},
+			want: &Config{
+				CallModuleType:    terraform.CallLocalModule,
+				Force:             false,
+				IgnoreModules:     map[string]bool{},
+				Varfiles:          []string{},
+				Variables:         []string{},
+				DisabledByDefault: false,
+				Rules:             map[string]*RuleConfig{},
+				Plugins: map[string]*PluginConfig{
+					"foo": {
+						Name:        "foo",
+						Enabled:     true,
+						Version:     ">= 0.29, < 1.0",
+						Source:      "github.com/foo/bar",
+						SourceHost:  "github.com",
+						SourceOwner: "foo",
+						SourceRepo:  "bar",
+					},
+					"terraform": {
+						Name:    "terraform",
+						Enabled: true,
+					},
+				},
+			},
+			errCheck: neverHappend,
+		},
+		{
+			name: "plugin with invalid version",
+			file: "plugin_with_invalid_version.hcl",
+			files: map[string]string{
+				"plugin_with_invalid_version.hcl": `
+plugin "foo" {
+	enabled = true
+
+	version = "latest"
+	source  = "github.com/foo/bar"
+}`,
+			},
+			errCheck: func(err error) bool {
+				return err == nil || err.Error() != `plugin "foo": "version" must be a version like "0.1.0" or version constraints like "~> 0.1"`
+			},
+		},
+		{
+			name: "plugin with version constraints and HTTPS source",
+			file: "plugin_with_version_constraints_and_https_source.hcl",
+			files: map[string]string{
+				"plugin_with_version_constraints_and_https_source.hcl": `
+plugin "foo" {
+	enabled = true
+
+	version = "~> 0.1"
+	source  = "https://example.com/{name}_{version}_{os}_{arch}.zip"
+}`,
+			},
+			errCheck: func(err error) bool {
+				return err == nil || err.Error() != `plugin "foo": version constraints can only be used with GitHub sources`
+			},
+		},
+		{
+			name: "plugin with unsupported URL scheme",
+			file: "plugin_with_unsupported_url_scheme.hcl",
+			files: map[string]string{
+				"plugin_with_unsupported_url_scheme.hcl": `
+plugin "foo" {
+	enabled = true
+
+	version = "0.1.0"
+	source  = "http://artifactory.example.com/tflint/{name}_{os}_{arch}.zip"
+}`,
+			},
+			errCheck: func(err error) bool {
+				return err == nil || err.Error() != `plugin "foo": "source" is invalid. Only "https://" and "file://" URLs are supported`
+			},
+		},
+		{
+			name: "plugin with checksums_url and GitHub source",
+			file: "plugin_with_checksums_url_and_github_source.hcl",
+			files: map[string]string{
+				"plugin_with_checksums_url_and_github_source.hcl": `
+plugin "foo" {
+	enabled = true
+
+	version       = "0.1.0"
+	source        = "github.com/foo/bar"
+	checksums_url = "https://example.com/checksums.txt"
+}`,
+			},
+			errCheck: func(err error) bool {
+				return err == nil || err.Error() != `plugin "foo": "checksums_url" can only be used with "https://" or "file://" sources`
+			},
+		},
+		{
+			name: "plugin with timeout",
+			file: "plugin_with_timeout.hcl",
+			files: map[string]string{
+				"plugin_with_timeout.hcl": `
+plugin "foo" {
+	enabled = true
+	timeout = "1m30s"
+}`,
+			},
+			want: &Config{
+				CallModuleType:    terraform.CallLocalModule,
+				Force:             false,
+				IgnoreModules:     map[string]bool{},
+				Varfiles:          []string{},
+				Variables:         []string{},
+				DisabledByDefault: false,
+				Rules:             map[string]*RuleConfig{},
+				Plugins: map[string]*PluginConfig{
+					"foo": {
+						Name:         "foo",
+						Enabled:      true,
+						Timeout:      "1m30s",
+						CheckTimeout: 90 * time.Second,
+					},
+					"terraform": {
+						Name:    "terraform",
+						Enabled: true,
+					},
+				},
+			},
+			errCheck: neverHappend,
+		},
+		{
+			name: "plugin with invalid timeout",
+			file: "plugin_with_invalid_timeout.hcl",
+			files: map[string]string{
+				"plugin_with_invalid_timeout.hcl": `
+plugin "foo" {
+	enabled = true
+	timeout = "30"
+}`,
+			},
+			errCheck: func(err error) bool {
+				return err == nil || err.Error() != `plugin "foo": "timeout" must be a positive duration like "30s" or "5m"`
+			},
+		},
+		{
+			name: "multiple plugin directories",
+			file: "plugin_dirs.hcl",
+			files: map[string]string{
+				"plugin_dirs.hcl": `
+config {
+	plugin_dir = ["./.tflint.d/plugins", "/opt/tflint/plugins"]
+}`,
+			},
+			want: &Config{
+				CallModuleType:    terraform.CallLocalModule,
+				Force:             false,
+				IgnoreModules:     map[string]bool{},
+				Varfiles:          []string{},
+				Variables:         []string{},
+				DisabledByDefault: false,
+				PluginDirs:        []string{"./.tflint.d/plugins", "/opt/tflint/plugins"},
+				PluginDirsSet:     true,
+				Rules:             map[string]*RuleConfig{},
+				Plugins: map[string]*PluginConfig{
+					"terraform": {
+						Name:    "terraform",
+						Enabled: true,
+					},
+				},
+			},
+			errCheck: neverHappend,
+		},
+		{
+			name: "plugin with env and args",
+			file: "plugin_with_env.hcl",
+			files: map[string]string{
+				"plugin_with_env.hcl": `
+plugin "foo" {
+	enabled = true
+	env = {
+		AWS_REGION = "us-east-1"
+	}
+	env_passthrough = ["AWS_*"]
+	args = ["--foo"]
+}`,
+			},
+			want: &Config{
+				CallModuleType:    terraform.CallLocalModule,
+				Force:             false,
+				IgnoreModules:     map[string]bool{},
+				Varfiles:          []string{},
+				Variables:         []string{},
+				DisabledByDefault: false,
+				Rules:             map[string]*RuleConfig{},
+				Plugins: map[string]*PluginConfig{
+					"foo": {
+						Name:           "foo",
+						Enabled:        true,
+						Env:            map[string]string{"AWS_REGION": "us-east-1"},
+						EnvPassthrough: []string{"AWS_*"},
+						Args:           []string{"--foo"},
+					},
+					"terraform": {
+						Name:    "terraform",
+						Enabled: true,
+					},
+				},
+			},
+			errCheck: neverHappend,
+		},
+		{
+			name: "plugin with invalid env_passthrough",
+			file: "plugin_with_invalid_env_passthrough.hcl",
+			files: map[string]string{
+				"plugin_with_invalid_env_passthrough.hcl": `
+plugin "foo" {
+	enabled = true
+	env_passthrough = ["AWS_REGION=us-east-1"]
+}`,
+			},
+			errCheck: func(err error) bool {
+				return err == nil || err.Error() != `plugin "foo": "env_passthrough" must be a list of environment variable names like "AWS_REGION" or "AWS_*"`
+			},
+		},
 		{
 			name: "prefer the passed file over TFLINT_CONFIG_FILE",
 			file: "cli.hcl",
@@ -440,6 +778,144 @@ config {
 			},
 			errCheck: neverHappend,
 		},
+		{
+			name: "scenarios",
+			file: "config.hcl",
+			files: map[string]string{
+				"config.hcl": `
+scenario "production" {
+  varfile   = ["production.tfvars"]
+  workspace = "production"
+}
+
+scenario "staging" {
+  variables = ["env=staging"]
+}`,
+			},
+			want: &Config{
+				CallModuleType:    terraform.CallLocalModule,
+				Force:             false,
+				IgnoreModules:     map[string]bool{},
+				Varfiles:          []string{},
+				Variables:         []string{},
+				DisabledByDefault: false,
+				Rules:             map[string]*RuleConfig{},
+				Plugins: map[string]*PluginConfig{
+					"terraform": {
+						Name:    "terraform",
+						Enabled: true,
+					},
+				},
+				Scenarios: []*ScenarioConfig{
+					{
+						Name:      "production",
+						Varfiles:  []string{"production.tfvars"},
+						Workspace: "production",
+					},
+					{
+						Name:      "staging",
+						Variables: []string{"env=staging"},
+					},
+				},
+			},
+			errCheck: neverHappend,
+		},
+		{
+			name: "duplicate scenarios",
+			file: "config.hcl",
+			files: map[string]string{
+				"config.hcl": `
+scenario "production" {}
+scenario "production" {}`,
+			},
+			errCheck: func(err error) bool {
+				return err == nil || err.Error() != `scenario "production" is declared more than once`
+			},
+		},
+		{
+			name: "modules",
+			file: "config.hcl",
+			files: map[string]string{
+				"config.hcl": `
+config {
+  ignore_module = {
+    "terraform-aws-modules/**" = true
+    "/^module\\.legacy_.+/"    = true
+  }
+}
+
+module "vpc" {
+  rule "aws_instance_invalid_type" {
+    enabled = false
+  }
+}`,
+			},
+			want: &Config{
+				CallModuleType: terraform.CallLocalModule,
+				Force:          false,
+				IgnoreModules: map[string]bool{
+					"terraform-aws-modules/**": true,
+					"/^module\\.legacy_.+/":    true,
+				},
+				Varfiles:          []string{},
+				Variables:         []string{},
+				DisabledByDefault: false,
+				Rules:             map[string]*RuleConfig{},
+				Plugins: map[string]*PluginConfig{
+					"terraform": {
+						Name:    "terraform",
+						Enabled: true,
+					},
+				},
+				Modules: map[string]*ModuleConfig{
+					"vpc": {
+						Name: "vpc",
+						Rules: []*ModuleRuleConfig{
+							{Name: "aws_instance_invalid_type", Enabled: false},
+						},
+					},
+				},
+			},
+			errCheck: neverHappend,
+		},
+		{
+			name: "duplicate modules",
+			file: "config.hcl",
+			files: map[string]string{
+				"config.hcl": `
+module "vpc" {}
+module "vpc" {}`,
+			},
+			errCheck: func(err error) bool {
+				return err == nil || err.Error() != `module "vpc" is declared more than once`
+			},
+		},
+		{
+			name: "invalid module call address",
+			file: "config.hcl",
+			files: map[string]string{
+				"config.hcl": `
+module "network.vpc" {}`,
+			},
+			errCheck: func(err error) bool {
+				return err == nil || err.Error() != `module "network.vpc" is not a valid module call address`
+			},
+		},
+		{
+			name: "invalid ignore_module pattern",
+			file: "config.hcl",
+			files: map[string]string{
+				"config.hcl": `
+config {
+  ignore_module = {
+    "/[/" = true
+  }
+}`,
+			},
+			errCheck: func(err error) bool {
+				return err == nil || err.Error() != `"/[/" is an invalid module pattern; error parsing regexp: missing closing ]: `+"`[`"
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -494,8 +970,8 @@ func TestMerge(t *testing.T) {
 		Varfiles:          []string{"example1.tfvars", "example2.tfvars"},
 		Variables:         []string{"foo=bar"},
 		DisabledByDefault: false,
-		PluginDir:         "./.tflint.d/plugins",
-		PluginDirSet:      true,
+		PluginDirs:        []string{"./.tflint.d/plugins"},
+		PluginDirsSet:     true,
 		Format:            "compact",
 		FormatSet:         true,
 		Rules: map[string]*RuleConfig{
@@ -552,8 +1028,8 @@ func TestMerge(t *testing.T) {
 				Variables:            []string{"foo=bar"},
 				DisabledByDefault:    true,
 				DisabledByDefaultSet: true,
-				PluginDir:            "./.tflint.d/plugins",
-				PluginDirSet:         true,
+				PluginDirs:           []string{"./.tflint.d/plugins"},
+				PluginDirsSet:        true,
 				Format:               "compact",
 				FormatSet:            true,
 				Rules: map[string]*RuleConfig{
@@ -592,10 +1068,12 @@ func TestMerge(t *testing.T) {
 				Variables:            []string{"bar=baz"},
 				DisabledByDefault:    false,
 				DisabledByDefaultSet: true,
-				PluginDir:            "~/.tflint.d/plugins",
-				PluginDirSet:         true,
+				PluginDirs:           []string{"~/.tflint.d/plugins"},
+				PluginDirsSet:        true,
 				Format:               "json",
 				FormatSet:            true,
+				Language:             terraform.LanguageOpenTofu,
+				LanguageSet:          true,
 				Rules: map[string]*RuleConfig{
 					"aws_instance_invalid_ami": {
 						Name:    "aws_instance_invalid_ami",
@@ -633,10 +1111,12 @@ func TestMerge(t *testing.T) {
 				Variables:            []string{"foo=bar", "bar=baz"},
 				DisabledByDefault:    false,
 				DisabledByDefaultSet: true,
-				PluginDir:            "~/.tflint.d/plugins",
-				PluginDirSet:         true,
+				PluginDirs:           []string{"~/.tflint.d/plugins"},
+				PluginDirsSet:        true,
 				Format:               "json",
 				FormatSet:            true,
+				Language:             terraform.LanguageOpenTofu,
+				LanguageSet:          true,
 				Rules: map[string]*RuleConfig{
 					"aws_instance_invalid_type": {
 						Name:    "aws_instance_invalid_type",
@@ -901,6 +1381,60 @@ func TestMerge(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "merge scenarios",
+			base: &Config{
+				Rules:   map[string]*RuleConfig{},
+				Plugins: map[string]*PluginConfig{},
+				Scenarios: []*ScenarioConfig{
+					{Name: "production", Workspace: "production"},
+					{Name: "staging", Workspace: "staging"},
+				},
+			},
+			other: &Config{
+				Rules:   map[string]*RuleConfig{},
+				Plugins: map[string]*PluginConfig{},
+				Scenarios: []*ScenarioConfig{
+					{Name: "staging", Variables: []string{"env=staging"}},
+					{Name: "development"},
+				},
+			},
+			want: &Config{
+				Rules:   map[string]*RuleConfig{},
+				Plugins: map[string]*PluginConfig{},
+				Scenarios: []*ScenarioConfig{
+					{Name: "production", Workspace: "production"},
+					{Name: "staging", Variables: []string{"env=staging"}}, // overridden
+					{Name: "development"},
+				},
+			},
+		},
+		{
+			name: "merge modules",
+			base: &Config{
+				Rules:   map[string]*RuleConfig{},
+				Plugins: map[string]*PluginConfig{},
+				Modules: map[string]*ModuleConfig{
+					"vpc": {Name: "vpc", Rules: []*ModuleRuleConfig{{Name: "rule_a", Enabled: false}}},
+					"db":  {Name: "db", Rules: []*ModuleRuleConfig{{Name: "rule_a", Enabled: false}}},
+				},
+			},
+			other: &Config{
+				Rules:   map[string]*RuleConfig{},
+				Plugins: map[string]*PluginConfig{},
+				Modules: map[string]*ModuleConfig{
+					"vpc": {Name: "vpc", Rules: []*ModuleRuleConfig{{Name: "rule_b", Enabled: false}}},
+				},
+			},
+			want: &Config{
+				Rules:   map[string]*RuleConfig{},
+				Plugins: map[string]*PluginConfig{},
+				Modules: map[string]*ModuleConfig{
+					"vpc": {Name: "vpc", Rules: []*ModuleRuleConfig{{Name: "rule_b", Enabled: false}}}, // overridden
+					"db":  {Name: "db", Rules: []*ModuleRuleConfig{{Name: "rule_a", Enabled: false}}},
+				},
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -1179,6 +1713,16 @@ func Test_ValidateRules(t *testing.T) {
 			RuleSets: []RuleSet{&ruleSetB{}},
 			Err:      errors.New("Rule not found: aws_instance_invalid_type"),
 		},
+		{
+			Name: "not found in module",
+			Config: &Config{
+				Modules: map[string]*ModuleConfig{
+					"vpc": {Name: "vpc", Rules: []*ModuleRuleConfig{{Name: "aws_instance_invalid_type", Enabled: false}}},
+				},
+			},
+			RuleSets: []RuleSet{&ruleSetB{}},
+			Err:      errors.New(`Rule not found: aws_instance_invalid_type (in module "vpc")`),
+		},
 	}
 
 	for _, tc := range cases {
@@ -1198,3 +1742,208 @@ func Test_ValidateRules(t *testing.T) {
 		}
 	}
 }
+
+func TestIsModuleIgnored(t *testing.T) {
+	fs := afero.Afero{Fs: afero.NewMemMapFs()}
+	if err := fs.WriteFile(".tflint.hcl", []byte(`
+config {
+  ignore_module = {
+    "terraform-aws-modules/**" = true
+  }
+}`), os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	config, err := LoadConfig(fs, ".tflint.hcl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cliConfig := EmptyConfig()
+	cliConfig.IgnoreModules = map[string]bool{"/^module\\.legacy_/": true}
+	config.Merge(cliConfig)
+
+	// Patterns are parsed when the config is loaded and merged
+	if diff := cmp.Diff([]string{"/^module\\.legacy_/", "terraform-aws-modules/**"}, mapKeys(config.ignoreModulePatterns), cmpopts.SortSlices(func(a, b string) bool { return a < b })); diff != "" {
+		t.Fatal(diff)
+	}
+
+	tests := []struct {
+		name    string
+		targets []string
+		want    bool
+	}{
+		{
+			name:    "glob in file",
+			targets: []string{"terraform-aws-modules/vpc/aws", "module.vpc"},
+			want:    true,
+		},
+		{
+			name:    "regexp in CLI",
+			targets: []string{"./modules/legacy", "module.legacy_network"},
+			want:    true,
+		},
+		{
+			name:    "not ignored",
+			targets: []string{"./modules/network", "module.network"},
+			want:    false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := config.IsModuleIgnored(test.targets...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != test.want {
+				t.Errorf("want %t, got %t", test.want, got)
+			}
+		})
+	}
+
+	// Invalid patterns passed by CLI are reported when matching
+	cliConfig.IgnoreModules = map[string]bool{"/[/": true}
+	config.Merge(cliConfig)
+	_, err = config.IsModuleIgnored("module.vpc")
+	if err == nil || err.Error() != `"/[/" is an invalid module pattern; error parsing regexp: missing closing ]: `+"`[`" {
+		t.Fatalf("unexpected error: %s", err)
+	}
+}
+
+func mapKeys[T any](m map[string]T) []string {
+	keys := []string{}
+	for k := range m {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+func TestIsRuleDisabledInModules(t *testing.T) {
+	config := EmptyConfig()
+	config.Modules = map[string]*ModuleConfig{
+		"network": {
+			Name:  "network",
+			Rules: []*ModuleRuleConfig{{Name: "test_rule", Enabled: false}},
+		},
+		"module.app.module.vpc": {
+			Name:  "module.app.module.vpc",
+			Rules: []*ModuleRuleConfig{{Name: "test_rule", Enabled: false}},
+		},
+		"enabled": {
+			Name:  "enabled",
+			Rules: []*ModuleRuleConfig{{Name: "test_rule", Enabled: true}},
+		},
+	}
+
+	tests := []struct {
+		name string
+		rule string
+		path addrs.Module
+		want bool
+	}{
+		{
+			name: "root module",
+			rule: "test_rule",
+			path: addrs.RootModule,
+			want: false,
+		},
+		{
+			name: "call name",
+			rule: "test_rule",
+			path: addrs.Module{"network"},
+			want: true,
+		},
+		{
+			name: "child of the module call",
+			rule: "test_rule",
+			path: addrs.Module{"network", "subnet"},
+			want: true,
+		},
+		{
+			name: "same name in another module",
+			rule: "test_rule",
+			path: addrs.Module{"app", "network"},
+			want: false,
+		},
+		{
+			name: "name prefix",
+			rule: "test_rule",
+			path: addrs.Module{"network_v2"},
+			want: false,
+		},
+		{
+			name: "call address",
+			rule: "test_rule",
+			path: addrs.Module{"app", "vpc"},
+			want: true,
+		},
+		{
+			name: "parent of the module call",
+			rule: "test_rule",
+			path: addrs.Module{"app"},
+			want: false,
+		},
+		{
+			name: "other rule",
+			rule: "other_rule",
+			path: addrs.Module{"network"},
+			want: false,
+		},
+		{
+			name: "enabled",
+			rule: "test_rule",
+			path: addrs.Module{"enabled"},
+			want: false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := config.IsRuleDisabledInModules(test.rule, test.path)
+			if got != test.want {
+				t.Errorf("want %t, got %t", test.want, got)
+			}
+		})
+	}
+}
+
+func TestForScenario(t *testing.T) {
+	config := &Config{
+		Varfiles:  []string{"common.tfvars"},
+		Variables: []string{"region=us-east-1"},
+	}
+
+	got := config.ForScenario(&ScenarioConfig{
+		Name:      "production",
+		Varfiles:  []string{"production.tfvars"},
+		Variables: []string{"env=production"},
+		Workspace: "production",
+	})
+
+	want := &Config{
+		Varfiles:  []string{"common.tfvars", "production.tfvars"},
+		Variables: []string{"region=us-east-1", "env=production"},
+		Workspace: "production",
+	}
+	opts := []cmp.Option{cmpopts.IgnoreUnexported(Config{})}
+	if diff := cmp.Diff(want, got, opts...); diff != "" {
+		t.Fatal(diff)
+	}
+
+	// The original config is not modified
+	if diff := cmp.Diff([]string{"common.tfvars"}, config.Varfiles); diff != "" {
+		t.Fatal(diff)
+	}
+	if config.Workspace != "" {
+		t.Fatalf("workspace should not be changed, but got %s", config.Workspace)
+	}
+
+	// The workspace in the config is kept if the scenario does not declare it
+	config.Workspace = "staging"
+	got = config.ForScenario(&ScenarioConfig{Name: "staging"})
+	if got.Workspace != "staging" {
+		t.Fatalf("workspace should be staging, but got %s", got.Workspace)
+	}
+	if diff := cmp.Diff([]string{"common.tfvars"}, got.Varfiles); diff != "" {
+		t.Fatal(diff)
+	}
+}
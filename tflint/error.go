@@ -0,0 +1,58 @@
+package tflint
+
+import (
+	"fmt"
+	"strings"
+)
+
+// WorkingDirError is an error that occurred while inspecting a working directory in recursive mode.
+type WorkingDirError struct {
+	Dir string
+	Err error
+}
+
+func (e *WorkingDirError) Error() string {
+	return fmt.Sprintf("%s working_dir=%s", e.Err, e.Dir)
+}
+
+func (e *WorkingDirError) Unwrap() error {
+	return e.Err
+}
+
+// WorkingDirErrors is a list of errors attributed to working directories.
+// This is used to report errors in multiple directories at once with --keep-going.
+type WorkingDirErrors []*WorkingDirError
+
+func (errs WorkingDirErrors) Error() string {
+	messages := make([]string, len(errs))
+	for i, err := range errs {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "\n")
+}
+
+// PluginError is an error that occurred while checking a ruleset plugin.
+type PluginError struct {
+	Name string
+	Err  error
+}
+
+func (e *PluginError) Error() string {
+	return fmt.Sprintf(`Failed to check "%s" ruleset; %s`, e.Name, e.Err)
+}
+
+func (e *PluginError) Unwrap() error {
+	return e.Err
+}
+
+// PluginErrors is a list of errors attributed to ruleset plugins.
+// This is used to report errors in failed plugins while keeping issues from other plugins with --keep-going-plugins.
+type PluginErrors []*PluginError
+
+func (errs PluginErrors) Error() string {
+	messages := make([]string, len(errs))
+	for i, err := range errs {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "\n")
+}
@@ -2,7 +2,9 @@ package tflint
 
 import (
 	"fmt"
+	"slices"
 	"sort"
+	"strings"
 
 	hcl "github.com/hashicorp/hcl/v2"
 	sdk "github.com/terraform-linters/tflint-plugin-sdk/tflint"
@@ -16,6 +18,14 @@ type Issue struct {
 	Fixable bool
 	Callers []hcl.Range
 
+	// Scenarios are the names of scenarios in which the issue was found.
+	// This is empty if no scenarios are declared.
+	Scenarios []string
+
+	// ModuleInstances are the addresses of module instances in which the issue was found.
+	// This is only set for issues found in modules called with count or for_each.
+	ModuleInstances []string
+
 	// Source is the source code of the file where the issue was found.
 	// Usually this is the same as the originally loaded source,
 	// but it may be a different if rewritten by autofixes.
@@ -56,6 +66,44 @@ func SeverityToInt32(s Severity) (int32, error) {
 	}
 }
 
+// Merge merges issues that differ only in scenarios or module instances into one issue
+// that has all the scenarios and module instances. The order of issues is preserved.
+func (issues Issues) Merge() Issues {
+	ret := Issues{}
+	seen := map[string]*Issue{}
+
+	for _, issue := range issues {
+		key := issue.key()
+		if merged, exists := seen[key]; exists {
+			for _, scenario := range issue.Scenarios {
+				if !slices.Contains(merged.Scenarios, scenario) {
+					merged.Scenarios = append(merged.Scenarios, scenario)
+				}
+			}
+			for _, instance := range issue.ModuleInstances {
+				if !slices.Contains(merged.ModuleInstances, instance) {
+					merged.ModuleInstances = append(merged.ModuleInstances, instance)
+				}
+			}
+			continue
+		}
+
+		seen[key] = issue
+		ret = append(ret, issue)
+	}
+
+	return ret
+}
+
+// key returns a string that identifies the issue regardless of scenarios and module instances.
+func (i *Issue) key() string {
+	callers := make([]string, len(i.Callers))
+	for idx, caller := range i.Callers {
+		callers[idx] = caller.String()
+	}
+	return fmt.Sprintf("%s:%s:%s:%t:%s", i.Rule.Name(), i.Range, i.Message, i.Fixable, strings.Join(callers, ","))
+}
+
 // Sort returns the sorted receiver
 func (issues Issues) Sort() Issues {
 	sort.Slice(issues, func(i, j int) bool {
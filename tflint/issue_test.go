@@ -226,3 +226,58 @@ func Test_Sort(t *testing.T) {
 		t.Fatalf("Failed: diff=%s", cmp.Diff(got, expected))
 	}
 }
+
+func Test_Merge(t *testing.T) {
+	rng := hcl.Range{
+		Filename: "test.tf",
+		Start:    hcl.Pos{Line: 1, Column: 1},
+		End:      hcl.Pos{Line: 1, Column: 2},
+	}
+
+	issues := Issues{
+		{Rule: &testRule{}, Message: "test", Range: rng, Scenarios: []string{"production"}},
+		{Rule: &testRule{}, Message: "other", Range: rng, Scenarios: []string{"production"}},
+		{Rule: &testRule{}, Message: "test", Range: rng, Scenarios: []string{"staging"}},
+		{Rule: &testRule{}, Message: "test", Range: rng, Scenarios: []string{"production"}},
+	}
+
+	expected := Issues{
+		{Rule: &testRule{}, Message: "test", Range: rng, Scenarios: []string{"production", "staging"}},
+		{Rule: &testRule{}, Message: "other", Range: rng, Scenarios: []string{"production"}},
+	}
+
+	got := issues.Merge()
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Fatalf("Failed: diff=%s", diff)
+	}
+}
+
+func Test_Merge_moduleInstances(t *testing.T) {
+	rng := hcl.Range{
+		Filename: "test.tf",
+		Start:    hcl.Pos{Line: 1, Column: 1},
+		End:      hcl.Pos{Line: 1, Column: 2},
+	}
+	caller := hcl.Range{
+		Filename: "main.tf",
+		Start:    hcl.Pos{Line: 3, Column: 1},
+		End:      hcl.Pos{Line: 3, Column: 2},
+	}
+
+	issues := Issues{
+		{Rule: &testRule{}, Message: "test", Range: rng, Callers: []hcl.Range{caller}, ModuleInstances: []string{"module.foo[0]"}, Scenarios: []string{"production"}},
+		{Rule: &testRule{}, Message: "test", Range: rng, Callers: []hcl.Range{caller}, ModuleInstances: []string{"module.foo[1]"}, Scenarios: []string{"production"}},
+		{Rule: &testRule{}, Message: "test", Range: rng, Callers: []hcl.Range{rng}, ModuleInstances: []string{"module.foo[0]"}, Scenarios: []string{"production"}},
+		{Rule: &testRule{}, Message: "test", Range: rng, Callers: []hcl.Range{caller}, ModuleInstances: []string{"module.foo[1]", "module.foo[2]"}, Scenarios: []string{"staging"}},
+	}
+
+	expected := Issues{
+		{Rule: &testRule{}, Message: "test", Range: rng, Callers: []hcl.Range{caller}, ModuleInstances: []string{"module.foo[0]", "module.foo[1]", "module.foo[2]"}, Scenarios: []string{"production", "staging"}},
+		{Rule: &testRule{}, Message: "test", Range: rng, Callers: []hcl.Range{rng}, ModuleInstances: []string{"module.foo[0]"}, Scenarios: []string{"production"}},
+	}
+
+	got := issues.Merge()
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Fatalf("Failed: diff=%s", diff)
+	}
+}
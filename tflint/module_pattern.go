@@ -0,0 +1,67 @@
+package tflint
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/bmatcuk/doublestar"
+)
+
+// modulePattern is a pattern to match module calls in "ignore_module".
+//
+// A pattern enclosed in slashes (e.g. "/^terraform-aws-modules/.+/") is a regular expression,
+// and a pattern that contains glob metacharacters (e.g. "terraform-aws-modules/**") is a glob.
+// Otherwise, the pattern matches exactly. In globs, "*" does not match slashes, but "**" does.
+type modulePattern struct {
+	raw    string
+	regexp *regexp.Regexp
+	glob   bool
+}
+
+func parseModulePattern(raw string) (*modulePattern, error) {
+	pattern := &modulePattern{raw: raw}
+
+	if len(raw) > 2 && strings.HasPrefix(raw, "/") && strings.HasSuffix(raw, "/") {
+		re, err := regexp.Compile(raw[1 : len(raw)-1])
+		if err != nil {
+			return nil, fmt.Errorf(`"%s" is an invalid module pattern; %w`, raw, err)
+		}
+		pattern.regexp = re
+		return pattern, nil
+	}
+
+	if strings.ContainsAny(raw, "*?[{") {
+		// Check the syntax of the glob in advance
+		if _, err := doublestar.Match(raw, ""); err != nil {
+			return nil, fmt.Errorf(`"%s" is an invalid module pattern; %w`, raw, err)
+		}
+		pattern.glob = true
+	}
+
+	return pattern, nil
+}
+
+// match returns true if any of the targets matches the pattern.
+func (p *modulePattern) match(targets ...string) bool {
+	for _, target := range targets {
+		if target == "" {
+			continue
+		}
+
+		switch {
+		case target == p.raw:
+			return true
+		case p.regexp != nil:
+			if p.regexp.MatchString(target) {
+				return true
+			}
+		case p.glob:
+			// The syntax is already checked in parseModulePattern
+			if matched, _ := doublestar.Match(p.raw, target); matched {
+				return true
+			}
+		}
+	}
+	return false
+}
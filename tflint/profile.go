@@ -0,0 +1,211 @@
+package tflint
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Profiler records durations of operations such as loading configurations and plugin checks.
+// This is used to print a timing summary with --profile and to write a trace file with --trace-file.
+//
+// A nil Profiler is valid and records nothing, so callers don't need to check whether profiling is enabled.
+type Profiler struct {
+	start time.Time
+	// parent and args are set for a profiler returned by WithArgs.
+	parent *Profiler
+	args   []string
+
+	mu     sync.Mutex
+	events []*ProfileEvent
+}
+
+// ProfileEvent is a recorded operation.
+type ProfileEvent struct {
+	Name string
+	// Args is a list of key/value pairs that describe the operation, like "plugin", "aws".
+	Args     []string
+	Start    time.Time
+	Duration time.Duration
+}
+
+// NewProfiler returns a new Profiler.
+func NewProfiler() *Profiler {
+	return &Profiler{start: time.Now()}
+}
+
+// WithArgs returns a profiler that prepends the passed args to the args of all operations.
+// This is used to distinguish operations in each working directory with --recursive.
+// Operations are recorded in the receiver.
+func (p *Profiler) WithArgs(args ...string) *Profiler {
+	if p == nil {
+		return nil
+	}
+	return &Profiler{start: p.start, parent: p, args: args}
+}
+
+// Start starts recording the operation and returns a function to stop it.
+// The args are key/value pairs like "plugin", "aws", "module", "module.foo".
+func (p *Profiler) Start(name string, args ...string) func() {
+	if p == nil {
+		return func() {}
+	}
+	if p.parent != nil {
+		return p.parent.Start(name, append(append([]string{}, p.args...), args...)...)
+	}
+
+	start := time.Now()
+	return func() {
+		event := &ProfileEvent{Name: name, Args: args, Start: start, Duration: time.Since(start)}
+
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		p.events = append(p.events, event)
+	}
+}
+
+// Events returns the recorded events in the order of completion.
+func (p *Profiler) Events() []*ProfileEvent {
+	if p == nil {
+		return []*ProfileEvent{}
+	}
+	if p.parent != nil {
+		return p.parent.Events()
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]*ProfileEvent{}, p.events...)
+}
+
+// key returns the operation name with args, like "check (plugin=aws, module=root)".
+func (e *ProfileEvent) key() string {
+	if len(e.Args) == 0 {
+		return e.Name
+	}
+	pairs := []string{}
+	for i := 0; i+1 < len(e.Args); i += 2 {
+		pairs = append(pairs, fmt.Sprintf("%s=%s", e.Args[i], e.Args[i+1]))
+	}
+	return fmt.Sprintf("%s (%s)", e.Name, strings.Join(pairs, ", "))
+}
+
+// lane returns the name of the lane in which the event is shown in a trace viewer.
+// Events with the same args are shown in the same lane, so that requests from
+// a plugin are nested in the check of the plugin.
+func (e *ProfileEvent) lane() string {
+	values := []string{}
+	for i := 1; i < len(e.Args); i += 2 {
+		values = append(values, e.Args[i])
+	}
+	if len(values) == 0 {
+		return "main"
+	}
+	return strings.Join(values, " ")
+}
+
+// PrintSummary writes a table with the count, total, average, and maximum duration of each operation.
+// Operations are sorted by the total duration in descending order.
+func (p *Profiler) PrintSummary(w io.Writer) error {
+	type row struct {
+		key   string
+		count int
+		total time.Duration
+		max   time.Duration
+	}
+
+	rows := []*row{}
+	byKey := map[string]*row{}
+	for _, event := range p.Events() {
+		r, exists := byKey[event.key()]
+		if !exists {
+			r = &row{key: event.key()}
+			byKey[r.key] = r
+			rows = append(rows, r)
+		}
+		r.count++
+		r.total += event.Duration
+		if event.Duration > r.max {
+			r.max = event.Duration
+		}
+	}
+	sort.SliceStable(rows, func(i, j int) bool { return rows[i].total > rows[j].total })
+
+	width := len("Operation")
+	for _, r := range rows {
+		if len(r.key) > width {
+			width = len(r.key)
+		}
+	}
+
+	fmt.Fprintf(w, "%-*s  %7s  %12s  %12s  %12s\n", width, "Operation", "Count", "Total", "Avg", "Max")
+	for _, r := range rows {
+		avg := r.total / time.Duration(r.count)
+		fmt.Fprintf(w, "%-*s  %7d  %12s  %12s  %12s\n", width, r.key, r.count, formatDuration(r.total), formatDuration(avg), formatDuration(r.max))
+	}
+	_, err := fmt.Fprintf(w, "\nTotal elapsed time: %s\n", formatDuration(time.Since(p.start)))
+	return err
+}
+
+func formatDuration(d time.Duration) string {
+	return d.Round(time.Microsecond).String()
+}
+
+type traceEvent struct {
+	Name      string            `json:"name"`
+	Phase     string            `json:"ph"`
+	Timestamp float64           `json:"ts"`
+	Duration  float64           `json:"dur,omitempty"`
+	PID       int               `json:"pid"`
+	TID       int               `json:"tid"`
+	Args      map[string]string `json:"args,omitempty"`
+}
+
+// WriteTrace writes the recorded events in the Chrome trace event format.
+// The file can be opened in trace viewers such as chrome://tracing and Perfetto.
+func (p *Profiler) WriteTrace(w io.Writer) error {
+	events := p.Events()
+	sort.SliceStable(events, func(i, j int) bool { return events[i].Start.Before(events[j].Start) })
+
+	traceEvents := []traceEvent{}
+	lanes := map[string]int{}
+	for _, event := range events {
+		lane := event.lane()
+		tid, exists := lanes[lane]
+		if !exists {
+			tid = len(lanes) + 1
+			lanes[lane] = tid
+			// Metadata event to show the lane name
+			traceEvents = append(traceEvents, traceEvent{
+				Name:  "thread_name",
+				Phase: "M",
+				PID:   1,
+				TID:   tid,
+				Args:  map[string]string{"name": lane},
+			})
+		}
+
+		args := map[string]string{}
+		for i := 0; i+1 < len(event.Args); i += 2 {
+			args[event.Args[i]] = event.Args[i+1]
+		}
+		traceEvents = append(traceEvents, traceEvent{
+			Name:      event.Name,
+			Phase:     "X",
+			Timestamp: float64(event.Start.Sub(p.start).Nanoseconds()) / 1e3,
+			Duration:  float64(event.Duration.Nanoseconds()) / 1e3,
+			PID:       1,
+			TID:       tid,
+			Args:      args,
+		})
+	}
+
+	return json.NewEncoder(w).Encode(map[string]interface{}{
+		"traceEvents":     traceEvents,
+		"displayTimeUnit": "ms",
+	})
+}
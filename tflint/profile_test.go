@@ -0,0 +1,125 @@
+package tflint
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_Profiler_nil(t *testing.T) {
+	var profiler *Profiler
+	profiler.Start("check", "plugin", "aws")()
+
+	if len(profiler.Events()) != 0 {
+		t.Fatalf("Expected no events, but got %d", len(profiler.Events()))
+	}
+}
+
+func Test_Profiler_Start(t *testing.T) {
+	profiler := NewProfiler()
+	profiler.Start("check", "plugin", "aws", "module", "root")()
+
+	events := profiler.Events()
+	if len(events) != 1 {
+		t.Fatalf("Expected 1 event, but got %d", len(events))
+	}
+	if events[0].Name != "check" {
+		t.Fatalf("expected=check, actual=%s", events[0].Name)
+	}
+	if diff := cmp.Diff([]string{"plugin", "aws", "module", "root"}, events[0].Args); diff != "" {
+		t.Fatal(diff)
+	}
+}
+
+func Test_Profiler_WithArgs(t *testing.T) {
+	profiler := NewProfiler()
+	profiler.WithArgs("dir", "modules/foo").Start("check", "plugin", "aws", "module", "root")()
+	profiler.Start("load TFLint config")()
+
+	events := profiler.Events()
+	if len(events) != 2 {
+		t.Fatalf("Expected 2 events, but got %d", len(events))
+	}
+	if diff := cmp.Diff([]string{"dir", "modules/foo", "plugin", "aws", "module", "root"}, events[0].Args); diff != "" {
+		t.Fatal(diff)
+	}
+	if len(events[1].Args) != 0 {
+		t.Fatalf("Expected no args, but got %v", events[1].Args)
+	}
+
+	var nilProfiler *Profiler
+	if nilProfiler.WithArgs("dir", "modules/foo") != nil {
+		t.Fatal("Expected nil profiler")
+	}
+}
+
+func Test_Profiler_PrintSummary(t *testing.T) {
+	start := time.Now()
+	profiler := &Profiler{
+		start: start,
+		events: []*ProfileEvent{
+			{Name: "load TFLint config", Start: start, Duration: 1 * time.Millisecond},
+			{Name: "EvaluateExpr", Args: []string{"plugin", "aws", "module", "root"}, Start: start, Duration: 2 * time.Millisecond},
+			{Name: "EvaluateExpr", Args: []string{"plugin", "aws", "module", "root"}, Start: start, Duration: 4 * time.Millisecond},
+			{Name: "check", Args: []string{"plugin", "aws", "module", "root"}, Start: start, Duration: 10 * time.Millisecond},
+		},
+	}
+
+	out := new(bytes.Buffer)
+	if err := profiler.PrintSummary(out); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(out.String(), "\n")
+
+	expected := []string{
+		"Operation                                 Count         Total           Avg           Max",
+		"check (plugin=aws, module=root)               1          10ms          10ms          10ms",
+		"EvaluateExpr (plugin=aws, module=root)        2           6ms           3ms           4ms",
+		"load TFLint config                            1           1ms           1ms           1ms",
+	}
+	if diff := cmp.Diff(expected, lines[:4]); diff != "" {
+		t.Fatal(diff)
+	}
+	if !strings.HasPrefix(lines[5], "Total elapsed time: ") {
+		t.Fatalf("Expected the total elapsed time, but got %s", lines[5])
+	}
+}
+
+func Test_Profiler_WriteTrace(t *testing.T) {
+	start := time.Now()
+	profiler := &Profiler{
+		start: start,
+		events: []*ProfileEvent{
+			{Name: "EvaluateExpr", Args: []string{"plugin", "aws", "module", "root"}, Start: start.Add(2 * time.Millisecond), Duration: 1 * time.Millisecond},
+			{Name: "check", Args: []string{"plugin", "aws", "module", "root"}, Start: start.Add(1 * time.Millisecond), Duration: 5 * time.Millisecond},
+			{Name: "load TFLint config", Start: start, Duration: 1 * time.Millisecond},
+		},
+	}
+
+	out := new(bytes.Buffer)
+	if err := profiler.WriteTrace(out); err != nil {
+		t.Fatal(err)
+	}
+
+	var got struct {
+		TraceEvents []traceEvent `json:"traceEvents"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []traceEvent{
+		{Name: "thread_name", Phase: "M", PID: 1, TID: 1, Args: map[string]string{"name": "main"}},
+		{Name: "load TFLint config", Phase: "X", Timestamp: 0, Duration: 1000, PID: 1, TID: 1},
+		{Name: "thread_name", Phase: "M", PID: 1, TID: 2, Args: map[string]string{"name": "aws root"}},
+		{Name: "check", Phase: "X", Timestamp: 1000, Duration: 5000, PID: 1, TID: 2, Args: map[string]string{"plugin": "aws", "module": "root"}},
+		{Name: "EvaluateExpr", Phase: "X", Timestamp: 2000, Duration: 1000, PID: 1, TID: 2, Args: map[string]string{"plugin": "aws", "module": "root"}},
+	}
+	if diff := cmp.Diff(expected, got.TraceEvents); diff != "" {
+		t.Fatal(diff)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"log"
 	"path/filepath"
+	"sync"
 
 	hcl "github.com/hashicorp/hcl/v2"
 	"github.com/terraform-linters/tflint-plugin-sdk/hclext"
@@ -11,6 +12,7 @@ import (
 	"github.com/terraform-linters/tflint/terraform/addrs"
 	"github.com/terraform-linters/tflint/terraform/lang"
 	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/gocty"
 )
 
 // Runner checks templates according rules.
@@ -21,11 +23,23 @@ type Runner struct {
 	Issues   Issues
 	Ctx      *terraform.Evaluator
 
+	// ModuleInstance is the address of the module instance to be checked.
+	// Unlike TFConfig.Path, this includes instance keys of modules called with count/for_each.
+	ModuleInstance addrs.ModuleInstance
+
 	annotations map[string]Annotations
 	config      *Config
 	currentExpr hcl.Expression
 	modVars     map[string]*moduleVariable
 	changes     map[string][]byte
+
+	// parent and moduleArgs are the runner of the caller module and the module call arguments.
+	// These are used to trace unevaluated expressions to root module variables.
+	parent     *Runner
+	moduleArgs map[string]hcl.Expression
+
+	unevaluatedMu sync.Mutex
+	unevaluated   map[string]*unevaluatedRecord
 }
 
 // Rule is interface for building the issue
@@ -49,9 +63,13 @@ func NewRunner(originalWorkingDir string, c *Config, ants map[string]Annotations
 	if diags.HasErrors() {
 		return nil, diags
 	}
+	workspace := c.Workspace
+	if workspace == "" {
+		workspace = terraform.Workspace()
+	}
 	ctx := &terraform.Evaluator{
 		Meta: &terraform.ContextMeta{
-			Env:                terraform.Workspace(),
+			Env:                workspace,
 			OriginalWorkingDir: originalWorkingDir,
 		},
 		ModulePath:     cfg.Path.UnkeyedInstanceShim(),
@@ -61,8 +79,9 @@ func NewRunner(originalWorkingDir string, c *Config, ants map[string]Annotations
 	}
 
 	runner := &Runner{
-		TFConfig: cfg,
-		Issues:   Issues{},
+		TFConfig:       cfg,
+		Issues:         Issues{},
+		ModuleInstance: cfg.Path.UnkeyedInstanceShim(),
 
 		Ctx:         ctx,
 		annotations: ants,
@@ -86,8 +105,13 @@ func NewModuleRunners(parent *Runner) ([]*Runner, error) {
 		if !ok {
 			panic(fmt.Errorf(`Expected module call "%s" is not found in %s`, name, parent.TFConfig.Path.String()))
 		}
-		if parent.TFConfig.Path.IsRoot() && parent.config.IgnoreModules[moduleCall.SourceAddrRaw] {
-			log.Printf(`[INFO] Ignore "%s" module`, moduleCall.Name)
+		// Modules can be ignored by the source or the module call address (e.g. "module.foo") at any depth
+		ignored, err := parent.config.IsModuleIgnored(moduleCall.SourceAddrRaw, cfg.ResolvedSource(), cfg.Path.String(), "module."+moduleCall.Name)
+		if err != nil {
+			return runners, err
+		}
+		if ignored {
+			log.Printf(`[INFO] Ignore "%s" module`, cfg.Path.String())
 			continue
 		}
 
@@ -117,11 +141,22 @@ func NewModuleRunners(parent *Runner) ([]*Runner, error) {
 				moduleCallBodies = append(moduleCallBodies, block.Body)
 			}
 		}
+		instanceKeys, err := moduleInstanceKeys(parent, moduleCall.Name)
+		if err != nil {
+			return runners, err
+		}
+		if len(instanceKeys) != len(moduleCallBodies) {
+			// This should not happen because keys are evaluated in the same way as the expansion.
+			log.Printf("[WARN] Failed to determine instance keys of %s: got %d keys for %d instances", moduleCall.Name, len(instanceKeys), len(moduleCallBodies))
+			instanceKeys = make([]addrs.InstanceKey, len(moduleCallBodies))
+		}
 
-		for _, body := range moduleCallBodies {
+		for idx, body := range moduleCallBodies {
 			modVars := map[string]*moduleVariable{}
+			moduleArgs := map[string]hcl.Expression{}
 			inputs := terraform.InputValues{}
 			for varName, attribute := range body.Attributes {
+				moduleArgs[varName] = attribute.Expr
 				val, diags := parent.Ctx.EvaluateExpr(attribute.Expr, cty.DynamicPseudoType)
 				if diags.HasErrors() {
 					err := fmt.Errorf(
@@ -159,6 +194,9 @@ func NewModuleRunners(parent *Runner) ([]*Runner, error) {
 				return runners, err
 			}
 			runner.modVars = modVars
+			runner.parent = parent
+			runner.moduleArgs = moduleArgs
+			runner.ModuleInstance = parent.ModuleInstance.Child(moduleCall.Name, instanceKeys[idx])
 			runners = append(runners, runner)
 			moduleRunners, err := NewModuleRunners(runner)
 			if err != nil {
@@ -171,6 +209,80 @@ func NewModuleRunners(parent *Runner) ([]*Runner, error) {
 	return runners, nil
 }
 
+// moduleInstanceKeys returns the instance keys of the passed module call in the same order
+// as the expanded module blocks. If the module call has neither count nor for_each, NoKey is returned.
+func moduleInstanceKeys(parent *Runner, name string) ([]addrs.InstanceKey, error) {
+	content, diags := parent.TFConfig.Module.PartialContent(&hclext.BodySchema{
+		Blocks: []hclext.BlockSchema{
+			{
+				Type:       "module",
+				LabelNames: []string{"name"},
+				Body: &hclext.BodySchema{
+					Attributes: []hclext.AttributeSchema{{Name: "count"}, {Name: "for_each"}},
+				},
+			},
+		},
+	}, nil)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	for _, block := range content.Blocks {
+		if block.Labels[0] != name {
+			continue
+		}
+
+		if attr, exists := block.Body.Attributes["count"]; exists {
+			val, diags := parent.Ctx.EvaluateExpr(attr.Expr, cty.Number)
+			if diags.HasErrors() {
+				return nil, diags
+			}
+			val, _ = val.Unmark()
+			if !val.IsKnown() || val.IsNull() {
+				return []addrs.InstanceKey{}, nil
+			}
+			var count int
+			if err := gocty.FromCtyValue(val, &count); err != nil {
+				return nil, err
+			}
+			keys := make([]addrs.InstanceKey, count)
+			for i := range keys {
+				keys[i] = addrs.IntKey(i)
+			}
+			return keys, nil
+		}
+
+		if attr, exists := block.Body.Attributes["for_each"]; exists {
+			val, diags := parent.Ctx.EvaluateExpr(attr.Expr, cty.DynamicPseudoType)
+			if diags.HasErrors() {
+				return nil, diags
+			}
+			val, _ = val.Unmark()
+			if !val.IsKnown() || val.IsNull() || !val.CanIterateElements() {
+				return []addrs.InstanceKey{}, nil
+			}
+			keys := []addrs.InstanceKey{}
+			for it := val.ElementIterator(); it.Next(); {
+				key, value := it.Element()
+				// The key of a set is the same as the value
+				if val.Type().IsSetType() {
+					key = value
+				}
+				instanceKey, err := addrs.ParseInstanceKey(key)
+				if err != nil {
+					return nil, err
+				}
+				keys = append(keys, instanceKey)
+			}
+			return keys, nil
+		}
+
+		return []addrs.InstanceKey{addrs.NoKey}, nil
+	}
+
+	return []addrs.InstanceKey{}, nil
+}
+
 // LookupIssues returns issues according to the received files
 func (r *Runner) LookupIssues(files ...string) Issues {
 	if len(files) == 0 {
@@ -206,9 +318,12 @@ func (r *Runner) LookupChanges(files ...string) map[string][]byte {
 }
 
 // File returns the raw *hcl.File representation of a Terraform configuration at the specified path,
-// or nil if there path does not match any configuration.
+// or nil if there path does not match any configuration. Test files and mock data files are also looked up.
 func (r *Runner) File(path string) *hcl.File {
-	return r.TFConfig.Module.Files[path]
+	if file, exists := r.TFConfig.Module.Files[path]; exists {
+		return file
+	}
+	return r.TFConfig.Module.TestFiles[path]
 }
 
 // Files returns the raw *hcl.File representation of all Terraform configuration in the module directory.
@@ -225,6 +340,37 @@ func (r *Runner) Sources() map[string][]byte {
 	return r.TFConfig.Module.Sources
 }
 
+// TestFiles returns the raw *hcl.File representation of test files and mock data files.
+// They are kept separately from Files so that rules for configurations are not applied to them.
+func (r *Runner) TestFiles() map[string]*hcl.File {
+	result := make(map[string]*hcl.File)
+	for name, file := range r.TFConfig.Module.TestFiles {
+		result[name] = file
+	}
+	return result
+}
+
+// TestSources returns the sources of test files and mock data files.
+func (r *Runner) TestSources() map[string][]byte {
+	return r.TFConfig.Module.TestSources
+}
+
+// source returns the source of the passed file, which may be a test file.
+func (r *Runner) source(path string) []byte {
+	if source, exists := r.TFConfig.Module.Sources[path]; exists {
+		return source
+	}
+	return r.TFConfig.Module.TestSources[path]
+}
+
+// ModuleName returns the name of the module, like "root" or "module.foo".
+func (r *Runner) ModuleName() string {
+	if r.TFConfig.Path.IsRoot() {
+		return "root"
+	}
+	return r.TFConfig.Path.String()
+}
+
 // EmitIssue builds an issue and accumulates it.
 // Returns true if the issue was not ignored by annotations.
 func (r *Runner) EmitIssue(rule Rule, message string, location hcl.Range, fixable bool) bool {
@@ -234,20 +380,31 @@ func (r *Runner) EmitIssue(rule Rule, message string, location hcl.Range, fixabl
 			Message: message,
 			Range:   location,
 			Fixable: fixable,
-			Source:  r.Sources()[location.Filename],
+			Source:  r.source(location.Filename),
 		})
 	} else {
+		if r.config.IsRuleDisabledInModules(rule.Name(), r.TFConfig.Path) {
+			log.Printf("[INFO] %s (%s) is disabled in %s", location.String(), rule.Name(), r.TFConfig.Path.String())
+			return false
+		}
+
+		var moduleInstances []string
+		if r.ModuleInstance.IsKeyed() {
+			moduleInstances = []string{r.ModuleInstance.String()}
+		}
+
 		modVars := r.listModuleVars(r.currentExpr)
 		// Returns true only if all issues have not been ignored in called modules.
 		allApplied := len(modVars) > 0
 		for _, modVar := range modVars {
 			applied := r.emitIssue(&Issue{
-				Rule:    rule,
-				Message: message,
-				Range:   modVar.DeclRange,
-				Fixable: false, // Issues are always not fixable in called modules.
-				Callers: append(modVar.callers(), location),
-				Source:  r.Sources()[modVar.DeclRange.Filename],
+				Rule:            rule,
+				Message:         message,
+				Range:           modVar.DeclRange,
+				Fixable:         false, // Issues are always not fixable in called modules.
+				Callers:         append(modVar.callers(), location),
+				ModuleInstances: moduleInstances,
+				Source:          r.source(modVar.DeclRange.Filename),
 			})
 			if !applied {
 				allApplied = false
@@ -285,6 +442,7 @@ func (r *Runner) ApplyChanges(changes map[string][]byte) hcl.Diagnostics {
 	if diags.HasErrors() {
 		return diags
 	}
+	r.Ctx.ClearCache()
 	for path, source := range changes {
 		r.changes[path] = source
 	}
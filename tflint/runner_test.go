@@ -167,6 +167,29 @@ func Test_NewModuleRunners_withCountForEach(t *testing.T) {
 		if diff := cmp.Diff(moduleNames, expected, cmpopts.SortSlices(less)); diff != "" {
 			t.Fatal(diff)
 		}
+
+		expr, diags := hclsyntax.ParseExpression([]byte("var.instance_type"), "", hcl.InitialPos)
+		if diags.HasErrors() {
+			t.Fatal(diags)
+		}
+		instances := map[string]string{}
+		for _, r := range runners {
+			val, diags := r.Ctx.EvaluateExpr(expr, cty.String)
+			if diags.HasErrors() {
+				t.Fatal(diags)
+			}
+			instances[r.ModuleInstance.String()] = val.AsString()
+		}
+		expectedInstances := map[string]string{
+			"module.count_is_one[0]":          "t2.micro",
+			"module.count_is_two[0]":          "t0.micro",
+			"module.count_is_two[1]":          "t1.micro",
+			"module.for_each_is_not_empty[0]": "t2.micro",
+			"module.for_each_is_not_empty[1]": "t3.nano",
+		}
+		if diff := cmp.Diff(expectedInstances, instances); diff != "" {
+			t.Fatal(diff)
+		}
 	})
 }
 
@@ -256,20 +279,87 @@ func Test_NewModuleRunners_modVars(t *testing.T) {
 }
 
 func Test_NewModuleRunners_ignoreModules(t *testing.T) {
-	withinFixtureDir(t, "nested_modules", func() {
-		config := moduleConfig()
-		config.IgnoreModules["./module"] = true
-		runner := testRunnerWithOsFs(t, config)
-
-		runners, err := NewModuleRunners(runner)
-		if err != nil {
-			t.Fatalf("Unexpected error occurred: %s", err)
-		}
+	tests := []struct {
+		name    string
+		ignore  map[string]bool
+		want    []string
+		wantErr string
+	}{
+		{
+			name:   "source",
+			ignore: map[string]bool{"./module": true},
+			want:   []string{},
+		},
+		{
+			name:   "disabled",
+			ignore: map[string]bool{"./module": false},
+			want:   []string{"module.root", "module.root.module.test"},
+		},
+		{
+			name:   "nested source",
+			ignore: map[string]bool{"./module1": true},
+			want:   []string{"module.root"},
+		},
+		{
+			name:   "resolved source",
+			ignore: map[string]bool{"module/module1": true},
+			want:   []string{"module.root"},
+		},
+		{
+			name:   "call name",
+			ignore: map[string]bool{"module.test": true},
+			want:   []string{"module.root"},
+		},
+		{
+			name:   "call address",
+			ignore: map[string]bool{"module.root.module.test": true},
+			want:   []string{"module.root"},
+		},
+		{
+			name:   "glob",
+			ignore: map[string]bool{"module/**": true},
+			want:   []string{"module.root"},
+		},
+		{
+			name:   "regexp",
+			ignore: map[string]bool{"/^module\\.te/": true},
+			want:   []string{"module.root"},
+		},
+		{
+			name:    "invalid regexp",
+			ignore:  map[string]bool{"/[/": true},
+			wantErr: `"/[/" is an invalid module pattern; error parsing regexp: missing closing ]: ` + "`[`",
+		},
+	}
 
-		if len(runners) != 0 {
-			t.Fatalf(`This function must not return runners because "ignore_module" is set. Got %d runner(s)`, len(runners))
-		}
-	})
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			withinFixtureDir(t, "nested_modules", func() {
+				config := moduleConfig()
+				config.IgnoreModules = test.ignore
+				runner := testRunnerWithOsFs(t, config)
+
+				runners, err := NewModuleRunners(runner)
+				if err != nil {
+					if err.Error() != test.wantErr {
+						t.Fatalf("Unexpected error occurred: %s", err)
+					}
+					return
+				}
+				if test.wantErr != "" {
+					t.Fatalf("expected error is `%s`, but got no error", test.wantErr)
+				}
+
+				got := make([]string, len(runners))
+				for idx, r := range runners {
+					got[idx] = r.TFConfig.Path.String()
+				}
+				if diff := cmp.Diff(test.want, got); diff != "" {
+					t.Fatal(diff)
+				}
+			})
+		})
+	}
 }
 
 func Test_NewModuleRunners_withInvalidExpression(t *testing.T) {
@@ -308,6 +398,34 @@ func Test_RunnerFiles(t *testing.T) {
 	}
 }
 
+func Test_RunnerTestFiles(t *testing.T) {
+	runner := TestRunner(t, map[string]string{
+		"main.tf":         "",
+		"main.tftest.hcl": `run "foo" {}`,
+	})
+
+	// Test files are kept separately from configuration files
+	if _, exists := runner.Files()["main.tf"]; !exists {
+		t.Error("main.tf is not found in Files()")
+	}
+	if _, exists := runner.Files()["main.tftest.hcl"]; exists {
+		t.Error("main.tftest.hcl is found in Files()")
+	}
+	if _, exists := runner.Sources()["main.tftest.hcl"]; exists {
+		t.Error("main.tftest.hcl is found in Sources()")
+	}
+
+	if files := runner.TestFiles(); len(files) != 1 || files["main.tftest.hcl"] == nil {
+		t.Errorf("unexpected test files: %#v", files)
+	}
+	if diff := cmp.Diff(map[string][]byte{"main.tftest.hcl": []byte(`run "foo" {}`)}, runner.TestSources()); diff != "" {
+		t.Error(diff)
+	}
+	if runner.File("main.tftest.hcl") == nil {
+		t.Error("main.tftest.hcl is not found by File()")
+	}
+}
+
 func Test_LookupIssues(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -460,6 +578,8 @@ func Test_EmitIssue(t *testing.T) {
 	type moduleConfig struct {
 		currentExpr hcl.Expression
 		variables   map[string]*moduleVariable
+		instance    addrs.ModuleInstance
+		modules     map[string]*ModuleConfig
 	}
 
 	cases := []struct {
@@ -714,15 +834,72 @@ func Test_EmitIssue(t *testing.T) {
 			},
 			Applied: true,
 		},
+		{
+			Name:    "in module instance",
+			Rule:    &testRule{},
+			Message: "This is test message",
+			Location: hcl.Range{
+				Filename: "test.tf",
+				Start:    hcl.Pos{Line: 1},
+			},
+			Module: &moduleConfig{
+				currentExpr: parseExpr("var.foo"),
+				variables: map[string]*moduleVariable{
+					"foo": {Root: true, DeclRange: hcl.Range{Filename: "module.tf", Start: hcl.Pos{Line: 1}}},
+				},
+				instance: addrs.ModuleInstance{{Name: "module1", InstanceKey: addrs.StringKey("a")}},
+			},
+			Expected: Issues{
+				{
+					Rule:    &testRule{},
+					Message: "This is test message",
+					Range: hcl.Range{
+						Filename: "module.tf",
+						Start:    hcl.Pos{Line: 1},
+					},
+					Callers: []hcl.Range{
+						{Filename: "module.tf", Start: hcl.Pos{Line: 1}},
+						{Filename: "test.tf", Start: hcl.Pos{Line: 1}},
+					},
+					ModuleInstances: []string{`module.module1["a"]`},
+					Source:          []byte("bar = 2"),
+				},
+			},
+			Applied: true,
+		},
+		{
+			Name:    "disabled in module",
+			Rule:    &testRule{},
+			Message: "This is test message",
+			Location: hcl.Range{
+				Filename: "test.tf",
+				Start:    hcl.Pos{Line: 1},
+			},
+			Module: &moduleConfig{
+				currentExpr: parseExpr("var.foo"),
+				variables: map[string]*moduleVariable{
+					"foo": {Root: true, DeclRange: hcl.Range{Filename: "module.tf", Start: hcl.Pos{Line: 1}}},
+				},
+				modules: map[string]*ModuleConfig{
+					"module1": {Name: "module1", Rules: []*ModuleRuleConfig{{Name: "test_rule", Enabled: false}}},
+				},
+			},
+			Expected: Issues{},
+			Applied:  false,
+		},
 	}
 
 	for _, tc := range cases {
 		t.Run(tc.Name, func(t *testing.T) {
 			runner := testRunnerWithAnnotations(t, sources, tc.Annotations)
 			if tc.Module != nil {
-				runner.TFConfig.Path = []string{"module", "module1"}
+				runner.TFConfig.Path = []string{"module1"}
 				runner.currentExpr = tc.Module.currentExpr
 				runner.modVars = tc.Module.variables
+				if tc.Module.instance != nil {
+					runner.ModuleInstance = tc.Module.instance
+				}
+				runner.config.Modules = tc.Module.modules
 			}
 
 			got := runner.EmitIssue(tc.Rule, tc.Message, tc.Location, tc.Fixable)
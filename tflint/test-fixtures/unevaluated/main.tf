@@ -0,0 +1,36 @@
+variable "name" {}
+variable "region" {}
+variable "password" {
+  sensitive = true
+  default   = "secret"
+}
+variable "known" {
+  default = "foo"
+}
+
+locals {
+  prefix = "${var.name}-${var.region}"
+}
+
+resource "aws_instance" "main" {
+  ami = "ami-12345678"
+}
+
+module "child" {
+  source = "./module"
+
+  input = local.prefix
+  known = var.known
+}
+
+output "name" {
+  value = "${local.prefix}-${var.known}"
+}
+
+output "id" {
+  value = aws_instance.main.id
+}
+
+output "password" {
+  value = var.password
+}
@@ -0,0 +1,6 @@
+variable "input" {}
+variable "known" {}
+
+output "value" {
+  value = "${var.input}-${var.known}"
+}
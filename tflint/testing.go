@@ -34,6 +34,9 @@ func TestRunnerWithConfig(t *testing.T, files map[string]string, config *Config)
 	if err != nil {
 		t.Fatal(err)
 	}
+	loader.SetLanguage(config.Language)
+	loader.SetModuleMirror(config.ModuleMirror)
+	loader.SetStaleModuleManifest(config.StaleModuleManifest)
 
 	dirMap := map[string]*struct{}{}
 	for file := range files {
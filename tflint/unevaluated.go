@@ -0,0 +1,255 @@
+package tflint
+
+import (
+	"fmt"
+	"log"
+	"path/filepath"
+	"slices"
+	"sort"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/terraform-linters/tflint/terraform/addrs"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// UnevaluatedReason is the reason why rules could not check an expression.
+type UnevaluatedReason string
+
+const (
+	// UnevaluatedUnknown means that the expression contains unknown values.
+	UnevaluatedUnknown UnevaluatedReason = "unknown"
+	// UnevaluatedNull means that the expression contains null values.
+	UnevaluatedNull UnevaluatedReason = "null"
+	// UnevaluatedSensitive means that the expression contains sensitive or ephemeral values.
+	UnevaluatedSensitive UnevaluatedReason = "sensitive"
+)
+
+// UnevaluatedExpr is an expression that rules skipped because it was evaluated
+// to an unknown, null, or sensitive value.
+type UnevaluatedExpr struct {
+	Range  hcl.Range
+	Reason UnevaluatedReason
+	// Callers are the ranges of nested module calls and the expression in called modules.
+	// Like issues, expressions in called modules are reported at the module call in the root module.
+	Callers []hcl.Range
+	// Causes are references in the expression that could not be evaluated,
+	// such as "var.foo", "local.bar", or "aws_instance.main".
+	Causes []string
+	// Inputs are root module variables that the expression depends on.
+	// Setting them with --var or tfvars files may make the expression evaluable.
+	Inputs []string
+}
+
+// UnevaluatedReasonOf returns the reason why rules cannot check the passed value.
+// If the value can be checked, it returns an empty string.
+//
+// This follows the same semantics as plugins. Marked values are always skipped,
+// but values are passed as they are if the wanted type is cty.DynamicPseudoType.
+func UnevaluatedReasonOf(val cty.Value, wantType cty.Type) UnevaluatedReason {
+	if wantType == cty.DynamicPseudoType && !val.ContainsMarked() {
+		return ""
+	}
+	return unevaluatedReason(val)
+}
+
+func unevaluatedReason(val cty.Value) UnevaluatedReason {
+	if val.ContainsMarked() {
+		return UnevaluatedSensitive
+	}
+
+	var reason UnevaluatedReason
+	// The callback never returns errors
+	_ = cty.Walk(val, func(path cty.Path, v cty.Value) (bool, error) {
+		if !v.IsKnown() {
+			reason = UnevaluatedUnknown
+			return false, nil
+		}
+		if v.IsNull() {
+			reason = UnevaluatedNull
+			return false, nil
+		}
+		return true, nil
+	})
+	return reason
+}
+
+type unevaluatedRecord struct {
+	expr   hcl.Expression
+	reason UnevaluatedReason
+}
+
+// RecordUnevaluated records the expression that rules could not check.
+// This is goroutine-safe because the root runner is shared by module runners.
+func (r *Runner) RecordUnevaluated(expr hcl.Expression, reason UnevaluatedReason) {
+	r.unevaluatedMu.Lock()
+	defer r.unevaluatedMu.Unlock()
+
+	key := fmt.Sprintf("%s:%s", expr.Range(), reason)
+	if r.unevaluated == nil {
+		r.unevaluated = map[string]*unevaluatedRecord{}
+	}
+	r.unevaluated[key] = &unevaluatedRecord{expr: expr, reason: reason}
+}
+
+// LookupUnevaluated returns recorded expressions in the passed files along with their causes.
+// If no files are passed, all expressions are returned.
+//
+// Like LookupIssues, expressions recorded in called modules are located at the module call
+// in the root module, and the passed files are matched against the location.
+func (r *Runner) LookupUnevaluated(files ...string) []*UnevaluatedExpr {
+	r.unevaluatedMu.Lock()
+	defer r.unevaluatedMu.Unlock()
+
+	callRanges := r.moduleCallRanges()
+
+	ret := []*UnevaluatedExpr{}
+	for _, record := range r.unevaluated {
+		rng := record.expr.Range()
+		var callers []hcl.Range
+		if len(callRanges) > 0 {
+			rng = callRanges[0]
+			callers = append(slices.Clone(callRanges[1:]), record.expr.Range())
+		}
+
+		if len(files) > 0 && !slices.ContainsFunc(files, func(file string) bool {
+			return filepath.Clean(file) == filepath.Clean(rng.Filename)
+		}) {
+			continue
+		}
+
+		causes, inputs := r.traceUnevaluated(record.expr, map[string]bool{})
+		ret = append(ret, &UnevaluatedExpr{
+			Range:   rng,
+			Reason:  record.reason,
+			Callers: callers,
+			Causes:  causes,
+			Inputs:  inputs,
+		})
+	}
+
+	sort.Slice(ret, func(i, j int) bool {
+		if ret[i].Range.Filename != ret[j].Range.Filename {
+			return ret[i].Range.Filename < ret[j].Range.Filename
+		}
+		if ret[i].Range.Start.Byte != ret[j].Range.Start.Byte {
+			return ret[i].Range.Start.Byte < ret[j].Range.Start.Byte
+		}
+		return fmt.Sprint(ret[i].Callers) < fmt.Sprint(ret[j].Callers)
+	})
+	return ret
+}
+
+// moduleCallRanges returns the ranges of module calls from the root module to the module.
+// If the runner is for the root module, it returns nil.
+func (r *Runner) moduleCallRanges() []hcl.Range {
+	if r.parent == nil || r.TFConfig.Path.IsRoot() {
+		return nil
+	}
+
+	ret := r.parent.moduleCallRanges()
+	name := r.TFConfig.Path[len(r.TFConfig.Path)-1]
+	if call, exists := r.parent.TFConfig.Module.ModuleCalls[name]; exists {
+		ret = append(ret, call.DeclRange)
+	}
+	return ret
+}
+
+// traceUnevaluated returns references in the expression that cannot be evaluated,
+// and root module variables they depend on. Local values are traced through their
+// expressions, and variables of called modules are traced through module call arguments.
+func (r *Runner) traceUnevaluated(expr hcl.Expression, visited map[string]bool) (causes []string, inputs []string) {
+	causes = []string{}
+	inputs = []string{}
+
+	for _, traversal := range expr.Variables() {
+		ref, diags := addrs.ParseRef(traversal)
+		if diags.HasErrors() {
+			continue
+		}
+
+		val, diags := r.Ctx.EvaluateExpr(&hclsyntax.ScopeTraversalExpr{Traversal: traversal, SrcRange: traversal.SourceRange()}, cty.DynamicPseudoType)
+		if diags.HasErrors() {
+			log.Printf("[DEBUG] Failed to evaluate %s to find causes: %s", ref.Subject, diags)
+			continue
+		}
+		reason := unevaluatedReason(val)
+		if reason == "" {
+			continue
+		}
+		causes = appendUnique(causes, ref.Subject.String())
+
+		key := fmt.Sprintf("%s:%s", r.ModuleInstance, ref.Subject)
+		if visited[key] {
+			continue
+		}
+		visited[key] = true
+
+		switch subject := ref.Subject.(type) {
+		case addrs.InputVariable:
+			// Sensitive values cannot be checked even if the variable is set.
+			if reason == UnevaluatedSensitive {
+				continue
+			}
+			if r.TFConfig.Path.IsRoot() {
+				inputs = appendUnique(inputs, subject.String())
+				continue
+			}
+			if arg, exists := r.moduleArgs[subject.Name]; exists && r.parent != nil {
+				_, parentInputs := r.parent.traceUnevaluated(arg, visited)
+				for _, input := range parentInputs {
+					inputs = appendUnique(inputs, input)
+				}
+			}
+
+		case addrs.LocalValue:
+			if local, exists := r.TFConfig.Module.Locals[subject.Name]; exists {
+				_, localInputs := r.traceUnevaluated(local.Expr, visited)
+				for _, input := range localInputs {
+					inputs = appendUnique(inputs, input)
+				}
+			}
+		}
+	}
+
+	return causes, inputs
+}
+
+func appendUnique(list []string, s string) []string {
+	if slices.Contains(list, s) {
+		return list
+	}
+	return append(list, s)
+}
+
+// MergeUnevaluated merges expressions recorded at the same range with the same callers for the same reason,
+// such as those recorded in multiple scenarios. Causes and inputs are combined.
+func MergeUnevaluated(exprs []*UnevaluatedExpr) []*UnevaluatedExpr {
+	ret := []*UnevaluatedExpr{}
+	merged := map[string]*UnevaluatedExpr{}
+
+	for _, expr := range exprs {
+		key := fmt.Sprintf("%s:%s:%v", expr.Range, expr.Reason, expr.Callers)
+		if m, exists := merged[key]; exists {
+			for _, cause := range expr.Causes {
+				m.Causes = appendUnique(m.Causes, cause)
+			}
+			for _, input := range expr.Inputs {
+				m.Inputs = appendUnique(m.Inputs, input)
+			}
+			continue
+		}
+
+		m := &UnevaluatedExpr{
+			Range:   expr.Range,
+			Reason:  expr.Reason,
+			Callers: slices.Clone(expr.Callers),
+			Causes:  slices.Clone(expr.Causes),
+			Inputs:  slices.Clone(expr.Inputs),
+		}
+		merged[key] = m
+		ret = append(ret, m)
+	}
+
+	return ret
+}
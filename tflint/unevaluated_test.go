@@ -0,0 +1,133 @@
+package tflint
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/terraform-linters/tflint-plugin-sdk/hclext"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func Test_LookupUnevaluated(t *testing.T) {
+	withinFixtureDir(t, "unevaluated", func() {
+		runner := testRunnerWithOsFs(t, moduleConfig())
+		moduleRunners, err := NewModuleRunners(runner)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(moduleRunners) != 1 {
+			t.Fatalf("expected 1 module runner, but got %d", len(moduleRunners))
+		}
+		child := moduleRunners[0]
+
+		// Record expressions in the same way as the plugin server
+		record := func(r *Runner) {
+			content, diags := r.TFConfig.Module.PartialContent(&hclext.BodySchema{
+				Blocks: []hclext.BlockSchema{
+					{
+						Type:       "output",
+						LabelNames: []string{"name"},
+						Body:       &hclext.BodySchema{Attributes: []hclext.AttributeSchema{{Name: "value"}}},
+					},
+				},
+			}, r.Ctx)
+			if diags.HasErrors() {
+				t.Fatal(diags)
+			}
+			for _, block := range content.Blocks {
+				expr := block.Body.Attributes["value"].Expr
+				val, diags := r.Ctx.EvaluateExpr(expr, cty.String)
+				if diags.HasErrors() {
+					t.Fatal(diags)
+				}
+				if reason := UnevaluatedReasonOf(val, cty.String); reason != "" {
+					r.RecordUnevaluated(expr, reason)
+				}
+			}
+		}
+		record(runner)
+		record(child)
+
+		opts := cmp.Options{
+			cmpopts.IgnoreFields(hcl.Range{}, "Start", "End"),
+			cmpopts.SortSlices(func(x, y string) bool { return x < y }),
+		}
+
+		got := runner.LookupUnevaluated()
+		want := []*UnevaluatedExpr{
+			{
+				Range:  hcl.Range{Filename: "main.tf"},
+				Reason: UnevaluatedUnknown,
+				Causes: []string{"local.prefix"},
+				Inputs: []string{"var.name", "var.region"},
+			},
+			{
+				Range:  hcl.Range{Filename: "main.tf"},
+				Reason: UnevaluatedUnknown,
+				Causes: []string{"aws_instance.main"},
+				Inputs: []string{},
+			},
+			{
+				Range:  hcl.Range{Filename: "main.tf"},
+				Reason: UnevaluatedSensitive,
+				Causes: []string{"var.password"},
+				Inputs: []string{},
+			},
+		}
+		if diff := cmp.Diff(want, got, opts); diff != "" {
+			t.Errorf("root: %s", diff)
+		}
+
+		// Expressions in called modules are located at the module call in the root module
+		want = []*UnevaluatedExpr{
+			{
+				Range:   hcl.Range{Filename: "main.tf"},
+				Reason:  UnevaluatedUnknown,
+				Callers: []hcl.Range{{Filename: filepath.Join("module", "main.tf")}},
+				Causes:  []string{"var.input"},
+				Inputs:  []string{"var.name", "var.region"},
+			},
+		}
+		got = child.LookupUnevaluated()
+		if diff := cmp.Diff(want, got, opts); diff != "" {
+			t.Errorf("child: %s", diff)
+		}
+		if got[0].Range.Start.Line != 19 {
+			t.Errorf("expected the module call at line 19, but got line %d", got[0].Range.Start.Line)
+		}
+
+		// The filter matches the module call, not the expression in the module
+		got = child.LookupUnevaluated("main.tf")
+		if diff := cmp.Diff(want, got, opts); diff != "" {
+			t.Errorf("child with filter: %s", diff)
+		}
+		got = child.LookupUnevaluated(filepath.Join("module", "main.tf"))
+		if len(got) != 0 {
+			t.Errorf("expected no expressions at module/main.tf, but got %d", len(got))
+		}
+		got = runner.LookupUnevaluated(filepath.Join("module", "main.tf"))
+		if len(got) != 0 {
+			t.Errorf("expected no expressions in module/main.tf, but got %d", len(got))
+		}
+	})
+}
+
+func Test_MergeUnevaluated(t *testing.T) {
+	rng := hcl.Range{Filename: "main.tf", Start: hcl.Pos{Line: 1, Column: 1}, End: hcl.Pos{Line: 1, Column: 5}}
+
+	got := MergeUnevaluated([]*UnevaluatedExpr{
+		{Range: rng, Reason: UnevaluatedUnknown, Causes: []string{"var.foo"}, Inputs: []string{"var.foo"}},
+		{Range: rng, Reason: UnevaluatedUnknown, Causes: []string{"var.foo", "var.bar"}, Inputs: []string{"var.bar"}},
+		{Range: rng, Reason: UnevaluatedNull, Causes: []string{"var.baz"}, Inputs: []string{"var.baz"}},
+	})
+	want := []*UnevaluatedExpr{
+		{Range: rng, Reason: UnevaluatedUnknown, Causes: []string{"var.foo", "var.bar"}, Inputs: []string{"var.foo", "var.bar"}},
+		{Range: rng, Reason: UnevaluatedNull, Causes: []string{"var.baz"}, Inputs: []string{"var.baz"}},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Error(diff)
+	}
+}